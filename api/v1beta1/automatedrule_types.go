@@ -0,0 +1,143 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutomatedRuleSpec defines the desired state of AutomatedRule
+type AutomatedRuleSpec struct {
+	// A description of the rule's purpose
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Description string `json:"description,omitempty"`
+	// An expression evaluated against each target that Cryostat discovers. Recordings are
+	// started on targets for which the expression is true, such as
+	// "target.alias == 'com.example.MainClass'".
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MatchExpression string `json:"matchExpression"`
+	// Name of the event template used to create recordings
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	EventTemplate string `json:"eventTemplate"`
+	// Type of the event template. Use "CUSTOM" for templates uploaded to Cryostat,
+	// such as those from an EventTemplate. If omitted, Cryostat looks up the template by name.
+	// +optional
+	// +kubebuilder:validation:Enum=TARGET;CUSTOM
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TemplateType *TemplateType `json:"templateType,omitempty"`
+	// How often to copy the recording into Cryostat's archives.
+	// If omitted, recordings are not archived.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ArchivalPeriod *metav1.Duration `json:"archivalPeriod,omitempty"`
+	// Number of archived copies to keep for each target. Older copies are deleted.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	PreservedArchives *int32 `json:"preservedArchives,omitempty"`
+	// Maximum age of data kept in the recording
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Maximum size of data kept in the recording
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+}
+
+// AutomatedRuleStatus defines the observed state of AutomatedRule
+type AutomatedRuleStatus struct {
+	// Name of the rule in Cryostat
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	RuleName string `json:"ruleName,omitempty"`
+	// The generation of the AutomatedRule most recently synced to Cryostat
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describing the rule's state in Cryostat
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// AutomatedRuleConditionType refers to a Condition type that may be used in status.conditions
+type AutomatedRuleConditionType string
+
+const (
+	// Whether the rule in Cryostat matches this AutomatedRule
+	ConditionTypeRuleSynced AutomatedRuleConditionType = "RuleSynced"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=automatedrules,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Rule Name",type=string,JSONPath=`.status.ruleName`
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="RuleSynced")].status`
+
+// AutomatedRule represents a Cryostat automated rule, which starts a recording on every target
+// matching its expression. The operator creates the rule in the Cryostat in the same namespace,
+// restores it if it is changed or removed outside of the operator, and deletes it when the
+// AutomatedRule is deleted.
+type AutomatedRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AutomatedRuleSpec   `json:"spec,omitempty"`
+	Status AutomatedRuleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// AutomatedRuleList contains a list of AutomatedRule
+type AutomatedRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutomatedRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AutomatedRule{}, &AutomatedRuleList{})
+}
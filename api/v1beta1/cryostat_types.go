@@ -37,9 +37,11 @@
 package v1beta1
 
 import (
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // CryostatSpec defines the desired state of Cryostat
@@ -60,6 +62,17 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable cert-manager Integration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	EnableCertManager *bool `json:"enableCertManager"`
+	// Provider of the certificates used to secure in-cluster communication between
+	// Cryostat components. "CertManager" uses cert-manager, subject to enableCertManager.
+	// "Operator" uses a certificate authority managed by the operator, and does not
+	// require cert-manager. Defaults to "CertManager".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS Provider"
+	TLSProvider *TLSProvider `json:"tlsProvider,omitempty"`
+	// Options to customize the certificates issued by cert-manager for Cryostat components
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CertificateOptions *CertificateConfiguration `json:"certificateOptions,omitempty"`
 	// Options to customize the storage for Flight Recordings and Templates
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
@@ -73,6 +86,10 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	NetworkOptions *NetworkConfigurationList `json:"networkOptions,omitempty"`
+	// Options to generate NetworkPolicies that restrict traffic to and from Cryostat components
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	NetworkPolicyOptions *NetworkPolicyConfiguration `json:"networkPolicyOptions,omitempty"`
 	// Options to configure Cryostat Automated Report Analysis
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
@@ -90,8 +107,140 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Resources ResourceConfigList `json:"resources,omitempty"`
+	// Options to control which Services the operator discovers as Flight Recorder targets
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TargetDiscoveryOptions *TargetDiscoveryOptions `json:"targetDiscoveryOptions,omitempty"`
+	// Options to configure scheduling for the Cryostat deployment
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SchedulingOptions *SchedulingConfiguration `json:"schedulingOptions,omitempty"`
+	// Options to configure the Security Contexts for the Cryostat deployment
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SecurityOptions *SecurityOptions `json:"securityOptions,omitempty"`
+	// Options to pass additional environment variables and JVM options
+	// to the containers of the Cryostat deployment
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	EnvironmentOptions *EnvironmentOptions `json:"environmentOptions,omitempty"`
+	// Override to apply to the pod template of the Cryostat deployment,
+	// for customizations not otherwise supported by the operator
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PodTemplateOverride *PodTemplateOverride `json:"podTemplateOverride,omitempty"`
+}
+
+// SecurityOptions contains Security Context customizations for the
+// main Cryostat deployment. When a Security Context is not specified,
+// the operator applies defaults that satisfy the restricted Pod Security Standard.
+type SecurityOptions struct {
+	// Security Context to apply to the Cryostat pod. Replaces the default
+	// Security Context, including the fsGroup chosen by the operator.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Security Context to apply to the Cryostat application container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CoreSecurityContext *corev1.SecurityContext `json:"coreSecurityContext,omitempty"`
+	// Security Context to apply to the JFR Data Source container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DataSourceSecurityContext *corev1.SecurityContext `json:"dataSourceSecurityContext,omitempty"`
+	// Security Context to apply to the Grafana container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	GrafanaSecurityContext *corev1.SecurityContext `json:"grafanaSecurityContext,omitempty"`
 }
 
+// ReportsSecurityOptions contains Security Context customizations for the
+// cryostat-reports deployment.
+type ReportsSecurityOptions struct {
+	// Security Context to apply to the cryostat-reports pod.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Security Context to apply to the cryostat-reports container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ReportsSecurityContext *corev1.SecurityContext `json:"reportsSecurityContext,omitempty"`
+}
+
+// EnvironmentOptions contains environment customizations for the
+// containers of the main Cryostat deployment.
+type EnvironmentOptions struct {
+	// Environment customizations for the Cryostat application container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CoreEnvironment *JavaContainerEnvironment `json:"coreEnvironment,omitempty"`
+	// Environment customizations for the JFR Data Source container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DataSourceEnvironment *JavaContainerEnvironment `json:"dataSourceEnvironment,omitempty"`
+	// Environment customizations for the Grafana container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	GrafanaEnvironment *ContainerEnvironment `json:"grafanaEnvironment,omitempty"`
+}
+
+// ContainerEnvironment contains additional environment variables
+// to pass to a container managed by the operator.
+type ContainerEnvironment struct {
+	// Additional environment variables to set in the container. Variables
+	// set by the operator take precedence, and any ignored variables are
+	// reported in the EnvironmentConflict condition.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+	// Additional sources of environment variables for the container,
+	// such as Secrets and Config Maps. Variables set by the operator
+	// take precedence over variables from these sources.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ExtraEnvFrom []corev1.EnvFromSource `json:"extraEnvFrom,omitempty"`
+}
+
+// JavaContainerEnvironment contains additional environment variables
+// and JVM options to pass to a Java container managed by the operator.
+type JavaContainerEnvironment struct {
+	// Additional options to pass to the JVM, using the JAVA_TOOL_OPTIONS
+	// environment variable. Options set on the command line of the
+	// container take precedence.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	JVMOptions           []string `json:"jvmOptions,omitempty"`
+	ContainerEnvironment `json:",inline"`
+}
+
+// PodTemplateOverride is a patch applied to the pod template of a
+// deployment after the operator has generated it. The patch must not
+// remove or change the containers, volumes, labels or service account
+// that the operator configured.
+type PodTemplateOverride struct {
+	// The type of the patch. "StrategicMerge" patches are partial pod templates,
+	// merged using the same rules as kubectl. "JSON" patches are lists of RFC 6902
+	// operations, with paths relative to the pod template. Defaults to "StrategicMerge".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Type *PodTemplatePatchType `json:"type,omitempty"`
+	// The patch to apply to the pod template, in JSON or YAML format.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Patch string `json:"patch"`
+}
+
+// PodTemplatePatchType selects how a PodTemplateOverride is applied
+// +kubebuilder:validation:Enum=StrategicMerge;JSON
+type PodTemplatePatchType string
+
+const (
+	// The patch is a strategic merge patch
+	PodTemplatePatchTypeStrategicMerge PodTemplatePatchType = "StrategicMerge"
+	// The patch is an RFC 6902 JSON patch
+	PodTemplatePatchTypeJSON PodTemplatePatchType = "JSON"
+)
+
 type ResourceConfigList struct {
 	// Resource requirements for the Cryostat application. If specifying a memory limit, at least 768MiB is recommended.
 	// +optional
@@ -107,6 +256,136 @@ type ResourceConfigList struct {
 	GrafanaResources corev1.ResourceRequirements `json:"grafanaResources,omitempty"`
 }
 
+// TLSProvider selects how certificates are issued for Cryostat components
+// +kubebuilder:validation:Enum=CertManager;Operator
+type TLSProvider string
+
+const (
+	// Certificates are issued by cert-manager
+	TLSProviderCertManager TLSProvider = "CertManager"
+	// Certificates are issued by a certificate authority managed by the operator
+	TLSProviderOperator TLSProvider = "Operator"
+)
+
+// CertificateConfiguration contains options to customize the certificates
+// issued by cert-manager for Cryostat components.
+type CertificateConfiguration struct {
+	// Reference to an existing cert-manager Issuer or ClusterIssuer that signs
+	// the certificates for Cryostat components. If omitted, the operator creates
+	// a self-signed CA for this Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+	// Requested lifetime of the certificates. Defaults to the cert-manager default of 90 days.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// How long before expiry the certificates should be renewed.
+	// Defaults to the cert-manager default of 30 days.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	// Options for the private keys of the certificates.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PrivateKey *CertificatePrivateKey `json:"privateKey,omitempty"`
+}
+
+// IssuerReference refers to a cert-manager Issuer or ClusterIssuer.
+type IssuerReference struct {
+	// Name of the issuer. An Issuer must be in the same namespace as the Cryostat.
+	Name string `json:"name"`
+	// Kind of the issuer. Defaults to "Issuer".
+	// +optional
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+	// API group of the issuer. Defaults to "cert-manager.io".
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CertificatePrivateKey contains options for the private key of a certificate.
+type CertificatePrivateKey struct {
+	// Algorithm of the private key. Defaults to "RSA".
+	// +optional
+	// +kubebuilder:validation:Enum=RSA;ECDSA
+	Algorithm string `json:"algorithm,omitempty"`
+	// Size of the private key in bits. For RSA, defaults to 2048 and may be 2048, 4096 or 8192.
+	// For ECDSA, defaults to 256 and may be 256, 384 or 521.
+	// +optional
+	Size int `json:"size,omitempty"`
+}
+
+// ReportsAutoscalingConfiguration contains options for the HorizontalPodAutoscaler
+// of the reports deployment. If neither a CPU target nor metrics are specified,
+// replicas are scaled to target an average CPU utilization of 80%.
+type ReportsAutoscalingConfiguration struct {
+	// The minimum number of report sidecar replicas. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// The maximum number of report sidecar replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MaxReplicas int32 `json:"maxReplicas"`
+	// The target average CPU utilization of the report sidecar replicas,
+	// as a percentage of their requested CPU.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// Additional metrics used to scale the report sidecar replicas,
+	// such as custom or external metrics.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Metrics []autoscalingv2beta2.MetricSpec `json:"metrics,omitempty"`
+}
+
+// PodDisruptionBudgetConfiguration contains options for a PodDisruptionBudget.
+// At most one of minAvailable and maxUnavailable may be specified.
+// If neither are specified, minAvailable defaults to 1.
+type PodDisruptionBudgetConfiguration struct {
+	// The number or percentage of pods that must remain available during an eviction.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// The number or percentage of pods that may be unavailable during an eviction.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// SchedulingConfiguration contains multiple choices to control scheduling of
+// the pods created by the operator.
+type SchedulingConfiguration struct {
+	// Label selector used to schedule a Cryostat pod to a node.
+	// See: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#nodeselector
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity rules for scheduling Cryostat pods.
+	// See: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#affinity-and-anti-affinity
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations to allow scheduling of Cryostat pods to tainted nodes.
+	// See: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Constraints on how Cryostat pods are spread across topology domains.
+	// See: https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Name of the PriorityClass to assign to Cryostat pods.
+	// See: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
 // CryostatStatus defines the observed state of Cryostat
 type CryostatStatus struct {
 	// Conditions of the components managed by the Cryostat Operator
@@ -116,6 +395,65 @@ type CryostatStatus struct {
 	// Address of the deployed Cryostat web application
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
 	ApplicationURL string `json:"applicationUrl"`
+	// Address of the deployed Grafana dashboard, if any
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:org.w3:link"}
+	GrafanaURL string `json:"grafanaUrl,omitempty"`
+	// Address of the reports generator service within the cluster, if any
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ReportsURL string `json:"reportsUrl,omitempty"`
+	// The generation of this Cryostat most recently applied by the operator.
+	// When equal to metadata.generation and the Ready condition is true,
+	// the current spec has been fully applied.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Version of the deployed Cryostat application, as given by the tag of its image
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Cryostat Version"
+	Version string `json:"version,omitempty"`
+	// Container images deployed for the Cryostat components
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Images *ImageStatus `json:"images,omitempty"`
+	// Persistent storage mounted by Cryostat, if any
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Storage *StorageStatus `json:"storage,omitempty"`
+}
+
+// StorageStatus describes the persistent storage mounted by Cryostat
+type StorageStatus struct {
+	// Name of the Persistent Volume Claim mounted by Cryostat. This changes
+	// when the operator migrates Cryostat's data to a new Persistent Volume Claim.
+	ClaimName string `json:"claimName"`
+	// Capacity of the Persistent Volume Claim in bytes.
+	// +optional
+	CapacityBytes *int64 `json:"capacityBytes,omitempty"`
+	// Bytes of the Persistent Volume Claim used by archived recordings.
+	// +optional
+	UsedBytes *int64 `json:"usedBytes,omitempty"`
+	// Bytes of the Persistent Volume Claim still available for archived recordings.
+	// +optional
+	AvailableBytes *int64 `json:"availableBytes,omitempty"`
+}
+
+// ImageStatus lists the container images deployed for each Cryostat component.
+// Components that are not deployed are omitted.
+type ImageStatus struct {
+	// Image of the Cryostat web application
+	// +optional
+	Core string `json:"core,omitempty"`
+	// Image of the JFR data source
+	// +optional
+	Datasource string `json:"datasource,omitempty"`
+	// Image of the Grafana dashboard
+	// +optional
+	Grafana string `json:"grafana,omitempty"`
+	// Image of the reports generator
+	// +optional
+	Reports string `json:"reports,omitempty"`
 }
 
 // CryostatConditionType refers to a Condition type that may be used in status.conditions
@@ -136,8 +474,73 @@ const (
 	ConditionTypeReportsDeploymentReplicaFailure CryostatConditionType = "ReportsDeploymentReplicaFailure"
 	// If enabled, whether TLS setup is complete for the Cryostat components
 	ConditionTypeTLSSetupComplete CryostatConditionType = "TLSSetupComplete"
+	// Whether TLS setup is complete and all deployed Cryostat components are available
+	ConditionTypeReady CryostatConditionType = "Ready"
+	// If the requested storage has grown, whether the Persistent Volume Claim has been expanded to that size
+	ConditionTypeStorageResized CryostatConditionType = "StorageResized"
+	// If the Persistent Volume Claim spec has changed in a way that requires a new volume,
+	// whether Cryostat's data has been migrated to it
+	ConditionTypeStorageMigrated CryostatConditionType = "StorageMigrated"
+	// Whether archived recordings use more of the Persistent Volume Claim than the configured thresholds
+	ConditionTypeStorageNearlyFull CryostatConditionType = "StorageNearlyFull"
+	// Whether any extra environment variables were ignored because they
+	// conflict with variables set by the operator
+	ConditionTypeEnvironmentConflict CryostatConditionType = "EnvironmentConflict"
+	// If an override is specified, whether it was applied to the pod template of the main Cryostat deployment
+	ConditionTypeMainDeploymentOverrideApplied CryostatConditionType = "MainDeploymentOverrideApplied"
+	// If reports are enabled and an override is specified, whether it was applied to the pod template
+	// of the reports deployment
+	ConditionTypeReportsDeploymentOverrideApplied CryostatConditionType = "ReportsDeploymentOverrideApplied"
+	// If a CryostatRestore targets this Cryostat before its first deployment, whether
+	// the restore has finished and the deployment may be created
+	ConditionTypeRestoreComplete CryostatConditionType = "RestoreComplete"
 )
 
+// DiscoveryExcludeAnnotation is an annotation that may be added to a Service
+// with a value of "true" to prevent the operator from creating FlightRecorders
+// for the Pods backing it.
+const DiscoveryExcludeAnnotation = "operator.cryostat.io/discovery-exclude"
+
+// AgentPortAnnotation is an annotation that may be added to a Service to
+// identify, by name or number, the port on which the Cryostat agent listens.
+const AgentPortAnnotation = "operator.cryostat.io/agent-port"
+
+// AgentSchemeAnnotation is an annotation that may be added to a Service to
+// specify whether its Cryostat agent is served over "http" or "https".
+// Defaults to "http".
+const AgentSchemeAnnotation = "operator.cryostat.io/agent-scheme"
+
+// TargetDiscoveryOptions provides customization for how the operator
+// discovers JVM targets and creates FlightRecorders for them.
+type TargetDiscoveryOptions struct {
+	// Only Services in namespaces matching this label selector will be
+	// considered for target discovery. Defaults to all namespaces.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Only Services matching this label selector will be considered for
+	// target discovery. Defaults to all Services.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ServiceSelector *metav1.LabelSelector `json:"serviceSelector,omitempty"`
+	// Names of Service ports that expose remote JMX. Ports matching these
+	// names take precedence over those matched by number.
+	// Defaults to ["jfr-jmx"].
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	JMXPortNames []string `json:"jmxPortNames,omitempty"`
+	// Service port numbers that expose remote JMX, used when no port
+	// matches by name. Defaults to [9091].
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	JMXPortNumbers []int32 `json:"jmxPortNumbers,omitempty"`
+	// Names of Service ports that expose the HTTP endpoint of the Cryostat
+	// agent. Agent ports take precedence over JMX ports. Defaults to ["cryostat-agent"].
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AgentPortNames []string `json:"agentPortNames,omitempty"`
+}
+
 // StorageConfiguration provides customization to the storage created by
 // the operator to hold Flight Recordings and Recording Templates. If no
 // configurations are specified, a PVC will be created by default.
@@ -153,6 +556,38 @@ type StorageConfiguration struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	EmptyDir *EmptyDirConfig `json:"emptyDir,omitempty"`
+
+	// Options to monitor how much of the Persistent Volume Claim
+	// is used by archived recordings.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Monitoring *StorageMonitoringConfiguration `json:"monitoring,omitempty"`
+}
+
+// StorageMonitoringConfiguration contains options to monitor how much of Cryostat's
+// Persistent Volume Claim is used by archived recordings.
+type StorageMonitoringConfiguration struct {
+	// Percentage of the Persistent Volume Claim's capacity used by archived recordings
+	// above which the StorageNearlyFull condition is set and a Warning Event is emitted.
+	// Defaults to 80.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	WarningThresholdPercent *int32 `json:"warningThresholdPercent,omitempty"`
+	// Percentage of the Persistent Volume Claim's capacity used by archived recordings
+	// above which the StorageNearlyFull condition reports critical usage and another
+	// Warning Event is emitted. Defaults to 95.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	CriticalThresholdPercent *int32 `json:"criticalThresholdPercent,omitempty"`
+	// How often the operator measures the storage used by archived recordings.
+	// Defaults to 5 minutes.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // ReportConfiguration is used to determine how many replicas of cryostat-reports
@@ -164,9 +599,20 @@ type StorageConfiguration struct {
 type ReportConfiguration struct {
 	// The number of report sidecar replica containers to deploy.
 	// Each replica can service one report generation request at a time.
+	// Ignored when autoscaling is configured.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
 	Replicas int32 `json:"replicas,omitempty"`
+	// Options to scale the number of report sidecar replicas automatically
+	// using a HorizontalPodAutoscaler. When specified, the operator no longer
+	// sets the number of replicas of the reports deployment after creating it.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Autoscaling *ReportsAutoscalingConfiguration `json:"autoscaling,omitempty"`
+	// Options to create a PodDisruptionBudget for the report sidecar replicas.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PodDisruptionBudget *PodDisruptionBudgetConfiguration `json:"podDisruptionBudget,omitempty"`
 	// The resources allocated to each sidecar replica.
 	// A replica with more resources can handle larger input recordings and will process them faster.
 	// +optional
@@ -178,6 +624,24 @@ type ReportConfiguration struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
 	SubProcessMaxHeapSize int32 `json:"subProcessMaxHeapSize,omitempty"`
+	// Options to configure scheduling for the reports deployment
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SchedulingOptions *SchedulingConfiguration `json:"schedulingOptions,omitempty"`
+	// Options to configure the Security Contexts for the reports deployment
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SecurityOptions *ReportsSecurityOptions `json:"securityOptions,omitempty"`
+	// Options to pass additional environment variables and JVM options
+	// to the cryostat-reports container
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	EnvironmentOptions *JavaContainerEnvironment `json:"environmentOptions,omitempty"`
+	// Override to apply to the pod template of the reports deployment,
+	// for customizations not otherwise supported by the operator
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PodTemplateOverride *PodTemplateOverride `json:"podTemplateOverride,omitempty"`
 }
 
 // ServiceConfig provides customization for a service created
@@ -253,17 +717,70 @@ type NetworkConfiguration struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	IngressSpec *netv1.IngressSpec `json:"ingressSpec,omitempty"`
-	// Annotations to add to the ingress during its creation.
+	// Configuration for the route that exposes the service on OpenShift.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RouteConfig *RouteConfiguration `json:"routeConfig,omitempty"`
+	// Annotations to add to the ingress, or route on OpenShift, during its creation.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Annotations map[string]string `json:"annotations,omitempty"`
-	// Labels to add to the ingress during its creation.
+	// Labels to add to the ingress, or route on OpenShift, during its creation.
 	// The label with key "app" is reserved for use by the operator.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Labels map[string]string `json:"labels,omitempty"`
 }
 
+// NetworkPolicyConfiguration provides customization for the NetworkPolicies
+// that the operator generates for Cryostat components.
+type NetworkPolicyConfiguration struct {
+	// Generate NetworkPolicies for Cryostat components. Defaults to false.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+	// Peers selecting the router or ingress controller pods, which may connect
+	// to the Cryostat web server and Grafana. Defaults to namespaces labelled with
+	// "network.openshift.io/policy-group: ingress" on OpenShift, and the
+	// "ingress-nginx" namespace on Kubernetes.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IngressControllerPeers []netv1.NetworkPolicyPeer `json:"ingressControllerPeers,omitempty"`
+	// Additional peers that may connect to the Cryostat web server and Grafana.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AdditionalIngressPeers []netv1.NetworkPolicyPeer `json:"additionalIngressPeers,omitempty"`
+	// Additional peers that Cryostat may connect to, on any port.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AdditionalEgressPeers []netv1.NetworkPolicyPeer `json:"additionalEgressPeers,omitempty"`
+}
+
+// RouteConfiguration contains options for an OpenShift route created by the operator.
+type RouteConfiguration struct {
+	// Host for the route. If not specified, a host is generated by OpenShift.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Host string `json:"host,omitempty"`
+	// Path that the router watches for, to route traffic to the service.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Path string `json:"path,omitempty"`
+	// Wildcard policy for the route. Subdomain allows the route to admit
+	// all hosts in the subdomain of its host. Defaults to None.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Subdomain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	WildcardPolicy string `json:"wildcardPolicy,omitempty"`
+	// Name of a secret in the same namespace containing a certificate (tls.crt)
+	// and private key (tls.key) that the router presents for this route,
+	// and optionally a CA certificate (ca.crt) for the certificate chain.
+	// If not specified, the router's default certificate is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	CertificateSecretName string `json:"certificateSecretName,omitempty"`
+}
+
 // NetworkConfigurationList holds all three NetworkConfiguration objects that specify
 // the Ingress configurations for the services created by the operator for
 // the main Cryostat deployment
@@ -285,6 +802,66 @@ type NetworkConfigurationList struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	GrafanaConfig *NetworkConfiguration `json:"grafanaConfig,omitempty"`
+	// Configuration for HTTPRoutes that expose the cryostat and cryostat-grafana
+	// services through a Gateway, using the Kubernetes Gateway API.
+	// When specified, HTTPRoutes are created instead of ingresses.
+	// Only used when running on Kubernetes.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	GatewayConfig *GatewayConfiguration `json:"gatewayConfig,omitempty"`
+}
+
+// GatewayConfiguration describes how Cryostat's services are exposed using
+// Gateway API HTTPRoutes.
+type GatewayConfiguration struct {
+	// Reference to the Gateway that the HTTPRoutes attach to.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	GatewayRef GatewayReference `json:"gatewayRef"`
+	// Configuration for the HTTPRoute that exposes the cryostat service
+	// (which serves the cryostat web-client).
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CoreRoute *HTTPRouteConfiguration `json:"coreRoute,omitempty"`
+	// Configuration for the HTTPRoute that exposes the cryostat-grafana service
+	// (which serves the grafana dashboard).
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	GrafanaRoute *HTTPRouteConfiguration `json:"grafanaRoute,omitempty"`
+}
+
+// GatewayReference refers to a Gateway, and optionally one of its listeners.
+type GatewayReference struct {
+	// Name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+	// Namespace of the Gateway. Defaults to the namespace of the Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the Gateway listener to attach to. If not specified,
+	// the HTTPRoutes attach to all compatible listeners of the Gateway.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// HTTPRouteConfiguration contains options for an HTTPRoute created by the operator.
+type HTTPRouteConfiguration struct {
+	// Hostnames that the HTTPRoute matches. The first hostname is used
+	// for the URL of the service. If not specified, the hostname of the
+	// Gateway listener or the Gateway's address is used instead.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Annotations to add to the HTTPRoute during its creation.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels to add to the HTTPRoute during its creation.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // PersistentVolumeClaimConfig holds all customization options to
@@ -301,8 +878,10 @@ type PersistentVolumeClaimConfig struct {
 	// Spec for a Persistent Volume Claim, whose options will override the
 	// defaults used by the operator. Unless overriden, the PVC will be
 	// created with the default Storage Class and 500MiB of storage.
-	// Once the operator has created the PVC, changes to this field have
-	// no effect.
+	// Once the operator has created the PVC, a larger storage request expands
+	// the PVC in place if its Storage Class allows volume expansion. Other changes,
+	// such as a new Storage Class, access modes or a smaller storage request, are
+	// applied by migrating Cryostat's data to a new PVC.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Spec *corev1.PersistentVolumeClaimSpec `json:"spec,omitempty"`
@@ -0,0 +1,220 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CryostatBackupSpec defines the desired state of CryostatBackup
+type CryostatBackupSpec struct {
+	// Name of the Cryostat, in the same namespace, whose persistent storage should be backed up.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CryostatName string `json:"cryostatName"`
+	// Directories of Cryostat's persistent volume to include in the backup.
+	// Defaults to all of them. Ignored when backing up to a VolumeSnapshot,
+	// which always captures the entire volume.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Paths []StoragePath `json:"paths,omitempty"`
+	// Where to store the backup. Exactly one destination must be specified.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Destination BackupDestination `json:"destination"`
+}
+
+// StoragePath is a directory within Cryostat's persistent volume
+// +kubebuilder:validation:Enum=config;flightrecordings;templates;clientlib;probes;truststore
+type StoragePath string
+
+const (
+	// Cryostat's configuration, including stored credentials and automated rules
+	StoragePathConfig StoragePath = "config"
+	// Archived recordings
+	StoragePathFlightRecordings StoragePath = "flightrecordings"
+	// Custom event templates
+	StoragePathTemplates StoragePath = "templates"
+	// Client library files
+	StoragePathClientLib StoragePath = "clientlib"
+	// Probe templates
+	StoragePathProbes StoragePath = "probes"
+	// Certificates added to Cryostat's trust store
+	StoragePathTrustStore StoragePath = "truststore"
+)
+
+// AllStoragePaths lists every directory within Cryostat's persistent volume
+var AllStoragePaths = []StoragePath{
+	StoragePathConfig,
+	StoragePathFlightRecordings,
+	StoragePathTemplates,
+	StoragePathClientLib,
+	StoragePathProbes,
+	StoragePathTrustStore,
+}
+
+// BackupDestination describes where a backup is stored
+type BackupDestination struct {
+	// Write a compressed archive of the backup to a persistent volume claim.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PVC *BackupPVCDestination `json:"pvc,omitempty"`
+	// Take a VolumeSnapshot of Cryostat's persistent volume. Requires the
+	// snapshot.storage.k8s.io API to be available in the cluster.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	VolumeSnapshot *BackupVolumeSnapshotDestination `json:"volumeSnapshot,omitempty"`
+}
+
+// BackupPVCDestination describes a persistent volume claim to store a backup archive in
+type BackupPVCDestination struct {
+	// Name of an existing persistent volume claim, in the same namespace, to write the archive to.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ClaimName string `json:"claimName"`
+	// Directory within the volume to write the archive to. Defaults to the root of the volume.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// BackupVolumeSnapshotDestination describes a VolumeSnapshot to store a backup in
+type BackupVolumeSnapshotDestination struct {
+	// Name of the VolumeSnapshotClass to use. Defaults to the cluster's default class.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// CryostatBackupStatus defines the observed state of CryostatBackup
+type CryostatBackupStatus struct {
+	// Current phase of the backup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Phase StorageOperationPhase `json:"phase,omitempty"`
+	// Directories of Cryostat's persistent volume contained in the backup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Paths []StoragePath `json:"paths,omitempty"`
+	// Location of the backup archive, once the backup has succeeded.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Archive *BackupArchive `json:"archive,omitempty"`
+	// Name of the VolumeSnapshot containing the backup, once the backup has succeeded.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+	// Time at which the backup started.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time at which the backup succeeded or failed.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Conditions of the backup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// BackupArchive is the location of a backup archive within a persistent volume claim
+type BackupArchive struct {
+	// Name of the persistent volume claim containing the archive.
+	ClaimName string `json:"claimName"`
+	// Path to the archive within the volume.
+	Path string `json:"path"`
+}
+
+// StorageOperationPhase is the lifecycle phase of a backup or restore
+type StorageOperationPhase string
+
+const (
+	// The operation is waiting for its prerequisites
+	StorageOperationPending StorageOperationPhase = "Pending"
+	// The operation is in progress
+	StorageOperationRunning StorageOperationPhase = "Running"
+	// The operation completed successfully
+	StorageOperationSucceeded StorageOperationPhase = "Succeeded"
+	// The operation failed and will not be retried
+	StorageOperationFailed StorageOperationPhase = "Failed"
+)
+
+// StorageOperationConditionType refers to a Condition type that may be used in the
+// status.conditions of a CryostatBackup or CryostatRestore
+type StorageOperationConditionType string
+
+const (
+	// Whether the backup or restore has finished successfully
+	ConditionTypeStorageOperationComplete StorageOperationConditionType = "Complete"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=cryostatbackups,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostatName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+
+// CryostatBackup represents a one-time backup of a Cryostat's persistent storage,
+// containing its archived recordings, templates, probes, credentials and configuration.
+// The operator copies the selected directories into a compressed archive on another
+// persistent volume claim using a Job, or takes a VolumeSnapshot of the volume.
+// A completed backup may be restored into a new Cryostat using a CryostatRestore.
+//+operator-sdk:csv:customresourcedefinitions:resources={{Job,v1},{PersistentVolumeClaim,v1}}
+type CryostatBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CryostatBackupSpec   `json:"spec,omitempty"`
+	Status CryostatBackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// CryostatBackupList contains a list of CryostatBackup
+type CryostatBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CryostatBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CryostatBackup{}, &CryostatBackupList{})
+}
@@ -0,0 +1,114 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CryostatRestoreSpec defines the desired state of CryostatRestore
+type CryostatRestoreSpec struct {
+	// Name of the Cryostat, in the same namespace, whose persistent storage should be
+	// restored. The Cryostat must not have been deployed yet. Its deployment is held
+	// back until the restore completes.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CryostatName string `json:"cryostatName"`
+	// Name of the CryostatBackup, in the same namespace, to restore from.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	BackupName string `json:"backupName"`
+	// Directories of the backup to restore. Defaults to all directories in the backup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Paths []StoragePath `json:"paths,omitempty"`
+}
+
+// CryostatRestoreStatus defines the observed state of CryostatRestore
+type CryostatRestoreStatus struct {
+	// Current phase of the restore.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Phase StorageOperationPhase `json:"phase,omitempty"`
+	// Time at which the restore started.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time at which the restore succeeded or failed.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Conditions of the restore.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=cryostatrestores,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostatName`
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// CryostatRestore repopulates the persistent storage of a new Cryostat from a
+// CryostatBackup. The operator runs a Job that copies the backup into Cryostat's
+// persistent volume before the Cryostat deployment is first created.
+//+operator-sdk:csv:customresourcedefinitions:resources={{Job,v1},{PersistentVolumeClaim,v1}}
+type CryostatRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CryostatRestoreSpec   `json:"spec,omitempty"`
+	Status CryostatRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// CryostatRestoreList contains a list of CryostatRestore
+type CryostatRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CryostatRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CryostatRestore{}, &CryostatRestoreList{})
+}
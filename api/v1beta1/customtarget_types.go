@@ -0,0 +1,132 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CustomTargetSpec defines the desired state of CustomTarget
+type CustomTargetSpec struct {
+	// JMX service URL used to connect to the JVM, such as
+	// "service:jmx:rmi:///jndi/rmi://my-host:9091/jmxrmi".
+	// Either this or both host and port must be specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ConnectURL *string `json:"connectUrl,omitempty"`
+	// Hostname or IP address of the JVM, used with port when connectUrl is not specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Host *string `json:"host,omitempty"`
+	// Remote JMX port of the JVM, used with host when connectUrl is not specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+	// Human-readable name used to identify this target in Cryostat.
+	// Defaults to the name of the CustomTarget.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Alias string `json:"alias,omitempty"`
+	// If JMX authentication is enabled for this target's JVM, specify the credentials in a secret
+	// and reference it here
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	JMXCredentials *JMXAuthSecret `json:"jmxCredentials,omitempty"`
+}
+
+// CustomTargetStatus defines the observed state of CustomTarget
+type CustomTargetStatus struct {
+	// Connection URL under which this target is registered with Cryostat
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	ConnectURL string `json:"connectUrl,omitempty"`
+	// Alias under which this target is registered with Cryostat
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Alias string `json:"alias,omitempty"`
+	// Conditions of the CustomTarget's registration with Cryostat
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CustomTargetConditionType refers to a Condition type that may be used in status.conditions
+type CustomTargetConditionType string
+
+const (
+	// Whether the target has been registered with Cryostat
+	ConditionTypeTargetRegistered CustomTargetConditionType = "TargetRegistered"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=customtargets,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Connect URL",type=string,JSONPath=`.status.connectUrl`
+// +kubebuilder:printcolumn:name="Alias",type=string,JSONPath=`.status.alias`
+
+// CustomTarget represents a JVM outside of the cluster, or otherwise not discoverable
+// by the operator, that Cryostat should connect to. The operator registers the target
+// with Cryostat and creates a FlightRecorder of the same name for it, so that
+// Recordings may be created for the target just like for a Pod.
+//+operator-sdk:csv:customresourcedefinitions:resources={{FlightRecorder,v1beta1},{Secret,v1}}
+type CustomTarget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CustomTargetSpec   `json:"spec,omitempty"`
+	Status CustomTargetStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// CustomTargetList contains a list of CustomTarget
+type CustomTargetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CustomTarget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CustomTarget{}, &CustomTargetList{})
+}
@@ -0,0 +1,123 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventTemplateSpec defines the desired state of EventTemplate
+type EventTemplateSpec struct {
+	// Contents of a JDK Flight Recorder event template, in the XML format of a .jfc file.
+	// Either this or configMapRef must be specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Template *string `json:"template,omitempty"`
+	// Reference to a .jfc file within a ConfigMap in the same namespace.
+	// Either this or template must be specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ConfigMapRef *TemplateConfigMap `json:"configMapRef,omitempty"`
+}
+
+// EventTemplateStatus defines the observed state of EventTemplate
+type EventTemplateStatus struct {
+	// Name of the template in Cryostat, taken from the label of the template's configuration element.
+	// Recordings may use this template with the event option "template=<templateName>,type=CUSTOM".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	TemplateName string `json:"templateName,omitempty"`
+	// Description of the template
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Description string `json:"description,omitempty"`
+	// Provider of the template
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Provider string `json:"provider,omitempty"`
+	// SHA-256 checksum of the template uploaded to Cryostat
+	// +optional
+	ContentHash string `json:"contentHash,omitempty"`
+	// Conditions of the template's availability in Cryostat
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// EventTemplateConditionType refers to a Condition type that may be used in status.conditions
+type EventTemplateConditionType string
+
+const (
+	// Whether the template has been uploaded to Cryostat and may be used by recordings
+	ConditionTypeTemplateAvailable EventTemplateConditionType = "TemplateAvailable"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=eventtemplates,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Template Name",type=string,JSONPath=`.status.templateName`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="TemplateAvailable")].status`
+
+// EventTemplate represents a custom JDK Flight Recorder event template. The operator validates
+// the template and uploads it to the Cryostat in the same namespace, without restarting Cryostat.
+// The template is removed from Cryostat when the EventTemplate is deleted.
+//+operator-sdk:csv:customresourcedefinitions:resources={{ConfigMap,v1}}
+type EventTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventTemplateSpec   `json:"spec,omitempty"`
+	Status EventTemplateStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// EventTemplateList contains a list of EventTemplate
+type EventTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EventTemplate{}, &EventTemplateList{})
+}
@@ -38,6 +38,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,6 +52,17 @@ type FlightRecorderSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	JMXCredentials *JMXAuthSecret `json:"jmxCredentials,omitempty"`
+	// If this FlightRecorder's JVM uses TLS for its JMX connections, specify the certificates
+	// Cryostat should use when connecting to it
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TLS *JMXTLSConfig `json:"tls,omitempty"`
+	// Name of a ProbeTemplate in the local namespace whose probes should be applied to this
+	// FlightRecorder's JVM. The JVM must have the JMC agent loaded. Removing this field removes
+	// the probes from the JVM.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:text"}
+	ProbeTemplate *string `json:"probeTemplate,omitempty"`
 }
 
 // FlightRecorderStatus defines the observed state of FlightRecorder
@@ -66,10 +78,110 @@ type FlightRecorderStatus struct {
 	// Reference to the pod/service that this object controls JFR for
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Target *corev1.ObjectReference `json:"target"`
-	// JMX port for target JVM
+	// JMX or agent port for target JVM
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
 	// +kubebuilder:validation:Minimum=0
 	Port int32 `json:"port"`
+	// Protocol used by Cryostat to communicate with the target JVM on the given port.
+	// Defaults to JMX.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Protocol TargetProtocol `json:"protocol,omitempty"`
+	// Runtime details of the target JVM, as of the most recent update
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	JVMInfo *JVMInfo `json:"jvmInfo,omitempty"`
+	// Probes applied to the target JVM using the JMC agent
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Probes *ProbeStatus `json:"probes,omitempty"`
+	// Conditions describing the connection between Cryostat and the target JVM
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// TargetProtocol is the protocol used to communicate with a target JVM
+// +kubebuilder:validation:Enum=JMX;HTTP;HTTPS
+type TargetProtocol string
+
+const (
+	// Remote JMX
+	TargetProtocolJMX TargetProtocol = "JMX"
+	// Cryostat agent over HTTP
+	TargetProtocolHTTP TargetProtocol = "HTTP"
+	// Cryostat agent over HTTPS
+	TargetProtocolHTTPS TargetProtocol = "HTTPS"
+)
+
+// FlightRecorderConditionType refers to a Condition type that may be used in status.conditions
+type FlightRecorderConditionType string
+
+const (
+	// If TLS is configured, whether Cryostat successfully completed a TLS handshake
+	// with the target JVM's JMX endpoint
+	ConditionTypeTLSHandshakeSucceeded FlightRecorderConditionType = "TLSHandshakeSucceeded"
+	// If a probe template is specified, whether its probes are active in the target JVM
+	ConditionTypeProbesApplied FlightRecorderConditionType = "ProbesApplied"
+)
+
+// ProbeStatus describes the probes applied to a target JVM
+type ProbeStatus struct {
+	// Name of the ProbeTemplate that was applied
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	ProbeTemplate string `json:"probeTemplate"`
+	// SHA-256 checksum of the ProbeTemplate contents that were applied
+	// +optional
+	ContentHash string `json:"contentHash,omitempty"`
+	// Probes reported as active by the target JVM
+	// +optional
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Active []ProbeInfo `json:"active,omitempty"`
+}
+
+// ProbeInfo contains metadata for a probe injected into a target JVM
+type ProbeInfo struct {
+	// The ID of the JFR event type emitted by this probe
+	ID string `json:"id"`
+	// Human-readable name for the event emitted by this probe
+	Name string `json:"name"`
+	// A description of the event emitted by this probe
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Fully-qualified name of the instrumented class
+	Class string `json:"class"`
+	// Name of the instrumented method
+	Method string `json:"method"`
+	// Descriptor of the instrumented method
+	// +optional
+	MethodDescriptor string `json:"methodDescriptor,omitempty"`
+}
+
+// JVMInfo contains runtime details of a target JVM
+type JVMInfo struct {
+	// Version of the Java virtual machine implementation
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Version string `json:"version"`
+	// Vendor of the Java virtual machine implementation
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:text"}
+	Vendor string `json:"vendor"`
+	// Time when the JVM started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// How long the JVM had been running when this status was last updated
+	// +optional
+	Uptime *metav1.Duration `json:"uptime,omitempty"`
+	// Initial size of the heap
+	// +optional
+	HeapInitial *resource.Quantity `json:"heapInitial,omitempty"`
+	// Maximum size of the heap, if defined
+	// +optional
+	HeapMax *resource.Quantity `json:"heapMax,omitempty"`
+	// Arguments passed to the JVM, excluding those passed to the main method
+	// +optional
+	// +listType=atomic
+	InputArguments []string `json:"inputArguments,omitempty"`
 }
 
 // RecordingLabel is the label name to be used with FlightRecorderSpec.RecordingSelector
@@ -145,6 +257,21 @@ type JMXAuthSecret struct {
 	PasswordKey *string `json:"passwordKey,omitempty"`
 }
 
+// JMXTLSConfig holds the certificates used to establish a TLS connection
+// with the FlightRecorder's JVM
+type JMXTLSConfig struct {
+	// Secret containing the certificate of the CA that signed the JVM's JMX server certificate.
+	// The operator adds this certificate to Cryostat's truststore.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CACert CertificateSecret `json:"caCert"`
+	// Name of a secret of type kubernetes.io/tls in the local namespace, containing a client
+	// certificate and private key for Cryostat to present to the JVM when it requires
+	// client authentication
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	ClientCertSecret *string `json:"clientCertSecret,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // +k8s:openapi-gen=true
@@ -152,6 +279,8 @@ type JMXAuthSecret struct {
 // +kubebuilder:storageversion
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="JVM Version",type=string,JSONPath=`.status.jvmInfo.version`
+// +kubebuilder:printcolumn:name="JVM Vendor",type=string,JSONPath=`.status.jvmInfo.vendor`
 
 // FlightRecorder represents a target Pod that is capable of creating JDK Flight Recordings
 // using Cryostat. The Cryostat operator creates FlightRecorder objects when it finds
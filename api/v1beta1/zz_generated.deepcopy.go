@@ -41,60 +41,16 @@
 package v1beta1
 
 import (
+	"k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CertificateSecret) DeepCopyInto(out *CertificateSecret) {
-	*out = *in
-	if in.CertificateKey != nil {
-		in, out := &in.CertificateKey, &out.CertificateKey
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecret.
-func (in *CertificateSecret) DeepCopy() *CertificateSecret {
-	if in == nil {
-		return nil
-	}
-	out := new(CertificateSecret)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CoreServiceConfig) DeepCopyInto(out *CoreServiceConfig) {
-	*out = *in
-	if in.HTTPPort != nil {
-		in, out := &in.HTTPPort, &out.HTTPPort
-		*out = new(int32)
-		**out = **in
-	}
-	if in.JMXPort != nil {
-		in, out := &in.JMXPort, &out.JMXPort
-		*out = new(int32)
-		**out = **in
-	}
-	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreServiceConfig.
-func (in *CoreServiceConfig) DeepCopy() *CoreServiceConfig {
-	if in == nil {
-		return nil
-	}
-	out := new(CoreServiceConfig)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Cryostat) DeepCopyInto(out *Cryostat) {
+func (in *AutomatedRule) DeepCopyInto(out *AutomatedRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -102,18 +58,18 @@ func (in *Cryostat) DeepCopyInto(out *Cryostat) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cryostat.
-func (in *Cryostat) DeepCopy() *Cryostat {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRule.
+func (in *AutomatedRule) DeepCopy() *AutomatedRule {
 	if in == nil {
 		return nil
 	}
-	out := new(Cryostat)
+	out := new(AutomatedRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Cryostat) DeepCopyObject() runtime.Object {
+func (in *AutomatedRule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -121,31 +77,31 @@ func (in *Cryostat) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CryostatList) DeepCopyInto(out *CryostatList) {
+func (in *AutomatedRuleList) DeepCopyInto(out *AutomatedRuleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Cryostat, len(*in))
+		*out = make([]AutomatedRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatList.
-func (in *CryostatList) DeepCopy() *CryostatList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleList.
+func (in *AutomatedRuleList) DeepCopy() *AutomatedRuleList {
 	if in == nil {
 		return nil
 	}
-	out := new(CryostatList)
+	out := new(AutomatedRuleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CryostatList) DeepCopyObject() runtime.Object {
+func (in *AutomatedRuleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -153,65 +109,47 @@ func (in *CryostatList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
+func (in *AutomatedRuleSpec) DeepCopyInto(out *AutomatedRuleSpec) {
 	*out = *in
-	if in.TrustedCertSecrets != nil {
-		in, out := &in.TrustedCertSecrets, &out.TrustedCertSecrets
-		*out = make([]CertificateSecret, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.EventTemplates != nil {
-		in, out := &in.EventTemplates, &out.EventTemplates
-		*out = make([]TemplateConfigMap, len(*in))
-		copy(*out, *in)
-	}
-	if in.EnableCertManager != nil {
-		in, out := &in.EnableCertManager, &out.EnableCertManager
-		*out = new(bool)
+	if in.TemplateType != nil {
+		in, out := &in.TemplateType, &out.TemplateType
+		*out = new(TemplateType)
 		**out = **in
 	}
-	if in.StorageOptions != nil {
-		in, out := &in.StorageOptions, &out.StorageOptions
-		*out = new(StorageConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceOptions != nil {
-		in, out := &in.ServiceOptions, &out.ServiceOptions
-		*out = new(ServiceConfigList)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NetworkOptions != nil {
-		in, out := &in.NetworkOptions, &out.NetworkOptions
-		*out = new(NetworkConfigurationList)
-		(*in).DeepCopyInto(*out)
+	if in.ArchivalPeriod != nil {
+		in, out := &in.ArchivalPeriod, &out.ArchivalPeriod
+		*out = new(v1.Duration)
+		**out = **in
 	}
-	if in.ReportOptions != nil {
-		in, out := &in.ReportOptions, &out.ReportOptions
-		*out = new(ReportConfiguration)
-		(*in).DeepCopyInto(*out)
+	if in.PreservedArchives != nil {
+		in, out := &in.PreservedArchives, &out.PreservedArchives
+		*out = new(int32)
+		**out = **in
 	}
-	if in.JmxCacheOptions != nil {
-		in, out := &in.JmxCacheOptions, &out.JmxCacheOptions
-		*out = new(JmxCacheOptions)
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatSpec.
-func (in *CryostatSpec) DeepCopy() *CryostatSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleSpec.
+func (in *AutomatedRuleSpec) DeepCopy() *AutomatedRuleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CryostatSpec)
+	out := new(AutomatedRuleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
+func (in *AutomatedRuleStatus) DeepCopyInto(out *AutomatedRuleStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -222,231 +160,1314 @@ func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatStatus.
-func (in *CryostatStatus) DeepCopy() *CryostatStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleStatus.
+func (in *AutomatedRuleStatus) DeepCopy() *AutomatedRuleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CryostatStatus)
+	out := new(AutomatedRuleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmptyDirConfig) DeepCopyInto(out *EmptyDirConfig) {
+func (in *BackupArchive) DeepCopyInto(out *BackupArchive) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDirConfig.
-func (in *EmptyDirConfig) DeepCopy() *EmptyDirConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArchive.
+func (in *BackupArchive) DeepCopy() *BackupArchive {
 	if in == nil {
 		return nil
 	}
-	out := new(EmptyDirConfig)
+	out := new(BackupArchive)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EventInfo) DeepCopyInto(out *EventInfo) {
+func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
 	*out = *in
-	if in.Category != nil {
-		in, out := &in.Category, &out.Category
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(BackupPVCDestination)
+		**out = **in
 	}
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
-		*out = make(map[string]OptionDescriptor, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.VolumeSnapshot != nil {
+		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
+		*out = new(BackupVolumeSnapshotDestination)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventInfo.
-func (in *EventInfo) DeepCopy() *EventInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
+func (in *BackupDestination) DeepCopy() *BackupDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(EventInfo)
+	out := new(BackupDestination)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlightRecorder) DeepCopyInto(out *FlightRecorder) {
+func (in *BackupPVCDestination) DeepCopyInto(out *BackupPVCDestination) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorder.
-func (in *FlightRecorder) DeepCopy() *FlightRecorder {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPVCDestination.
+func (in *BackupPVCDestination) DeepCopy() *BackupPVCDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(FlightRecorder)
+	out := new(BackupPVCDestination)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FlightRecorder) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlightRecorderList) DeepCopyInto(out *FlightRecorderList) {
+func (in *BackupVolumeSnapshotDestination) DeepCopyInto(out *BackupVolumeSnapshotDestination) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]FlightRecorder, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderList.
-func (in *FlightRecorderList) DeepCopy() *FlightRecorderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVolumeSnapshotDestination.
+func (in *BackupVolumeSnapshotDestination) DeepCopy() *BackupVolumeSnapshotDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(FlightRecorderList)
+	out := new(BackupVolumeSnapshotDestination)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FlightRecorderList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlightRecorderSpec) DeepCopyInto(out *FlightRecorderSpec) {
+func (in *CertificateConfiguration) DeepCopyInto(out *CertificateConfiguration) {
 	*out = *in
-	if in.RecordingSelector != nil {
-		in, out := &in.RecordingSelector, &out.RecordingSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(IssuerReference)
+		**out = **in
 	}
-	if in.JMXCredentials != nil {
-		in, out := &in.JMXCredentials, &out.JMXCredentials
-		*out = new(JMXAuthSecret)
-		(*in).DeepCopyInto(*out)
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PrivateKey != nil {
+		in, out := &in.PrivateKey, &out.PrivateKey
+		*out = new(CertificatePrivateKey)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderSpec.
-func (in *FlightRecorderSpec) DeepCopy() *FlightRecorderSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateConfiguration.
+func (in *CertificateConfiguration) DeepCopy() *CertificateConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(FlightRecorderSpec)
+	out := new(CertificateConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlightRecorderStatus) DeepCopyInto(out *FlightRecorderStatus) {
+func (in *CertificatePrivateKey) DeepCopyInto(out *CertificatePrivateKey) {
 	*out = *in
-	if in.Events != nil {
-		in, out := &in.Events, &out.Events
-		*out = make([]EventInfo, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = make([]TemplateInfo, len(*in))
-		copy(*out, *in)
-	}
-	if in.Target != nil {
-		in, out := &in.Target, &out.Target
-		*out = new(corev1.ObjectReference)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderStatus.
-func (in *FlightRecorderStatus) DeepCopy() *FlightRecorderStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePrivateKey.
+func (in *CertificatePrivateKey) DeepCopy() *CertificatePrivateKey {
 	if in == nil {
 		return nil
 	}
-	out := new(FlightRecorderStatus)
+	out := new(CertificatePrivateKey)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaServiceConfig) DeepCopyInto(out *GrafanaServiceConfig) {
+func (in *CertificateSecret) DeepCopyInto(out *CertificateSecret) {
 	*out = *in
-	if in.HTTPPort != nil {
-		in, out := &in.HTTPPort, &out.HTTPPort
-		*out = new(int32)
+	if in.CertificateKey != nil {
+		in, out := &in.CertificateKey, &out.CertificateKey
+		*out = new(string)
 		**out = **in
 	}
-	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceConfig.
-func (in *GrafanaServiceConfig) DeepCopy() *GrafanaServiceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecret.
+func (in *CertificateSecret) DeepCopy() *CertificateSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaServiceConfig)
+	out := new(CertificateSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JMXAuthSecret) DeepCopyInto(out *JMXAuthSecret) {
+func (in *ContainerEnvironment) DeepCopyInto(out *ContainerEnvironment) {
 	*out = *in
-	if in.UsernameKey != nil {
-		in, out := &in.UsernameKey, &out.UsernameKey
-		*out = new(string)
-		**out = **in
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.PasswordKey != nil {
-		in, out := &in.PasswordKey, &out.PasswordKey
-		*out = new(string)
-		**out = **in
+	if in.ExtraEnvFrom != nil {
+		in, out := &in.ExtraEnvFrom, &out.ExtraEnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JMXAuthSecret.
-func (in *JMXAuthSecret) DeepCopy() *JMXAuthSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnvironment.
+func (in *ContainerEnvironment) DeepCopy() *ContainerEnvironment {
 	if in == nil {
 		return nil
 	}
-	out := new(JMXAuthSecret)
+	out := new(ContainerEnvironment)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JmxCacheOptions) DeepCopyInto(out *JmxCacheOptions) {
+func (in *CoreServiceConfig) DeepCopyInto(out *CoreServiceConfig) {
 	*out = *in
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.JMXPort != nil {
+		in, out := &in.JMXPort, &out.JMXPort
+		*out = new(int32)
+		**out = **in
+	}
+	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JmxCacheOptions.
-func (in *JmxCacheOptions) DeepCopy() *JmxCacheOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreServiceConfig.
+func (in *CoreServiceConfig) DeepCopy() *CoreServiceConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(JmxCacheOptions)
+	out := new(CoreServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cryostat) DeepCopyInto(out *Cryostat) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cryostat.
+func (in *Cryostat) DeepCopy() *Cryostat {
+	if in == nil {
+		return nil
+	}
+	out := new(Cryostat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Cryostat) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackup) DeepCopyInto(out *CryostatBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackup.
+func (in *CryostatBackup) DeepCopy() *CryostatBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackupList) DeepCopyInto(out *CryostatBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CryostatBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackupList.
+func (in *CryostatBackupList) DeepCopy() *CryostatBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackupSpec) DeepCopyInto(out *CryostatBackupSpec) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]StoragePath, len(*in))
+		copy(*out, *in)
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackupSpec.
+func (in *CryostatBackupSpec) DeepCopy() *CryostatBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackupStatus) DeepCopyInto(out *CryostatBackupStatus) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]StoragePath, len(*in))
+		copy(*out, *in)
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(BackupArchive)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackupStatus.
+func (in *CryostatBackupStatus) DeepCopy() *CryostatBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatList) DeepCopyInto(out *CryostatList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Cryostat, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatList.
+func (in *CryostatList) DeepCopy() *CryostatList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestore) DeepCopyInto(out *CryostatRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestore.
+func (in *CryostatRestore) DeepCopy() *CryostatRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreList) DeepCopyInto(out *CryostatRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CryostatRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreList.
+func (in *CryostatRestoreList) DeepCopy() *CryostatRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreSpec) DeepCopyInto(out *CryostatRestoreSpec) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]StoragePath, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreSpec.
+func (in *CryostatRestoreSpec) DeepCopy() *CryostatRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreStatus) DeepCopyInto(out *CryostatRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreStatus.
+func (in *CryostatRestoreStatus) DeepCopy() *CryostatRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
+	*out = *in
+	if in.TrustedCertSecrets != nil {
+		in, out := &in.TrustedCertSecrets, &out.TrustedCertSecrets
+		*out = make([]CertificateSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventTemplates != nil {
+		in, out := &in.EventTemplates, &out.EventTemplates
+		*out = make([]TemplateConfigMap, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnableCertManager != nil {
+		in, out := &in.EnableCertManager, &out.EnableCertManager
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSProvider != nil {
+		in, out := &in.TLSProvider, &out.TLSProvider
+		*out = new(TLSProvider)
+		**out = **in
+	}
+	if in.CertificateOptions != nil {
+		in, out := &in.CertificateOptions, &out.CertificateOptions
+		*out = new(CertificateConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageOptions != nil {
+		in, out := &in.StorageOptions, &out.StorageOptions
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceOptions != nil {
+		in, out := &in.ServiceOptions, &out.ServiceOptions
+		*out = new(ServiceConfigList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkOptions != nil {
+		in, out := &in.NetworkOptions, &out.NetworkOptions
+		*out = new(NetworkConfigurationList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicyOptions != nil {
+		in, out := &in.NetworkPolicyOptions, &out.NetworkPolicyOptions
+		*out = new(NetworkPolicyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportOptions != nil {
+		in, out := &in.ReportOptions, &out.ReportOptions
+		*out = new(ReportConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JmxCacheOptions != nil {
+		in, out := &in.JmxCacheOptions, &out.JmxCacheOptions
+		*out = new(JmxCacheOptions)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.TargetDiscoveryOptions != nil {
+		in, out := &in.TargetDiscoveryOptions, &out.TargetDiscoveryOptions
+		*out = new(TargetDiscoveryOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(SchedulingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityOptions != nil {
+		in, out := &in.SecurityOptions, &out.SecurityOptions
+		*out = new(SecurityOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvironmentOptions != nil {
+		in, out := &in.EnvironmentOptions, &out.EnvironmentOptions
+		*out = new(EnvironmentOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(PodTemplateOverride)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatSpec.
+func (in *CryostatSpec) DeepCopy() *CryostatSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ImageStatus)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatStatus.
+func (in *CryostatStatus) DeepCopy() *CryostatStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomTarget) DeepCopyInto(out *CustomTarget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomTarget.
+func (in *CustomTarget) DeepCopy() *CustomTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CustomTarget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomTargetList) DeepCopyInto(out *CustomTargetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CustomTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomTargetList.
+func (in *CustomTargetList) DeepCopy() *CustomTargetList {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomTargetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CustomTargetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomTargetSpec) DeepCopyInto(out *CustomTargetSpec) {
+	*out = *in
+	if in.ConnectURL != nil {
+		in, out := &in.ConnectURL, &out.ConnectURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.Host != nil {
+		in, out := &in.Host, &out.Host
+		*out = new(string)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.JMXCredentials != nil {
+		in, out := &in.JMXCredentials, &out.JMXCredentials
+		*out = new(JMXAuthSecret)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomTargetSpec.
+func (in *CustomTargetSpec) DeepCopy() *CustomTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomTargetStatus) DeepCopyInto(out *CustomTargetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomTargetStatus.
+func (in *CustomTargetStatus) DeepCopy() *CustomTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmptyDirConfig) DeepCopyInto(out *EmptyDirConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDirConfig.
+func (in *EmptyDirConfig) DeepCopy() *EmptyDirConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EmptyDirConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentOptions) DeepCopyInto(out *EnvironmentOptions) {
+	*out = *in
+	if in.CoreEnvironment != nil {
+		in, out := &in.CoreEnvironment, &out.CoreEnvironment
+		*out = new(JavaContainerEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataSourceEnvironment != nil {
+		in, out := &in.DataSourceEnvironment, &out.DataSourceEnvironment
+		*out = new(JavaContainerEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaEnvironment != nil {
+		in, out := &in.GrafanaEnvironment, &out.GrafanaEnvironment
+		*out = new(ContainerEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentOptions.
+func (in *EnvironmentOptions) DeepCopy() *EnvironmentOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventInfo) DeepCopyInto(out *EventInfo) {
+	*out = *in
+	if in.Category != nil {
+		in, out := &in.Category, &out.Category
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]OptionDescriptor, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventInfo.
+func (in *EventInfo) DeepCopy() *EventInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(EventInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTemplate) DeepCopyInto(out *EventTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTemplate.
+func (in *EventTemplate) DeepCopy() *EventTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTemplateList) DeepCopyInto(out *EventTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EventTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTemplateList.
+func (in *EventTemplateList) DeepCopy() *EventTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTemplateSpec) DeepCopyInto(out *EventTemplateSpec) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(TemplateConfigMap)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTemplateSpec.
+func (in *EventTemplateSpec) DeepCopy() *EventTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTemplateStatus) DeepCopyInto(out *EventTemplateStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTemplateStatus.
+func (in *EventTemplateStatus) DeepCopy() *EventTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlightRecorder) DeepCopyInto(out *FlightRecorder) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorder.
+func (in *FlightRecorder) DeepCopy() *FlightRecorder {
+	if in == nil {
+		return nil
+	}
+	out := new(FlightRecorder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlightRecorder) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlightRecorderList) DeepCopyInto(out *FlightRecorderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FlightRecorder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderList.
+func (in *FlightRecorderList) DeepCopy() *FlightRecorderList {
+	if in == nil {
+		return nil
+	}
+	out := new(FlightRecorderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlightRecorderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlightRecorderSpec) DeepCopyInto(out *FlightRecorderSpec) {
+	*out = *in
+	if in.RecordingSelector != nil {
+		in, out := &in.RecordingSelector, &out.RecordingSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JMXCredentials != nil {
+		in, out := &in.JMXCredentials, &out.JMXCredentials
+		*out = new(JMXAuthSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(JMXTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeTemplate != nil {
+		in, out := &in.ProbeTemplate, &out.ProbeTemplate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderSpec.
+func (in *FlightRecorderSpec) DeepCopy() *FlightRecorderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlightRecorderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlightRecorderStatus) DeepCopyInto(out *FlightRecorderStatus) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]EventInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]TemplateInfo, len(*in))
+		copy(*out, *in)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.JVMInfo != nil {
+		in, out := &in.JVMInfo, &out.JVMInfo
+		*out = new(JVMInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlightRecorderStatus.
+func (in *FlightRecorderStatus) DeepCopy() *FlightRecorderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlightRecorderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayConfiguration) DeepCopyInto(out *GatewayConfiguration) {
+	*out = *in
+	out.GatewayRef = in.GatewayRef
+	if in.CoreRoute != nil {
+		in, out := &in.CoreRoute, &out.CoreRoute
+		*out = new(HTTPRouteConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaRoute != nil {
+		in, out := &in.GrafanaRoute, &out.GrafanaRoute
+		*out = new(HTTPRouteConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfiguration.
+func (in *GatewayConfiguration) DeepCopy() *GatewayConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaServiceConfig) DeepCopyInto(out *GrafanaServiceConfig) {
+	*out = *in
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(int32)
+		**out = **in
+	}
+	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceConfig.
+func (in *GrafanaServiceConfig) DeepCopy() *GrafanaServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteConfiguration) DeepCopyInto(out *HTTPRouteConfiguration) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteConfiguration.
+func (in *HTTPRouteConfiguration) DeepCopy() *HTTPRouteConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JMXAuthSecret) DeepCopyInto(out *JMXAuthSecret) {
+	*out = *in
+	if in.UsernameKey != nil {
+		in, out := &in.UsernameKey, &out.UsernameKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordKey != nil {
+		in, out := &in.PasswordKey, &out.PasswordKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JMXAuthSecret.
+func (in *JMXAuthSecret) DeepCopy() *JMXAuthSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(JMXAuthSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JMXTLSConfig) DeepCopyInto(out *JMXTLSConfig) {
+	*out = *in
+	in.CACert.DeepCopyInto(&out.CACert)
+	if in.ClientCertSecret != nil {
+		in, out := &in.ClientCertSecret, &out.ClientCertSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JMXTLSConfig.
+func (in *JMXTLSConfig) DeepCopy() *JMXTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(JMXTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMInfo) DeepCopyInto(out *JVMInfo) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Uptime != nil {
+		in, out := &in.Uptime, &out.Uptime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HeapInitial != nil {
+		in, out := &in.HeapInitial, &out.HeapInitial
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HeapMax != nil {
+		in, out := &in.HeapMax, &out.HeapMax
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.InputArguments != nil {
+		in, out := &in.InputArguments, &out.InputArguments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMInfo.
+func (in *JVMInfo) DeepCopy() *JVMInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JavaContainerEnvironment) DeepCopyInto(out *JavaContainerEnvironment) {
+	*out = *in
+	if in.JVMOptions != nil {
+		in, out := &in.JVMOptions, &out.JVMOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ContainerEnvironment.DeepCopyInto(&out.ContainerEnvironment)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JavaContainerEnvironment.
+func (in *JavaContainerEnvironment) DeepCopy() *JavaContainerEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(JavaContainerEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JmxCacheOptions) DeepCopyInto(out *JmxCacheOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JmxCacheOptions.
+func (in *JmxCacheOptions) DeepCopy() *JmxCacheOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(JmxCacheOptions)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -459,6 +1480,11 @@ func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
 		*out = new(networkingv1.IngressSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RouteConfig != nil {
+		in, out := &in.RouteConfig, &out.RouteConfig
+		*out = new(RouteConfiguration)
+		**out = **in
+	}
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
@@ -480,86 +1506,318 @@ func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfiguration)
+	out := new(NetworkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigurationList) DeepCopyInto(out *NetworkConfigurationList) {
+	*out = *in
+	if in.CoreConfig != nil {
+		in, out := &in.CoreConfig, &out.CoreConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommandConfig != nil {
+		in, out := &in.CommandConfig, &out.CommandConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaConfig != nil {
+		in, out := &in.GrafanaConfig, &out.GrafanaConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GatewayConfig != nil {
+		in, out := &in.GatewayConfig, &out.GatewayConfig
+		*out = new(GatewayConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigurationList.
+func (in *NetworkConfigurationList) DeepCopy() *NetworkConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfiguration) DeepCopyInto(out *NetworkPolicyConfiguration) {
+	*out = *in
+	if in.IngressControllerPeers != nil {
+		in, out := &in.IngressControllerPeers, &out.IngressControllerPeers
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalIngressPeers != nil {
+		in, out := &in.AdditionalIngressPeers, &out.AdditionalIngressPeers
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalEgressPeers != nil {
+		in, out := &in.AdditionalEgressPeers, &out.AdditionalEgressPeers
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfiguration.
+func (in *NetworkPolicyConfiguration) DeepCopy() *NetworkPolicyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptionDescriptor) DeepCopyInto(out *OptionDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionDescriptor.
+func (in *OptionDescriptor) DeepCopy() *OptionDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimConfig.
+func (in *PersistentVolumeClaimConfig) DeepCopy() *PersistentVolumeClaimConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfiguration) DeepCopyInto(out *PodDisruptionBudgetConfiguration) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfiguration.
+func (in *PodDisruptionBudgetConfiguration) DeepCopy() *PodDisruptionBudgetConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateOverride) DeepCopyInto(out *PodTemplateOverride) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(PodTemplatePatchType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateOverride.
+func (in *PodTemplateOverride) DeepCopy() *PodTemplateOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeInfo) DeepCopyInto(out *ProbeInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeInfo.
+func (in *ProbeInfo) DeepCopy() *ProbeInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeStatus) DeepCopyInto(out *ProbeStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]ProbeInfo, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeStatus.
+func (in *ProbeStatus) DeepCopy() *ProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfigurationList) DeepCopyInto(out *NetworkConfigurationList) {
+func (in *ProbeTemplate) DeepCopyInto(out *ProbeTemplate) {
 	*out = *in
-	if in.CoreConfig != nil {
-		in, out := &in.CoreConfig, &out.CoreConfig
-		*out = new(NetworkConfiguration)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplate.
+func (in *ProbeTemplate) DeepCopy() *ProbeTemplate {
+	if in == nil {
+		return nil
 	}
-	if in.CommandConfig != nil {
-		in, out := &in.CommandConfig, &out.CommandConfig
-		*out = new(NetworkConfiguration)
-		(*in).DeepCopyInto(*out)
+	out := new(ProbeTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProbeTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.GrafanaConfig != nil {
-		in, out := &in.GrafanaConfig, &out.GrafanaConfig
-		*out = new(NetworkConfiguration)
-		(*in).DeepCopyInto(*out)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTemplateList) DeepCopyInto(out *ProbeTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProbeTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigurationList.
-func (in *NetworkConfigurationList) DeepCopy() *NetworkConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplateList.
+func (in *ProbeTemplateList) DeepCopy() *ProbeTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfigurationList)
+	out := new(ProbeTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProbeTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptionDescriptor) DeepCopyInto(out *OptionDescriptor) {
+func (in *ProbeTemplateSpec) DeepCopyInto(out *ProbeTemplateSpec) {
 	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(TemplateConfigMap)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionDescriptor.
-func (in *OptionDescriptor) DeepCopy() *OptionDescriptor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplateSpec.
+func (in *ProbeTemplateSpec) DeepCopy() *ProbeTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OptionDescriptor)
+	out := new(ProbeTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
+func (in *ProbeTemplateStatus) DeepCopyInto(out *ProbeTemplateStatus) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Spec != nil {
-		in, out := &in.Spec, &out.Spec
-		*out = new(corev1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimConfig.
-func (in *PersistentVolumeClaimConfig) DeepCopy() *PersistentVolumeClaimConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTemplateStatus.
+func (in *ProbeTemplateStatus) DeepCopy() *ProbeTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistentVolumeClaimConfig)
+	out := new(ProbeTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -689,7 +1947,37 @@ func (in *RecordingStatus) DeepCopy() *RecordingStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReportConfiguration) DeepCopyInto(out *ReportConfiguration) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ReportsAutoscalingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(SchedulingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityOptions != nil {
+		in, out := &in.SecurityOptions, &out.SecurityOptions
+		*out = new(ReportsSecurityOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvironmentOptions != nil {
+		in, out := &in.EnvironmentOptions, &out.EnvironmentOptions
+		*out = new(JavaContainerEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(PodTemplateOverride)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportConfiguration.
@@ -702,6 +1990,63 @@ func (in *ReportConfiguration) DeepCopy() *ReportConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportsAutoscalingConfiguration) DeepCopyInto(out *ReportsAutoscalingConfiguration) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2beta2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportsAutoscalingConfiguration.
+func (in *ReportsAutoscalingConfiguration) DeepCopy() *ReportsAutoscalingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportsAutoscalingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportsSecurityOptions) DeepCopyInto(out *ReportsSecurityOptions) {
+	*out = *in
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportsSecurityContext != nil {
+		in, out := &in.ReportsSecurityContext, &out.ReportsSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportsSecurityOptions.
+func (in *ReportsSecurityOptions) DeepCopy() *ReportsSecurityOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportsSecurityOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReportsServiceConfig) DeepCopyInto(out *ReportsServiceConfig) {
 	*out = *in
@@ -741,6 +2086,97 @@ func (in *ResourceConfigList) DeepCopy() *ResourceConfigList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteConfiguration) DeepCopyInto(out *RouteConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteConfiguration.
+func (in *RouteConfiguration) DeepCopy() *RouteConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingConfiguration) DeepCopyInto(out *SchedulingConfiguration) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingConfiguration.
+func (in *SchedulingConfiguration) DeepCopy() *SchedulingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityOptions) DeepCopyInto(out *SecurityOptions) {
+	*out = *in
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CoreSecurityContext != nil {
+		in, out := &in.CoreSecurityContext, &out.CoreSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataSourceSecurityContext != nil {
+		in, out := &in.DataSourceSecurityContext, &out.DataSourceSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaSecurityContext != nil {
+		in, out := &in.GrafanaSecurityContext, &out.GrafanaSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityOptions.
+func (in *SecurityOptions) DeepCopy() *SecurityOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
 	*out = *in
@@ -818,6 +2254,11 @@ func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 		*out = new(EmptyDirConfig)
 		**out = **in
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(StorageMonitoringConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -830,6 +2271,106 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMonitoringConfiguration) DeepCopyInto(out *StorageMonitoringConfiguration) {
+	*out = *in
+	if in.WarningThresholdPercent != nil {
+		in, out := &in.WarningThresholdPercent, &out.WarningThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CriticalThresholdPercent != nil {
+		in, out := &in.CriticalThresholdPercent, &out.CriticalThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMonitoringConfiguration.
+func (in *StorageMonitoringConfiguration) DeepCopy() *StorageMonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
+	*out = *in
+	if in.CapacityBytes != nil {
+		in, out := &in.CapacityBytes, &out.CapacityBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UsedBytes != nil {
+		in, out := &in.UsedBytes, &out.UsedBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AvailableBytes != nil {
+		in, out := &in.AvailableBytes, &out.AvailableBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
+func (in *StorageStatus) DeepCopy() *StorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetDiscoveryOptions) DeepCopyInto(out *TargetDiscoveryOptions) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceSelector != nil {
+		in, out := &in.ServiceSelector, &out.ServiceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JMXPortNames != nil {
+		in, out := &in.JMXPortNames, &out.JMXPortNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JMXPortNumbers != nil {
+		in, out := &in.JMXPortNumbers, &out.JMXPortNumbers
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.AgentPortNames != nil {
+		in, out := &in.AgentPortNames, &out.AgentPortNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetDiscoveryOptions.
+func (in *TargetDiscoveryOptions) DeepCopy() *TargetDiscoveryOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetDiscoveryOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateConfigMap) DeepCopyInto(out *TemplateConfigMap) {
 	*out = *in
@@ -3,6 +3,7 @@ module github.com/cryostatio/cryostat-operator
 go 1.16
 
 require (
+	github.com/evanphx/json-patch v4.9.0+incompatible
 	github.com/go-logr/logr v0.3.0
 	github.com/jetstack/cert-manager v1.1.0
 	github.com/onsi/ginkgo v1.14.1
@@ -12,6 +13,8 @@ require (
 	k8s.io/apimachinery v0.19.2
 	k8s.io/client-go v0.19.2
 	sigs.k8s.io/controller-runtime v0.7.2
+	sigs.k8s.io/yaml v1.2.0
+	software.sslmate.com/src/go-pkcs12 v0.4.0
 )
 
 replace github.com/openshift/api => github.com/openshift/api v0.0.0-20200618202633-7192180f496a
@@ -0,0 +1,316 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// AutomatedRuleReconciler reconciles an AutomatedRule object
+type AutomatedRuleReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	common.Reconciler
+}
+
+const automatedRuleFinalizer = "operator.cryostat.io/automatedrule.finalizer"
+
+// Cryostat does not notify the operator of changes to its rules,
+// so check for drift periodically
+const ruleSyncInterval = 5 * time.Minute
+
+const (
+	reasonRuleSynced         = "RuleSynced"
+	reasonRuleDriftCorrected = "DriftCorrected"
+	reasonRuleSyncFailed     = "SyncFailed"
+	reasonRuleNameConflict   = "RuleNameConflict"
+)
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=automatedrules,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=automatedrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=automatedrules/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes Cryostat automated rules to match AutomatedRule objects
+func (r *AutomatedRuleReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling AutomatedRule")
+
+	// Fetch the AutomatedRule instance
+	rule := &operatorv1beta1.AutomatedRule{}
+	err := r.Client.Get(ctx, request.NamespacedName, rule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Check if this AutomatedRule is being deleted
+	if rule.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(rule, automatedRuleFinalizer) {
+			err = r.deleteRule(ctx, rule)
+			if err != nil {
+				return common.RequeueIfNotReady(r.Log, err)
+			}
+			err = common.RemoveFinalizer(ctx, r.Client, rule, automatedRuleFinalizer)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		// Ready for deletion
+		return reconcile.Result{}, nil
+	}
+
+	// Add our finalizer, so we can remove the rule from Cryostat when this AutomatedRule is deleted
+	if !controllerutil.ContainsFinalizer(rule, automatedRuleFinalizer) {
+		err = common.AddFinalizer(ctx, r.Client, rule, automatedRuleFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Different AutomatedRule names may map to the same rule name in Cryostat,
+	// which belongs to whichever AutomatedRule created it first
+	desired := newRule(rule)
+	owner, err := r.findRuleOwner(ctx, rule, desired.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if owner != nil {
+		meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+			Type:    string(operatorv1beta1.ConditionTypeRuleSynced),
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonRuleNameConflict,
+			Message: fmt.Sprintf("AutomatedRule %s already provides a rule named %s", owner.Name, desired.Name),
+		})
+		// Check again later, in case the other AutomatedRule is deleted
+		return reconcile.Result{RequeueAfter: ruleSyncInterval}, r.Client.Status().Update(ctx, rule)
+	}
+
+	// Obtain a client configured to communicate with Cryostat
+	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, nil)
+	if err != nil {
+		return common.RequeueIfNotReady(r.Log, err)
+	}
+
+	// Compare the rule in Cryostat with the one we expect
+	current, err := cryostat.GetRule(desired.Name)
+	if err != nil {
+		reqLogger.Error(err, "failed to retrieve rule from Cryostat", "rule", desired.Name)
+		return reconcile.Result{}, r.setSyncFailedCondition(ctx, rule, err)
+	}
+
+	reason := reasonRuleSynced
+	message := fmt.Sprintf("Rule %s is up to date in Cryostat", desired.Name)
+	if current == nil || !ruleMatches(current, desired) {
+		// If the spec hasn't changed since the last sync, the rule was modified outside of the operator
+		if len(rule.Status.RuleName) > 0 && rule.Status.ObservedGeneration == rule.Generation {
+			reqLogger.Info("rule in Cryostat differs from AutomatedRule, restoring it", "rule", desired.Name)
+			reason = reasonRuleDriftCorrected
+			message = fmt.Sprintf("Rule %s was changed in Cryostat and has been restored", desired.Name)
+		}
+
+		// Rules cannot be modified in place, so replace any existing rule
+		if current != nil {
+			err = cryostat.DeleteRule(desired.Name)
+			if err != nil {
+				reqLogger.Error(err, "failed to remove outdated rule from Cryostat", "rule", desired.Name)
+				return reconcile.Result{}, r.setSyncFailedCondition(ctx, rule, err)
+			}
+		}
+		reqLogger.Info("creating rule in Cryostat", "rule", desired.Name)
+		err = cryostat.CreateRule(desired)
+		if err != nil {
+			reqLogger.Error(err, "failed to create rule in Cryostat", "rule", desired.Name)
+			return reconcile.Result{}, r.setSyncFailedCondition(ctx, rule, err)
+		}
+	}
+
+	rule.Status.RuleName = desired.Name
+	rule.Status.ObservedGeneration = rule.Generation
+	meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeRuleSynced),
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	err = r.Client.Status().Update(ctx, rule)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("AutomatedRule successfully updated", "Namespace", request.Namespace, "Name", request.Name)
+	return reconcile.Result{RequeueAfter: ruleSyncInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AutomatedRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.AutomatedRule{}).
+		Complete(r)
+}
+
+func (r *AutomatedRuleReconciler) deleteRule(ctx context.Context, rule *operatorv1beta1.AutomatedRule) error {
+	if len(rule.Status.RuleName) == 0 {
+		// Never created
+		return nil
+	}
+	// Nothing to remove if Cryostat has been deleted
+	cryostats := &operatorv1beta1.CryostatList{}
+	err := r.Client.List(ctx, cryostats, client.InNamespace(rule.Namespace))
+	if err != nil {
+		return err
+	}
+	if len(cryostats.Items) == 0 {
+		return nil
+	}
+	cryostat, err := r.GetCryostatClient(ctx, rule.Namespace, nil)
+	if err != nil {
+		return err
+	}
+	err = cryostat.DeleteRule(rule.Status.RuleName)
+	if err != nil {
+		r.Log.Error(err, "failed to remove rule from Cryostat", "rule", rule.Status.RuleName)
+		return err
+	}
+	return nil
+}
+
+// findRuleOwner returns another AutomatedRule in the same namespace that has
+// already created a rule with the provided name in Cryostat, if any
+func (r *AutomatedRuleReconciler) findRuleOwner(ctx context.Context, rule *operatorv1beta1.AutomatedRule,
+	ruleName string) (*operatorv1beta1.AutomatedRule, error) {
+	rules := &operatorv1beta1.AutomatedRuleList{}
+	err := r.Client.List(ctx, rules, client.InNamespace(rule.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	for i, other := range rules.Items {
+		if other.Name != rule.Name && other.Status.RuleName == ruleName {
+			return &rules.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// setSyncFailedCondition reports the error in the AutomatedRule's status and returns it,
+// so that the request is retried
+func (r *AutomatedRuleReconciler) setSyncFailedCondition(ctx context.Context, rule *operatorv1beta1.AutomatedRule,
+	syncErr error) error {
+	meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeRuleSynced),
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonRuleSyncFailed,
+		Message: syncErr.Error(),
+	})
+	err := r.Client.Status().Update(ctx, rule)
+	if err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// Cryostat rule names may only contain letters, digits and underscores
+var invalidRuleNameChars = regexp.MustCompile(`\W`)
+
+func getRuleName(rule *operatorv1beta1.AutomatedRule) string {
+	return invalidRuleNameChars.ReplaceAllString(rule.Name, "_")
+}
+
+// ruleMatches returns whether the rule in Cryostat has the values the operator
+// set for each field of the desired rule
+func ruleMatches(current *cryostatClient.Rule, desired *cryostatClient.Rule) bool {
+	return current.Name == desired.Name &&
+		current.Description == desired.Description &&
+		current.MatchExpression == desired.MatchExpression &&
+		current.EventSpecifier == desired.EventSpecifier &&
+		current.ArchivalPeriodSeconds == desired.ArchivalPeriodSeconds &&
+		current.PreservedArchives == desired.PreservedArchives &&
+		current.MaxAgeSeconds == desired.MaxAgeSeconds &&
+		current.MaxSizeBytes == desired.MaxSizeBytes &&
+		current.Enabled == desired.Enabled
+}
+
+func newRule(rule *operatorv1beta1.AutomatedRule) *cryostatClient.Rule {
+	spec := rule.Spec
+	eventSpecifier := "template=" + spec.EventTemplate
+	if spec.TemplateType != nil {
+		eventSpecifier += ",type=" + string(*spec.TemplateType)
+	}
+	result := &cryostatClient.Rule{
+		Name:            getRuleName(rule),
+		Description:     spec.Description,
+		MatchExpression: spec.MatchExpression,
+		EventSpecifier:  eventSpecifier,
+		Enabled:         true,
+	}
+	if spec.ArchivalPeriod != nil {
+		result.ArchivalPeriodSeconds = int64(spec.ArchivalPeriod.Seconds())
+	}
+	if spec.PreservedArchives != nil {
+		result.PreservedArchives = *spec.PreservedArchives
+	}
+	if spec.MaxAge != nil {
+		result.MaxAgeSeconds = int64(spec.MaxAge.Seconds())
+	}
+	if spec.MaxSize != nil {
+		result.MaxSizeBytes = spec.MaxSize.Value()
+	}
+	return result
+}
@@ -0,0 +1,355 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type automatedRuleTestInput struct {
+	controller *controllers.AutomatedRuleReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("AutomatedRuleController", func() {
+	var t *automatedRuleTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.AutomatedRuleReconciler{
+			Client:     t.Client,
+			Scheme:     s,
+			Log:        logger,
+			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		t = &automatedRuleTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewAutomatedRule(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new AutomatedRule", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleNotFoundHandler("test_rule"),
+					test.NewCreateRuleHandler(test.NewRule()),
+				}
+			})
+			It("should create the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				rule := t.getAutomatedRule()
+				Expect(rule.Status.RuleName).To(Equal("test_rule"))
+				Expect(rule.Status.ObservedGeneration).To(Equal(int64(1)))
+			})
+			It("should add finalizer", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				rule := t.getAutomatedRule()
+				Expect(rule.Finalizers).To(ContainElement("operator.cryostat.io/automatedrule.finalizer"))
+			})
+			It("should set RuleSynced condition", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				t.checkRuleSyncedCondition(metav1.ConditionTrue, "RuleSynced")
+			})
+		})
+		Context("with optional fields omitted", func() {
+			BeforeEach(func() {
+				rule := test.NewAutomatedRule()
+				rule.Spec = operatorv1beta1.AutomatedRuleSpec{
+					MatchExpression: "true",
+					EventTemplate:   "Profiling",
+				}
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), rule,
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleNotFoundHandler("test_rule"),
+					test.NewCreateRuleHandler(&cryostatClient.Rule{
+						Name:            "test_rule",
+						MatchExpression: "true",
+						EventSpecifier:  "template=Profiling",
+						Enabled:         true,
+					}),
+				}
+			})
+			It("should create the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				t.checkRuleSyncedCondition(metav1.ConditionTrue, "RuleSynced")
+			})
+		})
+		Context("with a rule in sync", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewAutomatedRuleSynced(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleHandler(test.NewRule()),
+				}
+			})
+			It("should not modify the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				t.checkRuleSyncedCondition(metav1.ConditionTrue, "RuleSynced")
+			})
+		})
+		Context("with a changed spec", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewAutomatedRuleChanged(),
+				}
+				expected := test.NewRule()
+				expected.MaxAgeSeconds = 1200
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleHandler(test.NewRule()),
+					test.NewDeleteRuleHandler("test_rule"),
+					test.NewCreateRuleHandler(expected),
+				}
+			})
+			It("should replace the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				rule := t.getAutomatedRule()
+				Expect(rule.Status.ObservedGeneration).To(Equal(int64(2)))
+				t.checkRuleSyncedCondition(metav1.ConditionTrue, "RuleSynced")
+			})
+		})
+		Context("with a rule modified in Cryostat", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewAutomatedRuleSynced(),
+				}
+				modified := test.NewRule()
+				modified.Enabled = false
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleHandler(modified),
+					test.NewDeleteRuleHandler("test_rule"),
+					test.NewCreateRuleHandler(test.NewRule()),
+				}
+			})
+			It("should restore the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				t.checkRuleSyncedCondition(metav1.ConditionTrue, "DriftCorrected")
+			})
+		})
+		Context("with a rule removed from Cryostat", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewAutomatedRuleSynced(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleNotFoundHandler("test_rule"),
+					test.NewCreateRuleHandler(test.NewRule()),
+				}
+			})
+			It("should recreate the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				t.checkRuleSyncedCondition(metav1.ConditionTrue, "DriftCorrected")
+			})
+		})
+		Context("with a rule name used by another AutomatedRule", func() {
+			BeforeEach(func() {
+				other := test.NewAutomatedRuleSynced()
+				other.Name = "test.rule"
+				t.objs = append(t.objs, other)
+			})
+			It("should not modify the rule", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				rule := t.getAutomatedRule()
+				Expect(rule.Status.RuleName).To(BeEmpty())
+			})
+			It("should set RuleSynced condition", func() {
+				t.expectAutomatedRuleReconcileSuccess()
+				t.checkRuleSyncedCondition(metav1.ConditionFalse, "RuleNameConflict")
+			})
+		})
+		Context("creating the rule fails", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewGetRuleNotFoundHandler("test_rule"),
+					test.NewCreateRuleFailHandler(test.NewRule()),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectAutomatedRuleReconcileError()
+			})
+			It("should set RuleSynced condition", func() {
+				t.expectAutomatedRuleReconcileError()
+				t.checkRuleSyncedCondition(metav1.ConditionFalse, "SyncFailed")
+			})
+		})
+		Context("AutomatedRule does not exist", func() {
+			It("should do nothing", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("Cryostat CR is missing", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCACert(), test.NewCryostatService(), test.NewAutomatedRule(),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectAutomatedRuleReconcileError()
+			})
+		})
+		Context("deleting an AutomatedRule", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewDeletedAutomatedRule(),
+				}
+			})
+			Context("that exists in Cryostat", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteRuleHandler("test_rule"),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectAutomatedRuleDeleted()
+				})
+			})
+			Context("that Cryostat no longer knows about", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteRuleNotFoundHandler("test_rule"),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectAutomatedRuleDeleted()
+				})
+			})
+			Context("after Cryostat is deleted", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCACert(), test.NewCryostatService(), test.NewDeletedAutomatedRule(),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectAutomatedRuleDeleted()
+				})
+			})
+		})
+	})
+})
+
+func (t *automatedRuleTestInput) expectAutomatedRuleReconcileSuccess() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-rule", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Minute}))
+}
+
+func (t *automatedRuleTestInput) expectAutomatedRuleReconcileError() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-rule", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).To(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *automatedRuleTestInput) expectAutomatedRuleDeleted() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-rule", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+
+	rule := &operatorv1beta1.AutomatedRule{}
+	err = t.Client.Get(context.Background(), req.NamespacedName, rule)
+	if err != nil {
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		return
+	}
+	Expect(rule.Finalizers).ToNot(ContainElement("operator.cryostat.io/automatedrule.finalizer"))
+}
+
+func (t *automatedRuleTestInput) getAutomatedRule() *operatorv1beta1.AutomatedRule {
+	rule := &operatorv1beta1.AutomatedRule{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-rule", Namespace: "default"}, rule)
+	Expect(err).ToNot(HaveOccurred())
+	return rule
+}
+
+func (t *automatedRuleTestInput) checkRuleSyncedCondition(status metav1.ConditionStatus, reason string) {
+	rule := t.getAutomatedRule()
+	condition := meta.FindStatusCondition(rule.Status.Conditions, string(operatorv1beta1.ConditionTypeRuleSynced))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
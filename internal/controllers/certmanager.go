@@ -40,15 +40,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -70,23 +73,35 @@ func (r *CryostatReconciler) setupTLS(ctx context.Context, cr *operatorv1beta1.C
 		return nil, errCertManagerMissing
 	}
 
-	// Create self-signed issuer used to bootstrap CA
-	err = r.createOrUpdateIssuer(ctx, resources.NewSelfSignedIssuer(cr), cr)
-	if err != nil {
-		return nil, err
-	}
+	// Certificates are signed either by an issuer referenced in the CR, or by a CA
+	// bootstrapped using a self-signed issuer
+	var caCerts []*certv1.Certificate
+	if resources.UsesExternalIssuer(cr) {
+		// Remove the self-signed CA from a previous configuration
+		err = r.deleteSelfSignedCA(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Create self-signed issuer used to bootstrap CA
+		err = r.createOrUpdateIssuer(ctx, resources.NewSelfSignedIssuer(cr), cr)
+		if err != nil {
+			return nil, err
+		}
 
-	// Create CA certificate for Cryostat using the self-signed issuer
-	caCert := resources.NewCryostatCACert(cr)
-	err = r.createOrUpdateCertificate(ctx, caCert, cr)
-	if err != nil {
-		return nil, err
-	}
+		// Create CA certificate for Cryostat using the self-signed issuer
+		caCert := resources.NewCryostatCACert(cr)
+		err = r.createOrUpdateCertificate(ctx, caCert, cr)
+		if err != nil {
+			return nil, err
+		}
+		caCerts = append(caCerts, caCert)
 
-	// Create CA issuer using the CA cert just created
-	err = r.createOrUpdateIssuer(ctx, resources.NewCryostatCAIssuer(cr), cr)
-	if err != nil {
-		return nil, err
+		// Create CA issuer using the CA cert just created
+		err = r.createOrUpdateIssuer(ctx, resources.NewCryostatCAIssuer(cr), cr)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Create secret to hold keystore password
@@ -117,7 +132,7 @@ func (r *CryostatReconciler) setupTLS(ctx context.Context, cr *operatorv1beta1.C
 	}
 
 	// Update owner references of TLS secrets created by cert-manager to ensure proper cleanup
-	err = r.setCertSecretOwner(ctx, cr, caCert, cryostatCert, grafanaCert, reportsCert)
+	err = r.setCertSecretOwner(ctx, cr, append(caCerts, cryostatCert, grafanaCert, reportsCert)...)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +155,11 @@ func (r *CryostatReconciler) setCertSecretOwner(ctx context.Context, cr *operato
 			}
 			return err
 		}
+		// The secret is created by cert-manager, but is still part of the desired state
+		err = addToInventory(ctx, r.Scheme, secret, false)
+		if err != nil {
+			return err
+		}
 		if !metav1.IsControlledBy(secret, cr) {
 			err = controllerutil.SetControllerReference(cr, secret, r.Scheme)
 			if err != nil {
@@ -155,6 +175,29 @@ func (r *CryostatReconciler) setCertSecretOwner(ctx context.Context, cr *operato
 	return nil
 }
 
+func (r *CryostatReconciler) deleteSelfSignedCA(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
+	caCert := resources.NewCryostatCACert(cr)
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      caCert.Spec.SecretName,
+			Namespace: cr.Namespace,
+		},
+	}
+	objs := []client.Object{resources.NewCryostatCAIssuer(cr), caCert, resources.NewSelfSignedIssuer(cr), caSecret}
+	for _, obj := range objs {
+		err := r.Client.Delete(ctx, obj)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		r.Log.Info(fmt.Sprintf("%s deleted", reflect.TypeOf(obj).Elem().Name()), "name", obj.GetName(),
+			"namespace", obj.GetNamespace())
+	}
+	return nil
+}
+
 func (r *CryostatReconciler) certManagerAvailable() (bool, error) {
 	// Check if cert-manager API is available. Checking just one should be enough.
 	_, err := r.RESTMapper.RESTMapping(schema.GroupKind{
@@ -174,7 +217,7 @@ func (r *CryostatReconciler) certManagerAvailable() (bool, error) {
 
 func (r *CryostatReconciler) createOrUpdateIssuer(ctx context.Context, issuer *certv1.Issuer, owner metav1.Object) error {
 	issuerSpec := issuer.Spec.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, issuer, func() error {
+	op, err := r.createOrUpdate(ctx, issuer, func() error {
 		if err := controllerutil.SetControllerReference(owner, issuer, r.Scheme); err != nil {
 			return err
 		}
@@ -191,7 +234,7 @@ func (r *CryostatReconciler) createOrUpdateIssuer(ctx context.Context, issuer *c
 
 func (r *CryostatReconciler) createOrUpdateCertificate(ctx context.Context, cert *certv1.Certificate, owner metav1.Object) error {
 	certSpec := cert.Spec.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cert, func() error {
+	op, err := r.createOrUpdate(ctx, cert, func() error {
 		if err := controllerutil.SetControllerReference(owner, cert, r.Scheme); err != nil {
 			return err
 		}
@@ -208,7 +251,7 @@ func (r *CryostatReconciler) createOrUpdateCertificate(ctx context.Context, cert
 
 func (r *CryostatReconciler) createOrUpdateKeystoreSecret(ctx context.Context, secret *corev1.Secret, owner metav1.Object) error {
 	// Don't modify secret data, since the password is psuedorandomly generated
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+	op, err := r.createOrUpdate(ctx, secret, func() error {
 		if err := controllerutil.SetControllerReference(owner, secret, r.Scheme); err != nil {
 			return err
 		}
@@ -73,6 +73,95 @@ type SavedRecording struct {
 	Name        string `json:"name"`
 	DownloadURL string `json:"downloadUrl"`
 	ReportURL   string `json:"reportUrl"`
+	Size        int64  `json:"size"`
+}
+
+// MBeanMetrics contains information about a JVM retrieved
+// from its platform MBeans
+type MBeanMetrics struct {
+	// Metrics from the JVM's RuntimeMXBean
+	Runtime RuntimeMetrics `json:"runtime"`
+	// Metrics from the JVM's MemoryMXBean
+	Memory MemoryMetrics `json:"memory"`
+}
+
+// RuntimeMetrics contains attributes of the JVM's RuntimeMXBean
+type RuntimeMetrics struct {
+	// Version of the Java virtual machine implementation
+	VMVersion string `json:"vmVersion"`
+	// Vendor of the Java virtual machine implementation
+	VMVendor string `json:"vmVendor"`
+	// Time when the JVM started, in milliseconds since Unix epoch
+	StartTime int64 `json:"startTime"`
+	// Uptime of the JVM, in milliseconds
+	Uptime int64 `json:"uptime"`
+	// Arguments passed to the JVM, excluding those passed to the main method
+	InputArguments []string `json:"inputArguments"`
+}
+
+// MemoryMetrics contains attributes of the JVM's MemoryMXBean
+type MemoryMetrics struct {
+	// Current usage of the heap
+	HeapMemoryUsage MemoryUsage `json:"heapMemoryUsage"`
+}
+
+// MemoryUsage describes the usage of a JVM memory area, in bytes
+type MemoryUsage struct {
+	// Memory initially requested from the operating system
+	Init int64 `json:"init"`
+	// Memory currently in use
+	Used int64 `json:"used"`
+	// Memory guaranteed to be available to the JVM
+	Committed int64 `json:"committed"`
+	// Maximum memory that may be used, or -1 if undefined
+	Max int64 `json:"max"`
+}
+
+// ProbeTemplate describes a JMC agent probe template that has been added to Cryostat
+type ProbeTemplate struct {
+	// Name of the template
+	Name string `json:"name"`
+	// Content of the template, in XML format
+	XML string `json:"xml"`
+}
+
+// ProbeEvent describes a probe that the JMC agent has injected into a JVM
+type ProbeEvent struct {
+	// The ID of the JFR event type emitted by this probe
+	ID string `json:"id"`
+	// Human-readable name for the event
+	Name string `json:"name"`
+	// A description of the event
+	Description string `json:"description"`
+	// Fully-qualified name of the instrumented class
+	Class string `json:"clazz"`
+	// Name of the instrumented method
+	MethodName string `json:"methodName"`
+	// Descriptor of the instrumented method
+	MethodDescriptor string `json:"methodDescriptor"`
+}
+
+// Rule describes an automated rule, which Cryostat uses to start recordings
+// on targets matching its expression
+type Rule struct {
+	// Unique name of the rule, consisting of letters, digits and underscores
+	Name string `json:"name"`
+	// A description of the rule's purpose
+	Description string `json:"description"`
+	// Expression that determines which targets the rule applies to
+	MatchExpression string `json:"matchExpression"`
+	// Event options for recordings created by this rule, such as "template=Continuous,type=TARGET"
+	EventSpecifier string `json:"eventSpecifier"`
+	// How often to archive the recording, in seconds. Zero disables archiving.
+	ArchivalPeriodSeconds int64 `json:"archivalPeriodSeconds"`
+	// Number of archived recordings to keep for each target
+	PreservedArchives int32 `json:"preservedArchives"`
+	// Maximum age of data kept in the recording, in seconds. Zero means no limit.
+	MaxAgeSeconds int64 `json:"maxAgeSeconds"`
+	// Maximum size of data kept in the recording, in bytes. Zero means no limit.
+	MaxSizeBytes int64 `json:"maxSizeBytes"`
+	// Whether the rule is active
+	Enabled bool `json:"enabled"`
 }
 
 // TargetAddress contains an address that Container JFR can use to connect
@@ -80,8 +169,30 @@ type SavedRecording struct {
 type TargetAddress struct {
 	Host string
 	Port int32
+	// URL scheme used to reach the target, such as "http" for the Cryostat
+	// agent. Left empty for JMX targets addressed by Host and Port.
+	Scheme string
+	// Full connection URL for the JVM, such as a JMX service URL.
+	// When set, this takes precedence over Host and Port.
+	ConnectURL string
 }
 
 func (target TargetAddress) String() string {
+	if len(target.ConnectURL) > 0 {
+		return target.ConnectURL
+	}
+	if len(target.Scheme) > 0 {
+		return fmt.Sprintf("%s://%s:%d", target.Scheme, target.Host, target.Port)
+	}
 	return fmt.Sprintf("%s:%d", target.Host, target.Port)
 }
+
+// ServiceURL returns the full connection URL of the target, which Cryostat
+// uses to identify it. Targets addressed by Host and Port are assumed to be
+// reachable with the default JMX service URL.
+func (target TargetAddress) ServiceURL() string {
+	if len(target.ConnectURL) > 0 || len(target.Scheme) > 0 {
+		return target.String()
+	}
+	return fmt.Sprintf("service:jmx:rmi:///jndi/rmi://%s:%d/jmxrmi", target.Host, target.Port)
+}
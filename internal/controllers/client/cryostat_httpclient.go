@@ -37,6 +37,7 @@
 package client
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
@@ -45,6 +46,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -53,6 +55,7 @@ import (
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -94,6 +97,56 @@ type CryostatClient interface {
 	DeleteSavedRecording(jfrFile string) error
 	ListEventTypes(target *TargetAddress) ([]operatorv1beta1.EventInfo, error)
 	ListTemplates(target *TargetAddress) ([]operatorv1beta1.TemplateInfo, error)
+	UploadEventTemplate(fileName string, template []byte) error
+	DeleteEventTemplate(templateName string) error
+	ListProbeTemplates() ([]ProbeTemplate, error)
+	UploadProbeTemplate(templateName string, template []byte) error
+	DeleteProbeTemplate(templateName string) error
+	ApplyProbes(target *TargetAddress, templateName string) error
+	RemoveProbes(target *TargetAddress) error
+	ListProbes(target *TargetAddress) ([]ProbeEvent, error)
+	GetRule(ruleName string) (*Rule, error)
+	CreateRule(rule *Rule) error
+	DeleteRule(ruleName string) error
+	UploadClientCertificate(target *TargetAddress, cert []byte, key []byte) error
+	GetMBeanMetrics(target *TargetAddress) (*MBeanMetrics, error)
+	CreateCustomTarget(target *TargetAddress, alias string) error
+	DeleteCustomTarget(target *TargetAddress) error
+}
+
+// StatusError is returned when Cryostat responds to a request with a
+// non-2xx status code
+type StatusError struct {
+	// HTTP status code of the response
+	StatusCode int
+	// HTTP status line of the response
+	Status string
+	// Error message contained in the response body
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("server returned status: %s", e.Status)
+}
+
+// IsTLSHandshakeError returns whether the error indicates that Cryostat
+// failed to establish a TLS connection with the target JVM
+func IsTLSHandshakeError(err error) bool {
+	statusErr, ok := err.(*StatusError)
+	if !ok {
+		return false
+	}
+	msg := strings.ToLower(statusErr.Message)
+	return strings.Contains(msg, "sslhandshakeexception") || strings.Contains(msg, "handshake")
+}
+
+// v2Response is the envelope that V2 API responses are wrapped in
+type v2Response struct {
+	Data v2ResponseData `json:"data"`
+}
+
+type v2ResponseData struct {
+	Result interface{} `json:"result"`
 }
 
 type httpClient struct {
@@ -102,20 +155,44 @@ type httpClient struct {
 }
 
 type apiPath struct {
+	version  string
 	resource string
 	target   *TargetAddress
 	name     *string
 }
 
 const (
-	resRecordings     = "recordings"
-	resEvents         = "events"
-	resTemplates      = "templates"
-	attrRecordingName = "recordingName"
-	attrEvents        = "events"
-	attrDuration      = "duration"
-	cmdStop           = "stop"
-	cmdSave           = "save"
+	resRecordings      = "recordings"
+	resEvents          = "events"
+	resTemplates       = "templates"
+	resGraphQL         = "graphql"
+	resTargets         = "targets"
+	resProbes          = "probes"
+	resRules           = "rules"
+	resCertificates    = "certificates"
+	attrConnectURL     = "connectUrl"
+	attrAlias          = "alias"
+	apiV1              = "v1"
+	apiV2              = "v2"
+	apiV2_2            = "v2.2"
+	fieldCert          = "cert"
+	fieldKey           = "key"
+	fieldTemplate      = "template"
+	fieldProbeTemplate = "probeTemplate"
+	attrRecordingName  = "recordingName"
+	attrEvents         = "events"
+	attrDuration       = "duration"
+	attrName           = "name"
+	attrDescription    = "description"
+	attrMatchExpr      = "matchExpression"
+	attrEventSpecifier = "eventSpecifier"
+	attrArchivalPeriod = "archivalPeriodSeconds"
+	attrPreserved      = "preservedArchives"
+	attrMaxAge         = "maxAgeSeconds"
+	attrMaxSize        = "maxSizeBytes"
+	attrEnabled        = "enabled"
+	cmdStop            = "stop"
+	cmdSave            = "save"
 )
 
 // NewHTTPClient creates a client to communicate with Cryostat over HTTP(S)
@@ -254,7 +331,8 @@ func (c *httpClient) ListEventTypes(target *TargetAddress) ([]operatorv1beta1.Ev
 	return result, err
 }
 
-// ListTemplates returns a list of templates available in the target JVM
+// ListTemplates returns a list of templates available in the target JVM. If no target
+// is provided, only the custom templates uploaded to Cryostat are returned.
 func (c *httpClient) ListTemplates(target *TargetAddress) ([]operatorv1beta1.TemplateInfo, error) {
 	path := &apiPath{
 		resource: resTemplates,
@@ -265,6 +343,243 @@ func (c *httpClient) ListTemplates(target *TargetAddress) ([]operatorv1beta1.Tem
 	return result, err
 }
 
+// UploadEventTemplate adds a custom event template, in the XML format of a .jfc
+// file, to Cryostat. The template is available to all targets under the label
+// of its configuration element.
+func (c *httpClient) UploadEventTemplate(fileName string, template []byte) error {
+	path := &apiPath{
+		resource: resTemplates,
+	}
+	files := []multipartFile{
+		{field: fieldTemplate, fileName: fileName, content: template},
+	}
+	return c.httpPostMultipart(path, files, nil)
+}
+
+// DeleteEventTemplate removes a custom event template previously added using
+// UploadEventTemplate. Deleting a template that does not exist is not considered an error.
+func (c *httpClient) DeleteEventTemplate(templateName string) error {
+	path := &apiPath{
+		resource: resTemplates,
+		name:     &templateName,
+	}
+	err := c.httpDelete(path, nil)
+	if statusErr, ok := err.(*StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// ListProbeTemplates returns the probe templates that have been added to Cryostat
+func (c *httpClient) ListProbeTemplates() ([]ProbeTemplate, error) {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resProbes,
+	}
+	result := []ProbeTemplate{}
+	err := c.httpGet(path, &v2Response{Data: v2ResponseData{Result: &result}})
+	return result, err
+}
+
+// UploadProbeTemplate adds a JMC agent probe template, in XML format, to Cryostat
+// under the provided name
+func (c *httpClient) UploadProbeTemplate(templateName string, template []byte) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resProbes,
+		name:     &templateName,
+	}
+	files := []multipartFile{
+		{field: fieldProbeTemplate, fileName: templateName + ".xml", content: template},
+	}
+	return c.httpPostMultipart(path, files, nil)
+}
+
+// DeleteProbeTemplate removes a probe template previously added using UploadProbeTemplate.
+// Deleting a template that does not exist is not considered an error.
+func (c *httpClient) DeleteProbeTemplate(templateName string) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resProbes,
+		name:     &templateName,
+	}
+	err := c.httpDelete(path, nil)
+	if statusErr, ok := err.(*StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// ApplyProbes injects the probes from the named probe template into the target JVM,
+// which must have the JMC agent loaded
+func (c *httpClient) ApplyProbes(target *TargetAddress, templateName string) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resProbes,
+		target:   target,
+		name:     &templateName,
+	}
+	return c.httpPostForm(path, url.Values{}, nil)
+}
+
+// RemoveProbes removes all probes injected into the target JVM
+func (c *httpClient) RemoveProbes(target *TargetAddress) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resProbes,
+		target:   target,
+	}
+	return c.httpDelete(path, nil)
+}
+
+// ListProbes returns the probes currently active in the target JVM
+func (c *httpClient) ListProbes(target *TargetAddress) ([]ProbeEvent, error) {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resProbes,
+		target:   target,
+	}
+	result := []ProbeEvent{}
+	err := c.httpGet(path, &v2Response{Data: v2ResponseData{Result: &result}})
+	return result, err
+}
+
+// GetRule returns the automated rule with the provided name,
+// or nil if no such rule exists
+func (c *httpClient) GetRule(ruleName string) (*Rule, error) {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resRules,
+		name:     &ruleName,
+	}
+	result := &Rule{}
+	err := c.httpGet(path, &v2Response{Data: v2ResponseData{Result: result}})
+	if statusErr, ok := err.(*StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateRule adds an automated rule to Cryostat
+func (c *httpClient) CreateRule(rule *Rule) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resRules,
+	}
+	values := url.Values{}
+	values.Add(attrName, rule.Name)
+	values.Add(attrDescription, rule.Description)
+	values.Add(attrMatchExpr, rule.MatchExpression)
+	values.Add(attrEventSpecifier, rule.EventSpecifier)
+	values.Add(attrArchivalPeriod, strconv.FormatInt(rule.ArchivalPeriodSeconds, 10))
+	values.Add(attrPreserved, strconv.FormatInt(int64(rule.PreservedArchives), 10))
+	values.Add(attrMaxAge, strconv.FormatInt(rule.MaxAgeSeconds, 10))
+	values.Add(attrMaxSize, strconv.FormatInt(rule.MaxSizeBytes, 10))
+	values.Add(attrEnabled, strconv.FormatBool(rule.Enabled))
+	return c.httpPostForm(path, values, nil)
+}
+
+// DeleteRule removes an automated rule from Cryostat. Deleting a rule
+// that does not exist is not considered an error.
+func (c *httpClient) DeleteRule(ruleName string) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resRules,
+		name:     &ruleName,
+	}
+	err := c.httpDelete(path, nil)
+	if statusErr, ok := err.(*StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// UploadClientCertificate configures the PEM-encoded client certificate and
+// private key that Cryostat presents when connecting to the target JVM
+func (c *httpClient) UploadClientCertificate(target *TargetAddress, cert []byte, key []byte) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resCertificates,
+		target:   target,
+	}
+	files := []multipartFile{
+		{field: fieldCert, fileName: corev1.TLSCertKey, content: cert},
+		{field: fieldKey, fileName: corev1.TLSPrivateKeyKey, content: key},
+	}
+	return c.httpPostMultipart(path, files, nil)
+}
+
+// GetMBeanMetrics returns runtime information about the target JVM
+func (c *httpClient) GetMBeanMetrics(target *TargetAddress) (*MBeanMetrics, error) {
+	path := &apiPath{
+		version:  apiV2_2,
+		resource: resGraphQL,
+	}
+	request := &graphQLRequest{
+		Query: mbeanMetricsQuery,
+		Variables: map[string]interface{}{
+			"connectUrl": target.ServiceURL(),
+		},
+	}
+	result := &mbeanMetricsQueryResult{}
+	err := c.httpPostGraphQL(path, request, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.TargetNodes) == 0 {
+		return nil, fmt.Errorf("target %s not found", target.ServiceURL())
+	}
+	return &result.TargetNodes[0].MBeanMetrics, nil
+}
+
+// Selects the MBean metrics used to populate a FlightRecorder's JVM details
+const mbeanMetricsQuery = `query MBeanMetricsForTarget($connectUrl: String) {
+  targetNodes(filter: { name: $connectUrl }) {
+    mbeanMetrics {
+      runtime { vmVersion vmVendor startTime uptime inputArguments }
+      memory { heapMemoryUsage { init used committed max } }
+    }
+  }
+}`
+
+type mbeanMetricsQueryResult struct {
+	TargetNodes []struct {
+		MBeanMetrics MBeanMetrics `json:"mbeanMetrics"`
+	} `json:"targetNodes"`
+}
+
+// CreateCustomTarget registers a target that Cryostat cannot discover on its own.
+// The target is identified in Cryostat by its ServiceURL.
+func (c *httpClient) CreateCustomTarget(target *TargetAddress, alias string) error {
+	path := &apiPath{
+		version:  apiV2,
+		resource: resTargets,
+	}
+	values := url.Values{}
+	values.Add(attrConnectURL, target.ServiceURL())
+	values.Add(attrAlias, alias)
+	return c.httpPostForm(path, values, nil)
+}
+
+// DeleteCustomTarget removes a target previously registered using CreateCustomTarget.
+// Deleting a target that is not registered is not considered an error.
+func (c *httpClient) DeleteCustomTarget(target *TargetAddress) error {
+	connectURL := target.ServiceURL()
+	path := &apiPath{
+		version:  apiV2,
+		resource: resTargets,
+		name:     &connectURL,
+	}
+	err := c.httpDelete(path, nil)
+	if statusErr, ok := err.(*StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
 func (c *httpClient) httpGet(path *apiPath, result interface{}) error {
 	return c.sendRequest(http.MethodGet, path, nil, nil, result)
 }
@@ -281,6 +596,71 @@ func (c *httpClient) httpPostForm(path *apiPath, formData url.Values, result int
 		&contentType, result)
 }
 
+type multipartFile struct {
+	field    string
+	fileName string
+	content  []byte
+}
+
+func (c *httpClient) httpPostMultipart(path *apiPath, files []multipartFile, result interface{}) error {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.field, file.fileName)
+		if err != nil {
+			return err
+		}
+		_, err = part.Write(file.content)
+		if err != nil {
+			return err
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return err
+	}
+	contentType := writer.FormDataContentType()
+	return c.sendRequest(http.MethodPost, path, buf, &contentType, result)
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// httpPostGraphQL sends a query to Cryostat's GraphQL endpoint and decodes
+// the "data" field of the response into result. GraphQL reports errors in the
+// body of a successful response, so these are returned as an error.
+func (c *httpClient) httpPostGraphQL(path *apiPath, request *graphQLRequest, result interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	contentType := "application/json"
+	response := &graphQLResponse{Data: result}
+	err = c.sendRequest(http.MethodPost, path, bytes.NewReader(body), &contentType, response)
+	if err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		messages := make([]string, 0, len(response.Errors))
+		for _, graphQLErr := range response.Errors {
+			messages = append(messages, graphQLErr.Message)
+		}
+		return fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+	return nil
+}
+
 func (c *httpClient) httpDelete(path *apiPath, result interface{}) error {
 	return c.sendRequest(http.MethodDelete, path, nil, nil, result)
 }
@@ -329,9 +709,13 @@ func (c *httpClient) sendRequest(method string, path *apiPath, body io.Reader, c
 			httpLogger.Error(err, "failed to read error message from response body")
 			return err
 		}
-		err = fmt.Errorf("server returned status: %s", resp.Status)
-		httpLogger.Error(err, "request failed", "message", string(errMsg))
-		return err
+		statusErr := &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Message:    string(errMsg),
+		}
+		httpLogger.Error(statusErr, "request failed", "message", statusErr.Message)
+		return statusErr
 	}
 	httpLogger.Info("request succeeded")
 
@@ -365,18 +749,23 @@ func decodeResponse(body io.Reader, result interface{}, httpLogger logr.Logger)
 }
 
 func (p *apiPath) URL() (*url.URL, error) {
+	version := p.version
+	if len(version) == 0 {
+		version = apiV1
+	}
 	// Build path based on what fields are defined in the receiver
 	var strPath string
 	if p.target != nil {
 		if p.name != nil {
-			strPath = fmt.Sprintf("/api/v1/targets/%s/%s/%s", url.PathEscape(p.target.String()), p.resource, *p.name)
+			strPath = fmt.Sprintf("/api/%s/targets/%s/%s/%s", version, url.PathEscape(p.target.String()), p.resource,
+				url.PathEscape(*p.name))
 		} else {
-			strPath = fmt.Sprintf("/api/v1/targets/%s/%s", url.PathEscape(p.target.String()), p.resource)
+			strPath = fmt.Sprintf("/api/%s/targets/%s/%s", version, url.PathEscape(p.target.String()), p.resource)
 		}
 	} else if p.name != nil {
-		strPath = fmt.Sprintf("/api/v1/%s/%s", p.resource, *p.name)
+		strPath = fmt.Sprintf("/api/%s/%s/%s", version, p.resource, url.PathEscape(*p.name))
 	} else {
-		strPath = fmt.Sprintf("/api/v1/%s", p.resource)
+		strPath = fmt.Sprintf("/api/%s/%s", version, p.resource)
 	}
 	return url.Parse(strPath)
 }
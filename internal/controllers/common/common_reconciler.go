@@ -53,6 +53,10 @@ import (
 
 var log = logf.Log.WithName("common_reconciler")
 
+// CustomTargetKind is the Kind used in a FlightRecorder's target reference
+// when it refers to a CustomTarget
+const CustomTargetKind = "CustomTarget"
+
 // ReconcilerConfig contains configuration used to customize a Reconciler
 // built with NewReconciler
 type ReconcilerConfig struct {
@@ -73,6 +77,8 @@ type Reconciler interface {
 	FindCryostat(ctx context.Context, namespace string) (*operatorv1beta1.Cryostat, error)
 	GetCryostatClient(ctx context.Context, namespace string, jmxAuth *operatorv1beta1.JMXAuthSecret) (cryostatClient.CryostatClient, error)
 	GetPodTarget(targetPod *corev1.Pod, jmxPort int32) (*cryostatClient.TargetAddress, error)
+	GetCustomTarget(target *operatorv1beta1.CustomTarget) (*cryostatClient.TargetAddress, error)
+	GetFlightRecorderTarget(ctx context.Context, fr *operatorv1beta1.FlightRecorder) (*cryostatClient.TargetAddress, error)
 	ReconcilerTLS
 }
 
@@ -114,7 +120,7 @@ func (r *commonReconciler) GetCryostatClient(ctx context.Context, namespace stri
 	// Get CA certificate if TLS is enabled
 	var caCert []byte
 	protocol := "http"
-	if r.IsCertManagerEnabled(cryostat) {
+	if r.IsTLSEnabled(cryostat) {
 		caCert, err = r.GetCryostatCABytes(ctx, cryostat)
 		if err != nil {
 			return nil, err
@@ -169,6 +175,75 @@ func (r *commonReconciler) GetPodTarget(targetPod *corev1.Pod, jmxPort int32) (*
 	}, nil
 }
 
+// GetCustomTarget returns a TargetAddress for the JVM described by a CustomTarget
+func (r *commonReconciler) GetCustomTarget(target *operatorv1beta1.CustomTarget) (*cryostatClient.TargetAddress, error) {
+	spec := target.Spec
+	if spec.ConnectURL != nil {
+		if spec.Host != nil || spec.Port != nil {
+			return nil, fmt.Errorf("CustomTarget %s must not specify both connectUrl and host/port", target.Name)
+		}
+		return &cryostatClient.TargetAddress{
+			ConnectURL: *spec.ConnectURL,
+		}, nil
+	}
+	if spec.Host == nil || spec.Port == nil {
+		return nil, fmt.Errorf("CustomTarget %s must specify either connectUrl or both host and port", target.Name)
+	}
+	return &cryostatClient.TargetAddress{
+		Host: *spec.Host,
+		Port: *spec.Port,
+	}, nil
+}
+
+// GetFlightRecorderTarget returns a TargetAddress for the JVM that a FlightRecorder
+// refers to, which may either be a Pod or a CustomTarget
+func (r *commonReconciler) GetFlightRecorderTarget(ctx context.Context,
+	fr *operatorv1beta1.FlightRecorder) (*cryostatClient.TargetAddress, error) {
+	targetRef := fr.Status.Target
+	if targetRef == nil {
+		return nil, fmt.Errorf("FlightRecorder %s has no target", fr.Name)
+	}
+	key := types.NamespacedName{Namespace: targetRef.Namespace, Name: targetRef.Name}
+
+	if targetRef.Kind == CustomTargetKind {
+		target := &operatorv1beta1.CustomTarget{}
+		err := r.Client.Get(ctx, key, target)
+		if err != nil {
+			return nil, err
+		}
+		return r.GetCustomTarget(target)
+	}
+
+	// Look up pod corresponding to this FlightRecorder object
+	targetPod := &corev1.Pod{}
+	err := r.Client.Get(ctx, key, targetPod)
+	if err != nil {
+		return nil, err
+	}
+	target, err := r.GetPodTarget(targetPod, fr.Status.Port)
+	if err != nil {
+		return nil, err
+	}
+	target.Scheme = schemeForProtocol(fr.Status.Protocol)
+	return target, nil
+}
+
+// schemeForProtocol returns the URL scheme Cryostat uses to address a target
+// with the given protocol, or an empty string for JMX targets
+func schemeForProtocol(protocol operatorv1beta1.TargetProtocol) string {
+	switch protocol {
+	case operatorv1beta1.TargetProtocolHTTP:
+		return "http"
+	case operatorv1beta1.TargetProtocolHTTPS:
+		return "https"
+	default:
+		return ""
+	}
+}
+
+// ErrCryostatNotFound is returned by FindCryostat when no Cryostat has been created
+var ErrCryostatNotFound = errors.New("No Cryostat objects found")
+
 func (r *commonReconciler) FindCryostat(ctx context.Context, namespace string) (*operatorv1beta1.Cryostat, error) {
 	// TODO Consider how to find Cryostat object if this operator becomes cluster-scoped
 	// Look up the Cryostat object for this operator, which will help us find its services
@@ -178,7 +253,7 @@ func (r *commonReconciler) FindCryostat(ctx context.Context, namespace string) (
 		return nil, err
 	}
 	if len(cryostatList.Items) == 0 {
-		return nil, errors.New("No Cryostat objects found")
+		return nil, ErrCryostatNotFound
 	} else if len(cryostatList.Items) > 1 {
 		// Does not seem like a proper use-case
 		log.Info("More than one Cryostat object found in namespace, using only the first one listed",
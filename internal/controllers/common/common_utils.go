@@ -39,8 +39,11 @@ package common
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // CryostatClientFactory provides a method for creating Cryostat clients
@@ -72,3 +75,13 @@ func (o *defaultOSUtils) GetEnv(name string) string {
 func (o *defaultOSUtils) GetFileContents(path string) ([]byte, error) {
 	return ioutil.ReadFile(path)
 }
+
+// RequeueIfNotReady requeues the request after a short delay if the error is
+// ErrCertNotReady, and otherwise returns the error to be retried with backoff.
+func RequeueIfNotReady(log logr.Logger, err error) (reconcile.Result, error) {
+	if err == ErrCertNotReady {
+		log.Info("Waiting for CA certificate")
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	return reconcile.Result{}, err
+}
@@ -0,0 +1,138 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resource_definitions
+
+import (
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const defaultReportsCPUUtilization int32 = 80
+
+// NewReportsHorizontalPodAutoscaler returns a HorizontalPodAutoscaler that scales
+// the reports deployment, using the autoscaling options of the Cryostat
+func NewReportsHorizontalPodAutoscaler(cr *operatorv1beta1.Cryostat) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	autoscaling := cr.Spec.ReportOptions.Autoscaling
+	minReplicas := getReportsMinReplicas(autoscaling)
+
+	metrics := []autoscalingv2beta2.MetricSpec{}
+	if autoscaling.TargetCPUUtilizationPercentage != nil || len(autoscaling.Metrics) == 0 {
+		utilization := defaultReportsCPUUtilization
+		if autoscaling.TargetCPUUtilizationPercentage != nil {
+			utilization = *autoscaling.TargetCPUUtilizationPercentage
+		}
+		metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+	for _, metric := range autoscaling.Metrics {
+		metrics = append(metrics, *metric.DeepCopy())
+	}
+
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-reports",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":       cr.Name,
+				"component": "reports",
+			},
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       cr.Name + "-reports",
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+// NewReportsPodDisruptionBudget returns a PodDisruptionBudget for the report
+// sidecar replicas, using the options of the Cryostat
+func NewReportsPodDisruptionBudget(cr *operatorv1beta1.Cryostat) *policyv1beta1.PodDisruptionBudget {
+	options := cr.Spec.ReportOptions.PodDisruptionBudget
+	spec := policyv1beta1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app":       cr.Name,
+				"kind":      "cryostat",
+				"component": "reports",
+			},
+		},
+	}
+	if options.MinAvailable == nil && options.MaxUnavailable == nil {
+		minAvailable := intstr.FromInt(1)
+		spec.MinAvailable = &minAvailable
+	} else {
+		spec.MinAvailable = options.MinAvailable
+		spec.MaxUnavailable = options.MaxUnavailable
+	}
+
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-reports",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":       cr.Name,
+				"component": "reports",
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func getReportsMinReplicas(autoscaling *operatorv1beta1.ReportsAutoscalingConfiguration) int32 {
+	if autoscaling.MinReplicas != nil {
+		return *autoscaling.MinReplicas
+	}
+	return 1
+}
@@ -0,0 +1,298 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resource_definitions
+
+import (
+	"path"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	storageVolumeName        = "cryostat-storage"
+	storageVolumeMountPath   = "/cryostat"
+	backupVolumeName         = "backup"
+	backupVolumeMountPath    = "/backup"
+	migrationVolumeName      = "migration"
+	migrationVolumeMountPath = "/migration"
+	// Number of times to retry a failed backup or restore pod
+	storageJobBackoffLimit int32 = 2
+)
+
+// VolumeSnapshotAPIGroup is the API group of the VolumeSnapshot resource
+const VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+
+// RestoreSource describes where a CryostatRestore copies its data from
+type RestoreSource struct {
+	// Name of the persistent volume claim containing the backup
+	ClaimName string
+	// Path to a backup archive within the volume. If empty, the directories
+	// are copied from the root of the volume instead.
+	ArchivePath string
+}
+
+// GetBackupArchivePath returns the path, relative to the root of the destination
+// volume, of the archive written by a CryostatBackup
+func GetBackupArchivePath(backup *operatorv1beta1.CryostatBackup) string {
+	return path.Join(backup.Spec.Destination.PVC.SubPath, backup.Name+".tar.gz")
+}
+
+// NewJobForBackup returns a Job that writes a compressed archive of the provided
+// directories of Cryostat's persistent volume to the backup's destination volume
+func NewJobForBackup(backup *operatorv1beta1.CryostatBackup, cr *operatorv1beta1.Cryostat,
+	paths []operatorv1beta1.StoragePath, image string, fsGroup int64) *batchv1.Job {
+	dest := backup.Spec.Destination.PVC
+	// Directories that Cryostat has not created yet are skipped
+	args := []string{"-czf", path.Join(backupVolumeMountPath, backup.Name+".tar.gz"), "--ignore-failed-read",
+		"-C", storageVolumeMountPath}
+	for _, p := range paths {
+		args = append(args, string(p))
+	}
+	container := corev1.Container{
+		Name:            "backup",
+		Image:           image,
+		ImagePullPolicy: getPullPolicy(image),
+		Command:         []string{"tar"},
+		Args:            args,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      storageVolumeName,
+				MountPath: storageVolumeMountPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      backupVolumeName,
+				MountPath: backupVolumeMountPath,
+				SubPath:   dest.SubPath,
+			},
+		},
+	}
+	volumes := []corev1.Volume{
+		newClaimVolume(storageVolumeName, GetStorageClaimName(cr), true),
+		newClaimVolume(backupVolumeName, dest.ClaimName, false),
+	}
+	return newStorageJob(backup.Name+"-backup", backup.Namespace, cr, container, volumes, fsGroup)
+}
+
+// NewJobForRestore returns a Job that copies the provided directories of a backup
+// into Cryostat's persistent volume
+func NewJobForRestore(restore *operatorv1beta1.CryostatRestore, cr *operatorv1beta1.Cryostat,
+	source *RestoreSource, paths []operatorv1beta1.StoragePath, image string, fsGroup int64) *batchv1.Job {
+	var command []string
+	var args []string
+	if len(source.ArchivePath) > 0 {
+		// Extract each directory present in the archive. The backup skips directories that
+		// did not exist at the time, and tar fails when asked for a member it cannot find.
+		command = []string{"/bin/sh", "-c"}
+		args = []string{`archive="$1"; shift; members=""; for dir in "$@"; do ` +
+			`if tar -tzf "$archive" "$dir" > /dev/null 2>&1; then members="$members $dir"; fi; done; ` +
+			`if [ -n "$members" ]; then tar -xzf "$archive" -C "` + storageVolumeMountPath + `" $members; fi`,
+			"restore", path.Join(backupVolumeMountPath, source.ArchivePath)}
+		for _, p := range paths {
+			args = append(args, string(p))
+		}
+	} else {
+		// Copy each directory present in the volume provisioned from the snapshot
+		command = []string{"/bin/sh", "-c"}
+		args = []string{`for dir in "$@"; do if [ -e "` + backupVolumeMountPath + `/$dir" ]; then cp -a "` +
+			backupVolumeMountPath + `/$dir" "` + storageVolumeMountPath + `/" || exit 1; fi; done`, "restore"}
+		for _, p := range paths {
+			args = append(args, string(p))
+		}
+	}
+	container := corev1.Container{
+		Name:            "restore",
+		Image:           image,
+		ImagePullPolicy: getPullPolicy(image),
+		Command:         command,
+		Args:            args,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      storageVolumeName,
+				MountPath: storageVolumeMountPath,
+			},
+			{
+				Name:      backupVolumeName,
+				MountPath: backupVolumeMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+	volumes := []corev1.Volume{
+		newClaimVolume(storageVolumeName, GetStorageClaimName(cr), false),
+		newClaimVolume(backupVolumeName, source.ClaimName, true),
+	}
+	return newStorageJob(restore.Name+"-restore", restore.Namespace, cr, container, volumes, fsGroup)
+}
+
+// NewJobForStorageMigration returns a Job that copies the contents of Cryostat's
+// persistent volume to the persistent volume claim with the provided name
+func NewJobForStorageMigration(cr *operatorv1beta1.Cryostat, targetClaim string, image string,
+	fsGroup int64) *batchv1.Job {
+	container := corev1.Container{
+		Name:            "migration",
+		Image:           image,
+		ImagePullPolicy: getPullPolicy(image),
+		Command:         []string{"cp"},
+		Args:            []string{"-a", storageVolumeMountPath + "/.", migrationVolumeMountPath + "/"},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      storageVolumeName,
+				MountPath: storageVolumeMountPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      migrationVolumeName,
+				MountPath: migrationVolumeMountPath,
+			},
+		},
+	}
+	volumes := []corev1.Volume{
+		newClaimVolume(storageVolumeName, GetStorageClaimName(cr), true),
+		newClaimVolume(migrationVolumeName, targetClaim, false),
+	}
+	return newStorageJob(targetClaim+"-migration", cr.Namespace, cr, container, volumes, fsGroup)
+}
+
+// NewPersistentVolumeClaimForRestore returns a persistent volume claim provisioned
+// from the VolumeSnapshot of a backup, to be used as the source of a restore
+func NewPersistentVolumeClaimForRestore(restore *operatorv1beta1.CryostatRestore,
+	storage *corev1.PersistentVolumeClaim, snapshotName string, size resource.Quantity) *corev1.PersistentVolumeClaim {
+	apiGroup := VolumeSnapshotAPIGroup
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restore.Name + "-restore-source",
+			Namespace: restore.Namespace,
+			Labels: map[string]string{
+				"app": storage.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      storage.Spec.AccessModes,
+			StorageClassName: storage.Spec.StorageClassName,
+			VolumeMode:       storage.Spec.VolumeMode,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VolumeSnapshot",
+				Name:     snapshotName,
+			},
+		},
+	}
+}
+
+func newStorageJob(name string, namespace string, cr *operatorv1beta1.Cryostat, container corev1.Container,
+	volumes []corev1.Volume, fsGroup int64) *batchv1.Job {
+	labels := map[string]string{
+		"app":       cr.Name,
+		"component": "storage-" + container.Name,
+	}
+	containerSc := newDefaultContainerSecurityContext()
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.CoreSecurityContext != nil {
+		containerSc = cr.Spec.SecurityOptions.CoreSecurityContext
+	}
+	container.SecurityContext = containerSc
+	backoffLimit := storageJobBackoffLimit
+
+	podSpec := corev1.PodSpec{
+		RestartPolicy:   corev1.RestartPolicyNever,
+		Containers:      []corev1.Container{container},
+		Volumes:         volumes,
+		SecurityContext: newCorePodSecurityContext(cr, fsGroup),
+	}
+	applySchedulingOptions(&podSpec, cr.Spec.SchedulingOptions)
+	if podSpec.Affinity == nil {
+		// Prefer the node running Cryostat, so that a ReadWriteOnce volume
+		// can be mounted by both pods
+		podSpec.Affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       cr.Name,
+									"kind":      "cryostat",
+									"component": "cryostat",
+								},
+							},
+							TopologyKey: corev1.LabelHostname,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+func newClaimVolume(name string, claimName string, readOnly bool) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+				ReadOnly:  readOnly,
+			},
+		},
+	}
+}
@@ -96,7 +96,7 @@ func NewCryostatCACert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
 }
 
 func NewCryostatCert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
-	return &certv1.Certificate{
+	cert := &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
 			Namespace: cr.Namespace,
@@ -120,19 +120,19 @@ func NewCryostatCert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
 					},
 				},
 			},
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			IssuerRef: newCertificateIssuerRef(cr),
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 				certv1.UsageClientAuth,
 			),
 		},
 	}
+	applyCertificateOptions(cr, cert)
+	return cert
 }
 
 func NewGrafanaCert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
-	return &certv1.Certificate{
+	cert := &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-grafana",
 			Namespace: cr.Namespace,
@@ -146,18 +146,18 @@ func NewGrafanaCert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
 				healthCheckHostname,
 			},
 			SecretName: cr.Name + "-grafana-tls",
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			IssuerRef:  newCertificateIssuerRef(cr),
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 			),
 		},
 	}
+	applyCertificateOptions(cr, cert)
+	return cert
 }
 
 func NewReportsCert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
-	return &certv1.Certificate{
+	cert := &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-reports",
 			Namespace: cr.Namespace,
@@ -170,12 +170,50 @@ func NewReportsCert(cr *operatorv1beta1.Cryostat) *certv1.Certificate {
 				fmt.Sprintf("%s-reports.%s.svc.cluster.local", cr.Name, cr.Namespace),
 			},
 			SecretName: cr.Name + "-reports-tls",
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			IssuerRef:  newCertificateIssuerRef(cr),
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 			),
 		},
 	}
+	applyCertificateOptions(cr, cert)
+	return cert
+}
+
+// newCertificateIssuerRef returns a reference to the issuer that signs the
+// certificates for Cryostat components. This is either an issuer specified
+// in the Cryostat CR, or the CA issuer created for this Cryostat.
+func newCertificateIssuerRef(cr *operatorv1beta1.Cryostat) certMeta.ObjectReference {
+	if UsesExternalIssuer(cr) {
+		ref := cr.Spec.CertificateOptions.IssuerRef
+		return certMeta.ObjectReference{
+			Name:  ref.Name,
+			Kind:  ref.Kind,
+			Group: ref.Group,
+		}
+	}
+	return certMeta.ObjectReference{
+		Name: cr.Name + "-ca",
+	}
+}
+
+func applyCertificateOptions(cr *operatorv1beta1.Cryostat, cert *certv1.Certificate) {
+	options := cr.Spec.CertificateOptions
+	if options == nil {
+		return
+	}
+	cert.Spec.Duration = options.Duration
+	cert.Spec.RenewBefore = options.RenewBefore
+	if options.PrivateKey != nil {
+		cert.Spec.PrivateKey = &certv1.CertificatePrivateKey{
+			Algorithm: certv1.PrivateKeyAlgorithm(options.PrivateKey.Algorithm),
+			Size:      options.PrivateKey.Size,
+		}
+	}
+}
+
+// UsesExternalIssuer returns whether the Cryostat CR specifies an existing
+// cert-manager issuer, in place of the self-signed CA created by the operator
+func UsesExternalIssuer(cr *operatorv1beta1.Cryostat) bool {
+	return cr.Spec.CertificateOptions != nil && cr.Spec.CertificateOptions.IssuerRef != nil
 }
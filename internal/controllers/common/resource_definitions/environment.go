@@ -0,0 +1,132 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resource_definitions
+
+import (
+	"reflect"
+	"strings"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// javaToolOptionsEnv is read by the JVM itself, regardless of how
+// the container launches Java
+const javaToolOptionsEnv = "JAVA_TOOL_OPTIONS"
+
+// applyJavaEnvironment adds the JVM options and extra environment
+// variables requested for a Java container to the container
+func applyJavaEnvironment(container *corev1.Container, env *operatorv1beta1.JavaContainerEnvironment) {
+	if env == nil {
+		return
+	}
+	if len(env.JVMOptions) > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  javaToolOptionsEnv,
+			Value: strings.Join(env.JVMOptions, " "),
+		})
+	}
+	applyEnvironment(container, &env.ContainerEnvironment)
+}
+
+// applyEnvironment adds the extra environment variables requested for a
+// container to the container. Variables already set by the operator are
+// not overridden, and extra sources of environment variables are placed
+// before the operator's own sources so they cannot override them either.
+func applyEnvironment(container *corev1.Container, env *operatorv1beta1.ContainerEnvironment) {
+	if env == nil {
+		return
+	}
+	for _, extra := range env.ExtraEnv {
+		if findEnvVar(container.Env, extra.Name) == nil {
+			container.Env = append(container.Env, *extra.DeepCopy())
+		}
+	}
+	if len(env.ExtraEnvFrom) > 0 {
+		envFrom := make([]corev1.EnvFromSource, 0, len(env.ExtraEnvFrom)+len(container.EnvFrom))
+		for _, extra := range env.ExtraEnvFrom {
+			envFrom = append(envFrom, *extra.DeepCopy())
+		}
+		container.EnvFrom = append(envFrom, container.EnvFrom...)
+	}
+}
+
+// FindEnvConflicts returns the names of the extra environment variables
+// requested in the Cryostat CR that were not applied to the containers in
+// the provided pod specs, because they conflict with variables set by the
+// operator. The result is keyed by container name.
+func FindEnvConflicts(cr *operatorv1beta1.Cryostat, podSpecs ...*corev1.PodSpec) map[string][]string {
+	envs := map[string]*operatorv1beta1.ContainerEnvironment{}
+	if options := cr.Spec.EnvironmentOptions; options != nil {
+		if options.CoreEnvironment != nil {
+			envs[cr.Name] = &options.CoreEnvironment.ContainerEnvironment
+		}
+		if options.DataSourceEnvironment != nil {
+			envs[cr.Name+"-jfr-datasource"] = &options.DataSourceEnvironment.ContainerEnvironment
+		}
+		envs[cr.Name+"-grafana"] = options.GrafanaEnvironment
+	}
+	if cr.Spec.ReportOptions != nil && cr.Spec.ReportOptions.EnvironmentOptions != nil {
+		envs[cr.Name+"-reports"] = &cr.Spec.ReportOptions.EnvironmentOptions.ContainerEnvironment
+	}
+
+	conflicts := map[string][]string{}
+	for _, podSpec := range podSpecs {
+		for _, container := range podSpec.Containers {
+			env := envs[container.Name]
+			if env == nil {
+				continue
+			}
+			for _, extra := range env.ExtraEnv {
+				found := findEnvVar(container.Env, extra.Name)
+				if found == nil || !reflect.DeepEqual(*found, extra) {
+					conflicts[container.Name] = append(conflicts[container.Name], extra.Name)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+func findEnvVar(envs []corev1.EnvVar, name string) *corev1.EnvVar {
+	for i := range envs {
+		if envs[i].Name == name {
+			return &envs[i]
+		}
+	}
+	return nil
+}
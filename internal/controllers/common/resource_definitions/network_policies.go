@@ -0,0 +1,220 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resource_definitions
+
+import (
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	dnsPort             int32 = 53
+	apiServerPort       int32 = 443
+	apiServerTargetPort int32 = 6443
+)
+
+// NamespaceNameLabel is the label that Kubernetes applies to every namespace,
+// containing its name
+const NamespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NewCoreNetworkPolicy returns a NetworkPolicy for the Cryostat pod. Its web server
+// and Grafana only admit traffic from the ingress controller peers, targets running
+// the Cryostat agent, and any additional peers. The web server also always admits
+// the operator's namespace, since the operator uses Cryostat's API. Cryostat may
+// connect to targets on the provided ports, the reports generator, DNS and the
+// Kubernetes API server. Custom targets outside the target namespaces are only
+// reachable through additional egress peers.
+func NewCoreNetworkPolicy(cr *operatorv1beta1.Cryostat, ingressControllerPeers []netv1.NetworkPolicyPeer,
+	targetPorts []netv1.NetworkPolicyPort, operatorNamespace string) *netv1.NetworkPolicy {
+	options := cr.Spec.NetworkPolicyOptions
+	targetPeer := newTargetNamespacesPeer(cr)
+	operatorPeer := netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{NamespaceNameLabel: operatorNamespace},
+		},
+	}
+
+	webPeers := append([]netv1.NetworkPolicyPeer{}, ingressControllerPeers...)
+	webPeers = append(webPeers, operatorPeer, targetPeer)
+	webPeers = append(webPeers, options.AdditionalIngressPeers...)
+	ingress := []netv1.NetworkPolicyIngressRule{
+		{
+			Ports: []netv1.NetworkPolicyPort{newTCPPolicyPort(cryostatHTTPContainerPort)},
+			From:  webPeers,
+		},
+	}
+	if !cr.Spec.Minimal {
+		grafanaPeers := append([]netv1.NetworkPolicyPeer{}, ingressControllerPeers...)
+		grafanaPeers = append(grafanaPeers, options.AdditionalIngressPeers...)
+		ingress = append(ingress, netv1.NetworkPolicyIngressRule{
+			Ports: []netv1.NetworkPolicyPort{newTCPPolicyPort(grafanaContainerPort)},
+			From:  grafanaPeers,
+		})
+	}
+
+	udp := corev1.ProtocolUDP
+	egress := []netv1.NetworkPolicyEgressRule{
+		{
+			Ports: targetPorts,
+			To:    []netv1.NetworkPolicyPeer{targetPeer},
+		},
+		{
+			Ports: []netv1.NetworkPolicyPort{
+				{
+					Protocol: &udp,
+					Port:     &intstr.IntOrString{IntVal: dnsPort},
+				},
+				newTCPPolicyPort(dnsPort),
+			},
+		},
+		{
+			Ports: []netv1.NetworkPolicyPort{
+				newTCPPolicyPort(apiServerPort),
+				newTCPPolicyPort(apiServerTargetPort),
+			},
+		},
+	}
+	if IsReportsEnabled(cr) {
+		egress = append(egress, netv1.NetworkPolicyEgressRule{
+			Ports: []netv1.NetworkPolicyPort{newTCPPolicyPort(reportsContainerPort)},
+			To: []netv1.NetworkPolicyPeer{
+				{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app":       cr.Name,
+							"component": "reports",
+						},
+					},
+				},
+			},
+		})
+	}
+	if len(options.AdditionalEgressPeers) > 0 {
+		egress = append(egress, netv1.NetworkPolicyEgressRule{
+			To: options.AdditionalEgressPeers,
+		})
+	}
+
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":       cr.Name,
+				"component": "cryostat",
+			},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       cr.Name,
+					"component": "cryostat",
+				},
+			},
+			Ingress: ingress,
+			Egress:  egress,
+			PolicyTypes: []netv1.PolicyType{
+				netv1.PolicyTypeIngress,
+				netv1.PolicyTypeEgress,
+			},
+		},
+	}
+}
+
+// NewReportsNetworkPolicy returns a NetworkPolicy for the reports generator pods,
+// which only admits traffic from the Cryostat pod
+func NewReportsNetworkPolicy(cr *operatorv1beta1.Cryostat) *netv1.NetworkPolicy {
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-reports",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":       cr.Name,
+				"component": "reports",
+			},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       cr.Name,
+					"component": "reports",
+				},
+			},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{
+					Ports: []netv1.NetworkPolicyPort{newTCPPolicyPort(reportsContainerPort)},
+					From: []netv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       cr.Name,
+									"component": "cryostat",
+								},
+							},
+						},
+					},
+				},
+			},
+			PolicyTypes: []netv1.PolicyType{
+				netv1.PolicyTypeIngress,
+			},
+		},
+	}
+}
+
+// newTargetNamespacesPeer selects pods in the namespaces considered for target
+// discovery, which defaults to all namespaces
+func newTargetNamespacesPeer(cr *operatorv1beta1.Cryostat) netv1.NetworkPolicyPeer {
+	selector := &metav1.LabelSelector{}
+	if cr.Spec.TargetDiscoveryOptions != nil && cr.Spec.TargetDiscoveryOptions.NamespaceSelector != nil {
+		selector = cr.Spec.TargetDiscoveryOptions.NamespaceSelector.DeepCopy()
+	}
+	return netv1.NetworkPolicyPeer{
+		NamespaceSelector: selector,
+	}
+}
+
+func newTCPPolicyPort(port int32) netv1.NetworkPolicyPort {
+	tcp := corev1.ProtocolTCP
+	return netv1.NetworkPolicyPort{
+		Protocol: &tcp,
+		Port:     &intstr.IntOrString{IntVal: port},
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resource_definitions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// ErrPodTemplatePatchInvalid indicates that a pod template override could not be applied
+	ErrPodTemplatePatchInvalid = errors.New("pod template patch is invalid")
+	// ErrPodTemplateOperatorFieldsModified indicates that a pod template override
+	// removes or changes part of the pod template required by the operator
+	ErrPodTemplateOperatorFieldsModified = errors.New("pod template patch modifies fields managed by the operator")
+)
+
+// ApplyPodTemplateOverride returns a copy of the pod template with the override
+// applied to it. An error is returned if the override cannot be applied, or if
+// it removes or changes the containers, volumes, labels or service account
+// configured by the operator.
+func ApplyPodTemplateOverride(template *corev1.PodTemplateSpec,
+	override *operatorv1beta1.PodTemplateOverride) (*corev1.PodTemplateSpec, error) {
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPodTemplatePatchInvalid, err.Error())
+	}
+	original, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	if override.Type != nil && *override.Type == operatorv1beta1.PodTemplatePatchTypeJSON {
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			patched, err = ops.Apply(original)
+		}
+	} else {
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPodTemplatePatchInvalid, err.Error())
+	}
+
+	// Reject fields that are not part of a pod template, rather than silently ignoring them
+	result := &corev1.PodTemplateSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPodTemplatePatchInvalid, err.Error())
+	}
+
+	if err := validatePodTemplateOverride(template, result); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPodTemplateOperatorFieldsModified, err.Error())
+	}
+	return result, nil
+}
+
+// validatePodTemplateOverride checks that the patched pod template still contains
+// everything the operator configured in the original pod template
+func validatePodTemplateOverride(original *corev1.PodTemplateSpec, patched *corev1.PodTemplateSpec) error {
+	for key, val := range original.Labels {
+		if patched.Labels[key] != val {
+			return fmt.Errorf("label \"%s\" must be \"%s\"", key, val)
+		}
+	}
+	if patched.Spec.ServiceAccountName != original.Spec.ServiceAccountName {
+		return fmt.Errorf("service account must be \"%s\"", original.Spec.ServiceAccountName)
+	}
+	for _, volume := range original.Spec.Volumes {
+		found := findVolume(patched.Spec.Volumes, volume.Name)
+		if found == nil || !equality.Semantic.DeepEqual(*found, volume) {
+			return fmt.Errorf("volume \"%s\" must not be removed or changed", volume.Name)
+		}
+	}
+	for _, container := range original.Spec.Containers {
+		found := findContainer(patched.Spec.Containers, container.Name)
+		if found == nil {
+			return fmt.Errorf("container \"%s\" must not be removed", container.Name)
+		}
+		if err := validateContainerOverride(&container, found); err != nil {
+			return fmt.Errorf("container \"%s\": %s", container.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+func validateContainerOverride(original *corev1.Container, patched *corev1.Container) error {
+	if patched.Image != original.Image {
+		return fmt.Errorf("image must be \"%s\"", original.Image)
+	}
+	for _, port := range original.Ports {
+		if findContainerPort(patched.Ports, port.ContainerPort) == nil {
+			return fmt.Errorf("port %d must not be removed", port.ContainerPort)
+		}
+	}
+	for _, env := range original.Env {
+		found := findEnvVar(patched.Env, env.Name)
+		if found == nil || !equality.Semantic.DeepEqual(*found, env) {
+			return fmt.Errorf("environment variable \"%s\" must not be removed or changed", env.Name)
+		}
+	}
+	for _, envFrom := range original.EnvFrom {
+		if !containsEnvFromSource(patched.EnvFrom, &envFrom) {
+			return errors.New("environment variable sources must not be removed")
+		}
+	}
+	for _, mount := range original.VolumeMounts {
+		if !containsVolumeMount(patched.VolumeMounts, &mount) {
+			return fmt.Errorf("volume mount at \"%s\" must not be removed or changed", mount.MountPath)
+		}
+	}
+	return nil
+}
+
+func findVolume(volumes []corev1.Volume, name string) *corev1.Volume {
+	for i := range volumes {
+		if volumes[i].Name == name {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+func findContainerPort(ports []corev1.ContainerPort, port int32) *corev1.ContainerPort {
+	for i := range ports {
+		if ports[i].ContainerPort == port {
+			return &ports[i]
+		}
+	}
+	return nil
+}
+
+func containsEnvFromSource(sources []corev1.EnvFromSource, source *corev1.EnvFromSource) bool {
+	for i := range sources {
+		if equality.Semantic.DeepEqual(sources[i], *source) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVolumeMount(mounts []corev1.VolumeMount, mount *corev1.VolumeMount) bool {
+	for i := range mounts {
+		if equality.Semantic.DeepEqual(mounts[i], *mount) {
+			return true
+		}
+	}
+	return false
+}
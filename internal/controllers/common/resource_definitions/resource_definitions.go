@@ -96,9 +96,46 @@ const (
 	loopbackAddress           string = "127.0.0.1"
 )
 
+// ActiveStorageLabel marks the persistent volume claim currently mounted by a Cryostat,
+// with the name of that Cryostat as its value
+const ActiveStorageLabel = "operator.cryostat.io/active-storage"
+
+// GetStorageClaimName returns the name of the persistent volume claim mounted by
+// Cryostat, which changes when its data is migrated to a new claim
+func GetStorageClaimName(cr *operatorv1beta1.Cryostat) string {
+	if cr.Status.Storage != nil && len(cr.Status.Storage.ClaimName) > 0 {
+		return cr.Status.Storage.ClaimName
+	}
+	return cr.Name
+}
+
+// IsEmptyDirRequested returns whether the Cryostat's spec requests that its data
+// be stored in an EmptyDir
+func IsEmptyDirRequested(cr *operatorv1beta1.Cryostat) bool {
+	return cr.Spec.StorageOptions != nil && cr.Spec.StorageOptions.EmptyDir != nil && cr.Spec.StorageOptions.EmptyDir.Enabled
+}
+
+// UsesEmptyDir returns whether Cryostat stores its data in an EmptyDir. An EmptyDir
+// is only used once no persistent volume claim holding Cryostat's data remains.
+func UsesEmptyDir(cr *operatorv1beta1.Cryostat) bool {
+	return IsEmptyDirRequested(cr) && (cr.Status.Storage == nil || len(cr.Status.Storage.ClaimName) == 0)
+}
+
+// GetMigrationClaimName returns the name of a new persistent volume claim with the
+// provided spec, to which Cryostat's data will be migrated
+func GetMigrationClaimName(cr *operatorv1beta1.Cryostat, spec *corev1.PersistentVolumeClaimSpec) (string, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	// Use a prefix of the spec's SHA256 checksum as a suffix
+	suffix := fmt.Sprintf("%x", sha256.Sum256(specJSON))
+	return cr.Name + "-" + suffix[:8], nil
+}
+
 func NewPersistentVolumeClaimForCR(cr *operatorv1beta1.Cryostat) *corev1.PersistentVolumeClaim {
 	objMeta := metav1.ObjectMeta{
-		Name:      cr.Name,
+		Name:      GetStorageClaimName(cr),
 		Namespace: cr.Namespace,
 	}
 	// Check for PVC config within CR
@@ -119,6 +156,7 @@ func NewPersistentVolumeClaimForCR(cr *operatorv1beta1.Cryostat) *corev1.Persist
 		objMeta.Labels = map[string]string{}
 	}
 	objMeta.Labels["app"] = cr.Name
+	objMeta.Labels[ActiveStorageLabel] = cr.Name
 
 	// Apply any applicable spec defaults. Don't apply a default storage class name, since nil
 	// may be intentionally specified.
@@ -177,11 +215,22 @@ func NewDeploymentForCR(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, image
 	}
 }
 
+// IsReportsEnabled returns whether the Cryostat should deploy report sidecar replicas,
+// rather than generating reports in a subprocess
+func IsReportsEnabled(cr *operatorv1beta1.Cryostat) bool {
+	options := cr.Spec.ReportOptions
+	return options != nil && (options.Replicas > 0 || options.Autoscaling != nil)
+}
+
 func NewDeploymentForReports(cr *operatorv1beta1.Cryostat, imageTags *ImageTags, tls *TLSConfig) *appsv1.Deployment {
 	if cr.Spec.ReportOptions == nil {
 		cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{Replicas: 0}
 	}
 	replicas := cr.Spec.ReportOptions.Replicas
+	if cr.Spec.ReportOptions.Autoscaling != nil {
+		// Start with the minimum number of replicas, and let the autoscaler take over
+		replicas = getReportsMinReplicas(cr.Spec.ReportOptions.Autoscaling)
+	}
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-reports",
@@ -316,6 +365,18 @@ func NewPodForCR(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, imageTags *I
 		}
 	}
 
+	// Add CA certificates of target JVMs, managed by the FlightRecorder controller.
+	// The secret may not exist yet, and changes to it roll out a new pod.
+	optional := true
+	volSources = append(volSources, corev1.VolumeProjection{
+		Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: cr.Name + TargetCASecretNameSuffix,
+			},
+			Optional: &optional,
+		},
+	})
+
 	// Project certificate secrets into deployment
 	certVolume := corev1.Volume{
 		Name: "cert-secrets",
@@ -349,10 +410,7 @@ func NewPodForCR(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, imageTags *I
 		volumes = append(volumes, eventTemplateVolume)
 	}
 
-	// Ensure PV mounts are writable
-	sc := &corev1.PodSecurityContext{
-		FSGroup: &fsGroup,
-	}
+	sc := newCorePodSecurityContext(cr, fsGroup)
 
 	// Use HostAlias for loopback address to allow health checks to
 	// work over HTTPS with hostname added as a SubjectAltName
@@ -364,13 +422,15 @@ func NewPodForCR(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, imageTags *I
 			},
 		},
 	}
-	return &corev1.PodSpec{
+	podSpec := &corev1.PodSpec{
 		ServiceAccountName: cr.Name,
 		Volumes:            volumes,
 		Containers:         containers,
 		SecurityContext:    sc,
 		HostAliases:        hostAliases,
 	}
+	applySchedulingOptions(podSpec, cr.Spec.SchedulingOptions)
+	return podSpec
 }
 
 func NewPodForReports(cr *operatorv1beta1.Cryostat, imageTags *ImageTags, tls *TLSConfig) *corev1.PodSpec {
@@ -462,8 +522,21 @@ func NewPodForReports(cr *operatorv1beta1.Cryostat, imageTags *ImageTags, tls *T
 			Path:   "/health",
 		},
 	}
-	return &corev1.PodSpec{
+	podSc := newDefaultPodSecurityContext()
+	containerSc := newDefaultContainerSecurityContext()
+	if cr.Spec.ReportOptions != nil && cr.Spec.ReportOptions.SecurityOptions != nil {
+		options := cr.Spec.ReportOptions.SecurityOptions
+		if options.PodSecurityContext != nil {
+			podSc = options.PodSecurityContext
+		}
+		if options.ReportsSecurityContext != nil {
+			containerSc = options.ReportsSecurityContext
+		}
+	}
+
+	podSpec := &corev1.PodSpec{
 		ServiceAccountName: cr.Name,
+		SecurityContext:    podSc,
 		Containers: []corev1.Container{
 			{
 				Name:            cr.Name + "-reports",
@@ -483,10 +556,65 @@ func NewPodForReports(cr *operatorv1beta1.Cryostat, imageTags *ImageTags, tls *T
 				StartupProbe: &corev1.Probe{
 					Handler: probeHandler,
 				},
+				SecurityContext: containerSc,
 			},
 		},
 		Volumes: volumes,
 	}
+	if cr.Spec.ReportOptions != nil {
+		applySchedulingOptions(podSpec, cr.Spec.ReportOptions.SchedulingOptions)
+		applyJavaEnvironment(&podSpec.Containers[0], cr.Spec.ReportOptions.EnvironmentOptions)
+	}
+	return podSpec
+}
+
+// newCorePodSecurityContext returns the Security Context for pods that
+// mount Cryostat's persistent volume
+func newCorePodSecurityContext(cr *operatorv1beta1.Cryostat, fsGroup int64) *corev1.PodSecurityContext {
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.PodSecurityContext != nil {
+		return cr.Spec.SecurityOptions.PodSecurityContext
+	}
+	sc := newDefaultPodSecurityContext()
+	// Ensure PV mounts are writable
+	sc.FSGroup = &fsGroup
+	return sc
+}
+
+// newDefaultPodSecurityContext returns a pod Security Context that
+// satisfies the restricted Pod Security Standard
+func newDefaultPodSecurityContext() *corev1.PodSecurityContext {
+	nonRoot := true
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot: &nonRoot,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// newDefaultContainerSecurityContext returns a container Security Context that
+// satisfies the restricted Pod Security Standard
+func newDefaultContainerSecurityContext() *corev1.SecurityContext {
+	privEscalation := false
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &privEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// applySchedulingOptions copies any scheduling constraints from the Cryostat spec
+// into the pod spec
+func applySchedulingOptions(podSpec *corev1.PodSpec, options *operatorv1beta1.SchedulingConfiguration) {
+	if options == nil {
+		return
+	}
+	podSpec.NodeSelector = options.NodeSelector
+	podSpec.Affinity = options.Affinity
+	podSpec.Tolerations = options.Tolerations
+	podSpec.TopologySpreadConstraints = options.TopologySpreadConstraints
+	podSpec.PriorityClassName = options.PriorityClassName
 }
 
 func NewCoreContainer(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, imageTag string,
@@ -747,7 +875,11 @@ func NewCoreContainer(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, imageTa
 			Scheme: livenessProbeScheme,
 		},
 	}
-	return corev1.Container{
+	containerSc := newDefaultContainerSecurityContext()
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.CoreSecurityContext != nil {
+		containerSc = cr.Spec.SecurityOptions.CoreSecurityContext
+	}
+	container := corev1.Container{
 		Name:            cr.Name,
 		Image:           imageTag,
 		ImagePullPolicy: getPullPolicy(imageTag),
@@ -771,7 +903,12 @@ func NewCoreContainer(cr *operatorv1beta1.Cryostat, specs *ServiceSpecs, imageTa
 			Handler:          probeHandler,
 			FailureThreshold: 18,
 		},
+		SecurityContext: containerSc,
+	}
+	if cr.Spec.EnvironmentOptions != nil {
+		applyJavaEnvironment(&container, cr.Spec.EnvironmentOptions.CoreEnvironment)
 	}
+	return container
 }
 
 func NewGrafanaSecretForCR(cr *operatorv1beta1.Cryostat) *corev1.Secret {
@@ -836,7 +973,11 @@ func NewGrafanaContainer(cr *operatorv1beta1.Cryostat, imageTag string, tls *TLS
 		// Use HTTPS for liveness probe
 		livenessProbeScheme = corev1.URISchemeHTTPS
 	}
-	return corev1.Container{
+	containerSc := newDefaultContainerSecurityContext()
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.GrafanaSecurityContext != nil {
+		containerSc = cr.Spec.SecurityOptions.GrafanaSecurityContext
+	}
+	container := corev1.Container{
 		Name:            cr.Name + "-grafana",
 		Image:           imageTag,
 		ImagePullPolicy: getPullPolicy(imageTag),
@@ -865,15 +1006,24 @@ func NewGrafanaContainer(cr *operatorv1beta1.Cryostat, imageTag string, tls *TLS
 				},
 			},
 		},
-		Resources: cr.Spec.Resources.GrafanaResources,
+		Resources:       cr.Spec.Resources.GrafanaResources,
+		SecurityContext: containerSc,
+	}
+	if cr.Spec.EnvironmentOptions != nil {
+		applyEnvironment(&container, cr.Spec.EnvironmentOptions.GrafanaEnvironment)
 	}
+	return container
 }
 
 // datasourceURL contains the fixed URL to jfr-datasource's web server
 var datasourceURL = "http://" + loopbackAddress + ":" + strconv.Itoa(int(datasourceContainerPort))
 
 func NewJfrDatasourceContainer(cr *operatorv1beta1.Cryostat, imageTag string) corev1.Container {
-	return corev1.Container{
+	containerSc := newDefaultContainerSecurityContext()
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.DataSourceSecurityContext != nil {
+		containerSc = cr.Spec.SecurityOptions.DataSourceSecurityContext
+	}
+	container := corev1.Container{
 		Name:            cr.Name + "-jfr-datasource",
 		Image:           imageTag,
 		ImagePullPolicy: getPullPolicy(imageTag),
@@ -896,8 +1046,13 @@ func NewJfrDatasourceContainer(cr *operatorv1beta1.Cryostat, imageTag string) co
 				},
 			},
 		},
-		Resources: cr.Spec.Resources.DataSourceResources,
+		Resources:       cr.Spec.Resources.DataSourceResources,
+		SecurityContext: containerSc,
+	}
+	if cr.Spec.EnvironmentOptions != nil {
+		applyJavaEnvironment(&container, cr.Spec.EnvironmentOptions.DataSourceEnvironment)
 	}
+	return container
 }
 
 func NewCoreService(cr *operatorv1beta1.Cryostat) *corev1.Service {
@@ -1092,6 +1247,21 @@ func NewKeystoreSecretForCR(cr *operatorv1beta1.Cryostat) *corev1.Secret {
 	}
 }
 
+// TargetCASecretNameSuffix is appended to the name of the Cryostat to form the name of
+// the secret holding the CA certificates of FlightRecorders configured for TLS
+const TargetCASecretNameSuffix = "-target-ca"
+
+// NewTargetCASecretForCR returns the secret whose certificates are added to
+// Cryostat's truststore to connect to target JVMs over TLS
+func NewTargetCASecretForCR(cr *operatorv1beta1.Cryostat) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + TargetCASecretNameSuffix,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
 func NewServiceAccountForCR(cr *operatorv1beta1.Cryostat, isOpenShift bool) (*corev1.ServiceAccount, error) {
 	annotations := make(map[string]string)
 
@@ -1263,9 +1433,7 @@ func getPullPolicy(imageTag string) corev1.PullPolicy {
 
 func newVolumeForCR(cr *operatorv1beta1.Cryostat) []corev1.Volume {
 	var volumeSource corev1.VolumeSource
-	deployEmptyDir := cr.Spec.StorageOptions != nil && cr.Spec.StorageOptions.EmptyDir != nil && cr.Spec.StorageOptions.EmptyDir.Enabled
-
-	if deployEmptyDir {
+	if UsesEmptyDir(cr) {
 		emptyDir := cr.Spec.StorageOptions.EmptyDir
 
 		sizeLimit, err := resource.ParseQuantity(emptyDir.SizeLimit)
@@ -1282,7 +1450,7 @@ func newVolumeForCR(cr *operatorv1beta1.Cryostat) []corev1.Volume {
 	} else {
 		volumeSource = corev1.VolumeSource{
 			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-				ClaimName: cr.Name,
+				ClaimName: GetStorageClaimName(cr),
 			},
 		}
 	}
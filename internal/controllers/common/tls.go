@@ -39,9 +39,11 @@ package common
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	certMeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -54,6 +56,8 @@ import (
 // TLS-related functionality
 type ReconcilerTLS interface {
 	IsCertManagerEnabled(cryostat *operatorv1beta1.Cryostat) bool
+	IsOperatorCAEnabled(cryostat *operatorv1beta1.Cryostat) bool
+	IsTLSEnabled(cryostat *operatorv1beta1.Cryostat) bool
 	GetCryostatCABytes(ctx context.Context, cryostat *operatorv1beta1.Cryostat) ([]byte, error)
 	GetCertificateSecret(ctx context.Context, name string, namespace string) (*corev1.Secret, error)
 	OSUtils
@@ -93,6 +97,11 @@ func NewReconcilerTLS(config *ReconcilerTLSConfig) ReconcilerTLS {
 // IsCertManagerEnabled returns whether TLS using cert-manager is enabled
 // for this operator
 func (r *reconcilerTLS) IsCertManagerEnabled(cr *operatorv1beta1.Cryostat) bool {
+	// The operator's own CA takes the place of cert-manager
+	if r.IsOperatorCAEnabled(cr) {
+		return false
+	}
+
 	// First check if cert-manager is explicitly enabled or disabled in CR
 	if cr.Spec.EnableCertManager != nil {
 		return *cr.Spec.EnableCertManager
@@ -102,10 +111,26 @@ func (r *reconcilerTLS) IsCertManagerEnabled(cr *operatorv1beta1.Cryostat) bool
 	return strings.ToLower(r.GetEnv(disableServiceTLS)) != "true"
 }
 
+// IsOperatorCAEnabled returns whether TLS certificates are issued by
+// the operator's own certificate authority
+func (r *reconcilerTLS) IsOperatorCAEnabled(cr *operatorv1beta1.Cryostat) bool {
+	return cr.Spec.TLSProvider != nil && *cr.Spec.TLSProvider == operatorv1beta1.TLSProviderOperator
+}
+
+// IsTLSEnabled returns whether communication between Cryostat components
+// is secured with TLS, using any provider
+func (r *reconcilerTLS) IsTLSEnabled(cr *operatorv1beta1.Cryostat) bool {
+	return r.IsOperatorCAEnabled(cr) || r.IsCertManagerEnabled(cr)
+}
+
 // ErrCertNotReady is returned when cert-manager has not marked the certificate
 // as ready, and no TLS secret has been populated yet.
 var ErrCertNotReady error = errors.New("Certificate secret not yet ready")
 
+// ErrCANotProvided is returned when an external issuer signed the Cryostat
+// certificate, but did not include its CA certificate in the TLS secret.
+var ErrCANotProvided error = errors.New("Issuer did not provide a CA certificate")
+
 // GetCertificateSecret returns the Secret corresponding to the named
 // cert-manager Certificate. This can return ErrCertNotReady if the
 // certificate secret is not available yet.
@@ -133,9 +158,39 @@ func (r *reconcilerTLS) GetCertificateSecret(ctx context.Context, name string, n
 }
 
 // GetCryostatCABytes returns the CA certificate created for the provided
-// Cryostat CR, as a byte slice.
+// Cryostat CR, as a byte slice. When certificates are signed by an external
+// issuer, this is the CA certificate provided by that issuer. If the issuer did
+// not provide one, an error wrapping ErrCANotProvided is returned.
 func (r *reconcilerTLS) GetCryostatCABytes(ctx context.Context, cryostat *operatorv1beta1.Cryostat) ([]byte, error) {
 	caName := cryostat.Name + "-ca"
+	secret := &corev1.Secret{}
+	if r.IsOperatorCAEnabled(cryostat) {
+		// The operator writes the CA secret directly
+		err := r.Client.Get(ctx, types.NamespacedName{Name: caName, Namespace: cryostat.Namespace}, secret)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, ErrCertNotReady
+			}
+			return nil, err
+		}
+		return secret.Data[corev1.TLSCertKey], nil
+	}
+
+	if resources.UsesExternalIssuer(cryostat) {
+		// No CA certificate is created by the operator, use the CA certificate
+		// included by the issuer in the Cryostat certificate's secret
+		secret, err := r.GetCertificateSecret(ctx, cryostat.Name, cryostat.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		caCert := secret.Data[resources.CAKey]
+		if len(caCert) == 0 {
+			return nil, fmt.Errorf("%w: key \"%s\" is missing or empty in secret \"%s/%s\"", ErrCANotProvided,
+				resources.CAKey, secret.Namespace, secret.Name)
+		}
+		return caCert, nil
+	}
+
 	secret, err := r.GetCertificateSecret(ctx, caName, cryostat.Namespace)
 	if err != nil {
 		return nil, err
@@ -38,14 +38,20 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
 	"net/url"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -58,16 +64,18 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	configv1 "github.com/openshift/api/config/v1"
-	consolev1 "github.com/openshift/api/console/v1"
 	openshiftv1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -101,6 +109,12 @@ const grafanaImageTagEnv = "RELATED_IMAGE_GRAFANA"
 // Environment variable to override the cryostat-reports image
 const reportsImageTagEnv = "RELATED_IMAGE_REPORTS"
 
+// Minimum delay before reconciling again to renew the operator's certificates
+const minRenewalRequeueDelay = 10 * time.Second
+
+// Environment variable containing the namespace the operator runs in
+const operatorNamespaceEnv = "OPERATOR_NAMESPACE"
+
 // Regular expression for the start of a GID range in the OpenShift
 // supplemental groups SCC annotation
 var supGroupRegexp = regexp.MustCompile(`^\d+`)
@@ -110,10 +124,19 @@ const apiServerName = "cluster"
 
 // Reasons for Cryostat Conditions
 const (
-	reasonWaitingForCert         = "WaitingForCertificate"
-	reasonAllCertsReady          = "AllCertificatesReady"
-	reasonCertManagerUnavailable = "CertManagerUnavailable"
-	reasonCertManagerDisabled    = "CertManagerDisabled"
+	reasonWaitingForCert               = "WaitingForCertificate"
+	reasonAllCertsReady                = "AllCertificatesReady"
+	reasonCertManagerUnavailable       = "CertManagerUnavailable"
+	reasonCAUnavailable                = "CAUnavailable"
+	reasonCertManagerDisabled          = "CertManagerDisabled"
+	reasonAllComponentsReady           = "AllComponentsReady"
+	reasonComponentsNotReady           = "ComponentsNotReady"
+	reasonNoEnvironmentConflicts       = "NoConflicts"
+	reasonReservedEnvironmentVariables = "ReservedVariables"
+	reasonOverrideApplied              = "OverrideApplied"
+	reasonInvalidPatch                 = "InvalidPatch"
+	reasonOperatorFieldsModified       = "OperatorFieldsModified"
+	reasonWaitingForRestore            = "WaitingForRestore"
 )
 
 // Map Cryostat conditions to deployment conditions
@@ -142,13 +165,19 @@ var reportsDeploymentConditions = deploymentConditionTypeMap{
 // +kubebuilder:rbac:namespace=system,groups=route.openshift.io,resources=routes;routes/custom-host,verbs=*
 // +kubebuilder:rbac:namespace=system,groups=apps.openshift.io,resources=deploymentconfigs,verbs=get
 // +kubebuilder:rbac:namespace=system,groups=apps,resources=deployments;daemonsets;replicasets;statefulsets,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=autoscaling,resources=horizontalpodautoscalers,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=policy,resources=poddisruptionbudgets,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=batch,resources=jobs,verbs=*
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:namespace=system,groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create
-// +kubebuilder:rbac:namespace=system,groups=cert-manager.io,resources=issuers;certificates,verbs=create;get;list;update;watch
+// +kubebuilder:rbac:namespace=system,groups=cert-manager.io,resources=issuers;certificates,verbs=create;get;list;update;watch;delete
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostats,verbs=*
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostats/status,verbs=get;update;patch
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostats/finalizers,verbs=update
 // +kubebuilder:rbac:groups=console.openshift.io,resources=consolelinks,verbs=get;create;list;update;delete
-// +kubebuilder:rbac:namespace=system,groups=networking.k8s.io,resources=ingresses,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=networking.k8s.io,resources=ingresses;networkpolicies,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=gateway.networking.k8s.io,resources=httproutes,verbs=*
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get
 
 // Reconcile processes a Cryostat CR and manages a Cryostat installation accordingly
 func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
@@ -209,60 +238,79 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 		}
 	}
 
+	// Record the objects making up the desired state of this Cryostat,
+	// so that any others can be pruned once reconciled
+	ctx = withInventory(ctx, instance)
+
 	reqLogger.Info("Spec", "Minimal", instance.Spec.Minimal)
 
-	shouldCreatePvc := !(instance.Spec.StorageOptions != nil && instance.Spec.StorageOptions.EmptyDir != nil && instance.Spec.StorageOptions.EmptyDir.Enabled)
-	if shouldCreatePvc {
-		pvc := resources.NewPersistentVolumeClaimForCR(instance)
-		if err := controllerutil.SetControllerReference(instance, pvc, r.Scheme); err != nil {
-			return reconcile.Result{}, err
-		}
-		if err = r.createObjectIfNotExists(context.Background(), types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, &corev1.PersistentVolumeClaim{}, pvc); err != nil {
-			return reconcile.Result{}, err
-		}
+	storage, err := r.reconcileStorage(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	grafanaSecret := resources.NewGrafanaSecretForCR(instance)
-	if err := controllerutil.SetControllerReference(instance, grafanaSecret, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-	if err = r.createObjectIfNotExists(context.Background(), types.NamespacedName{Name: grafanaSecret.Name, Namespace: grafanaSecret.Namespace}, &corev1.Secret{}, grafanaSecret); err != nil {
+	if err = r.createOrUpdateCredentialsSecret(ctx, grafanaSecret, instance); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	jmxAuthSecret := resources.NewJmxSecretForCR(instance)
-	if err := controllerutil.SetControllerReference(instance, jmxAuthSecret, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-	if err = r.createObjectIfNotExists(context.Background(), types.NamespacedName{Name: jmxAuthSecret.Name, Namespace: jmxAuthSecret.Namespace}, &corev1.Secret{}, jmxAuthSecret); err != nil {
+	if err = r.createOrUpdateCredentialsSecret(ctx, jmxAuthSecret, instance); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// Set up TLS using cert-manager, if available
+	// Set up TLS using cert-manager or the operator's CA, if enabled
 	var tlsConfig *resources.TLSConfig
 	var routeTLS *openshiftv1.TLSConfig
-	if r.IsCertManagerEnabled(instance) {
-		tlsConfig, err = r.setupTLS(context.Background(), instance)
+	// Reconcile again when the operator's certificates are due for renewal
+	var requeueAfter time.Duration
+	if r.IsTLSEnabled(instance) {
+		if r.IsOperatorCAEnabled(instance) {
+			var renewal time.Time
+			tlsConfig, renewal, err = r.setupOperatorTLS(ctx, instance)
+			// Don't retry immediately if renewal is already due
+			requeueAfter = time.Until(renewal)
+			if requeueAfter < minRenewalRequeueDelay {
+				requeueAfter = minRenewalRequeueDelay
+			}
+		} else {
+			tlsConfig, err = r.setupTLS(ctx, instance)
+		}
 		if err != nil {
 			if err == common.ErrCertNotReady {
 				condErr := r.updateCondition(ctx, instance, operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
 					reasonWaitingForCert, "Waiting for certificates to become ready.")
 				if condErr != nil {
-					return reconcile.Result{}, err
+					return reconcile.Result{}, condErr
+				}
+				condErr = r.updateReadyCondition(ctx, instance)
+				if condErr != nil {
+					return reconcile.Result{}, condErr
 				}
 				return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 			}
 			if err == errCertManagerMissing {
-				r.updateCondition(ctx, instance, operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
-					reasonCertManagerUnavailable, eventCertManagerUnavailableMsg)
+				condErr := r.updateCondition(ctx, instance, operatorv1beta1.ConditionTypeTLSSetupComplete,
+					metav1.ConditionFalse, reasonCertManagerUnavailable, eventCertManagerUnavailableMsg)
+				if condErr != nil {
+					return reconcile.Result{}, condErr
+				}
 			}
 			reqLogger.Error(err, "Failed to set up TLS for Cryostat")
 			return reconcile.Result{}, err
 		}
 
 		// Get CA certificate from secret and set as destination CA in route
-		caCert, err := r.GetCryostatCABytes(context.Background(), instance)
+		caCert, err := r.GetCryostatCABytes(ctx, instance)
 		if err != nil {
+			if goerrors.Is(err, common.ErrCANotProvided) {
+				condErr := r.updateCondition(ctx, instance, operatorv1beta1.ConditionTypeTLSSetupComplete,
+					metav1.ConditionFalse, reasonCAUnavailable, err.Error())
+				if condErr != nil {
+					return reconcile.Result{}, condErr
+				}
+			}
+			reqLogger.Error(err, "Failed to retrieve CA certificate for Cryostat")
 			return reconcile.Result{}, err
 		}
 		routeTLS = &openshiftv1.TLSConfig{
@@ -289,106 +337,85 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	err = r.createNetworkPolicies(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	serviceSpecs := &resources.ServiceSpecs{}
 	if !instance.Spec.Minimal {
 		grafanaSvc := resources.NewGrafanaService(instance)
-		svcUrl, err := r.createService(context.Background(), instance, grafanaSvc, &grafanaSvc.Spec.Ports[0], routeTLS)
+		svcUrl, err := r.createService(ctx, instance, grafanaSvc, &grafanaSvc.Spec.Ports[0], routeTLS)
 		if err != nil {
 			return requeueIfIngressNotReady(reqLogger, err)
 		}
 		serviceSpecs.GrafanaURL = svcUrl
-
-		// check for existing minimal deployment and delete if found
-		deployment := &appsv1.Deployment{}
-		err = r.Client.Get(context.Background(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, deployment)
-		if err == nil && len(deployment.Spec.Template.Spec.Containers) == 1 {
-			reqLogger.Info("Deleting existing minimal deployment")
-			err = r.Client.Delete(context.Background(), deployment)
-			if err != nil && !errors.IsNotFound(err) {
-				return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, err
-			}
-		}
-	} else {
-		// check for existing non-minimal resources and delete if found
-		svc := resources.NewGrafanaService(instance)
-		if r.IsOpenShift {
-			reqLogger.Info("Deleting existing non-minimal route", "route.Name", svc.Name)
-			route := &openshiftv1.Route{}
-			err = r.Client.Get(context.Background(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, route)
-			if err != nil && !errors.IsNotFound(err) {
-				reqLogger.Info("Non-minimal route could not be retrieved", "route.Name", svc.Name)
-				return reconcile.Result{}, err
-			} else if err == nil {
-				err = r.Client.Delete(context.Background(), route)
-				if err != nil && !errors.IsNotFound(err) {
-					reqLogger.Info("Could not delete non-minimal route", "route.Name", svc.Name)
-					return reconcile.Result{}, err
-				}
-			}
-		} else {
-			reqLogger.Info("Deleting existing non-minimal ingress", "ingress.Name", svc.Name)
-			ingress := &netv1.Ingress{}
-			err = r.Client.Get(context.Background(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, ingress)
-			if err != nil && !errors.IsNotFound(err) {
-				reqLogger.Info("Non-minimal ingress could not be retrieved", "ingress.Name", svc.Name)
-				return reconcile.Result{}, err
-			} else if err == nil {
-				err = r.Client.Delete(context.Background(), ingress)
-				if err != nil && !errors.IsNotFound(err) {
-					reqLogger.Info("Could not delete non-minimal ingress", "ingress.Name", svc.Name)
-					return reconcile.Result{}, err
-				}
-			}
-		}
-
-		err = r.Client.Get(context.Background(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, svc)
-		if err == nil {
-			reqLogger.Info("Deleting existing non-minimal service", "svc.Name", svc.Name)
-			err = r.Client.Delete(context.Background(), svc)
-			if err != nil && !errors.IsNotFound(err) {
-				reqLogger.Info("Could not delete non-minimal service")
-				return reconcile.Result{}, err
-			}
-		}
-
-		deployment := &appsv1.Deployment{}
-		err = r.Client.Get(context.Background(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, deployment)
-		if err == nil && len(deployment.Spec.Template.Spec.Containers) > 1 {
-			reqLogger.Info("Deleting existing non-minimal deployment")
-			err = r.Client.Delete(context.Background(), deployment)
-			if err != nil && !errors.IsNotFound(err) {
-				reqLogger.Info("Could not delete non-minimal deployment")
-				return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, err
-			}
-		}
 	}
 
 	coreSvc := resources.NewCoreService(instance)
-	svcUrl, err := r.createService(context.Background(), instance, coreSvc, &coreSvc.Spec.Ports[0], routeTLS)
+	svcUrl, err := r.createService(ctx, instance, coreSvc, &coreSvc.Spec.Ports[0], routeTLS)
 	if err != nil {
 		return requeueIfIngressNotReady(reqLogger, err)
 	}
 	serviceSpecs.CoreURL = svcUrl
 
 	imageTags := r.getImageTags()
-	fsGroup, err := r.getFSGroup(ctx, instance.Namespace)
+	fsGroup, err := getFSGroup(ctx, r.Client, r.IsOpenShift, instance.Namespace)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	reportsResult, err := r.reconcileReports(ctx, reqLogger, instance, tlsConfig, imageTags, serviceSpecs)
+	reportsPodSpec, reportsResult, err := r.reconcileReports(ctx, reqLogger, instance, tlsConfig, imageTags, serviceSpecs)
 	if err != nil {
 		return reportsResult, err
 	}
 
+	// Hold back the first deployment of Cryostat until any data has been restored into its storage
+	restore, err := r.getPendingRestore(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if restore != nil {
+		reqLogger.Info("Waiting for CryostatRestore to complete before deploying Cryostat", "restore", restore.Name)
+		err = r.updateCondition(ctx, instance, operatorv1beta1.ConditionTypeRestoreComplete, metav1.ConditionFalse,
+			reasonWaitingForRestore, fmt.Sprintf("Waiting for CryostatRestore %s to complete.", restore.Name))
+		return reconcile.Result{}, err
+	}
+	removeConditionIfPresent(instance, operatorv1beta1.ConditionTypeRestoreComplete)
+
+	configHash, err := r.getCoreConfigHash(ctx, instance, tlsConfig)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	deployment := resources.NewDeploymentForCR(instance, serviceSpecs, imageTags, tlsConfig, *fsGroup, r.IsOpenShift)
+	overridePodTemplate(reqLogger, instance, deployment, instance.Spec.PodTemplateOverride,
+		operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied)
+	metav1.SetMetaDataAnnotation(&deployment.Spec.Template.ObjectMeta, configHashAnnotation, configHash)
 	podTemplate := deployment.Spec.Template.DeepCopy()
 	if err := controllerutil.SetControllerReference(instance, deployment, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+	op, err := r.createOrUpdate(ctx, deployment, func() error {
 		// Update pod template spec to propagate any changes from Cryostat CR
 		deployment.Spec.Template.Spec = podTemplate.Spec
+		// Stop Cryostat while its storage is migrated, and start it again once
+		// no migration is in progress
+		if storage.scaleDown {
+			// Record that Cryostat was stopped for the migration, unless the user already scaled it down
+			if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas > 0 {
+				metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, storageScaledDownAnnotation, "true")
+			}
+			replicas := int32(0)
+			deployment.Spec.Replicas = &replicas
+		} else if _, scaledDown := deployment.Annotations[storageScaledDownAnnotation]; scaledDown {
+			deployment.Spec.Replicas = nil
+			delete(deployment.Annotations, storageScaledDownAnnotation)
+		}
+		// Roll out a new pod if any mounted secrets or config maps have changed,
+		// and propagate any labels and annotations added by a pod template override.
+		// Those left behind by a removed override are deleted.
+		replaceManagedLabelsAndAnnotations(&deployment.Spec.Template.ObjectMeta, podTemplate.Labels,
+			podTemplate.Annotations)
 		return nil
 	})
 	if err != nil {
@@ -396,12 +423,19 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 	}
 	reqLogger.Info(fmt.Sprintf("Deployment %s", op))
 
-	if serviceSpecs.CoreURL != nil {
-		instance.Status.ApplicationURL = serviceSpecs.CoreURL.String()
-		err = r.Client.Status().Update(context.Background(), instance)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
+	// Report any extra environment variables that conflict with the operator's
+	// own, whether they were added by a pod template override or otherwise
+	podSpecs := []*corev1.PodSpec{&podTemplate.Spec}
+	if reportsPodSpec != nil {
+		podSpecs = append(podSpecs, reportsPodSpec)
+	}
+	updateEnvironmentConflictCondition(instance, resources.FindEnvConflicts(instance, podSpecs...))
+
+	// Report the endpoints and images of the deployed components
+	setDeploymentStatus(instance, serviceSpecs, imageTags)
+	err = r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// OpenShift-specific
@@ -426,8 +460,21 @@ func (r *CryostatReconciler) Reconcile(ctx context.Context, request ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	// The current spec has been applied, summarize the conditions above
+	instance.Status.ObservedGeneration = instance.Generation
+	err = r.updateReadyCondition(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Delete any objects no longer part of the desired state
+	err = r.pruneResources(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	reqLogger.Info("Successfully reconciled deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -436,11 +483,13 @@ func (r *CryostatReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&operatorv1beta1.Cryostat{})
 
 	// Watch for changes to secondary resources and requeue the owner Cryostat
-	resources := []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.Secret{}, &corev1.PersistentVolumeClaim{}}
+	// This includes the TLS secrets created by cert-manager, which are owned by
+	// the Cryostat CR once issued. Renewed certificates trigger a new rollout.
+	resources := []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.Secret{}, &corev1.PersistentVolumeClaim{},
+		&netv1.NetworkPolicy{}, &batchv1.Job{}}
 	if r.IsOpenShift {
 		resources = append(resources, &openshiftv1.Route{})
 	}
-	// TODO watch certificates and redeploy when renewed
 
 	for _, resource := range resources {
 		c = c.Watches(&source.Kind{Type: resource}, &handler.EnqueueRequestForOwner{
@@ -449,11 +498,255 @@ func (r *CryostatReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		})
 	}
 
+	// Watch user-provided secrets and config maps referenced by Cryostat CRs,
+	// so that changes to their contents are rolled out to the deployment
+	c = c.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.secretToCryostats))
+	c = c.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.configMapToCryostats))
+
+	// Deploy Cryostat once any restore into its storage has finished
+	c = c.Watches(&source.Kind{Type: &operatorv1beta1.CryostatRestore{}}, handler.EnqueueRequestsFromMapFunc(r.restoreToCryostat))
+	c = c.Watches(&source.Kind{Type: &operatorv1beta1.CryostatBackup{}}, handler.EnqueueRequestsFromMapFunc(r.backupToCryostats))
+
 	return c.Complete(r)
 }
 
+func (r *CryostatReconciler) restoreToCryostat(obj client.Object) []reconcile.Request {
+	restore, ok := obj.(*operatorv1beta1.CryostatRestore)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{Name: restore.Spec.CryostatName, Namespace: restore.Namespace},
+		},
+	}
+}
+
+func (r *CryostatReconciler) backupToCryostats(obj client.Object) []reconcile.Request {
+	// Reconcile any Cryostat waiting on a restore from this backup
+	restores := &operatorv1beta1.CryostatRestoreList{}
+	err := r.Client.List(context.Background(), restores, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "Failed to list CryostatRestores", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, restore := range restores.Items {
+		if restore.Spec.BackupName == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: restore.Spec.CryostatName, Namespace: restore.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// getPendingRestore returns a CryostatRestore that is yet to finish restoring data
+// into the storage of a Cryostat that has not been deployed, if any. Restores that
+// have not started are only waited for if they are able to make progress.
+func (r *CryostatReconciler) getPendingRestore(ctx context.Context,
+	cr *operatorv1beta1.Cryostat) (*operatorv1beta1.CryostatRestore, error) {
+	deploy := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, deploy)
+	if err == nil {
+		// Restores only apply to new Cryostats
+		return nil, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	restores := &operatorv1beta1.CryostatRestoreList{}
+	err = r.Client.List(ctx, restores, client.InNamespace(cr.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	for i, restore := range restores.Items {
+		if restore.Spec.CryostatName != cr.Name || isStorageOperationFinished(restore.Status.Phase) {
+			continue
+		}
+		if restore.Status.Phase == operatorv1beta1.StorageOperationRunning {
+			return &restores.Items[i], nil
+		}
+		ready, err := r.isRestoreBackupAvailable(ctx, &restore)
+		if err != nil {
+			return nil, err
+		}
+		if ready {
+			return &restores.Items[i], nil
+		}
+		r.Log.Info("Not waiting for CryostatRestore, its backup is unavailable", "namespace", restore.Namespace,
+			"name", restore.Name, "backup", restore.Spec.BackupName)
+	}
+	return nil, nil
+}
+
+// isRestoreBackupAvailable returns whether the backup a CryostatRestore copies from
+// has completed, or is expected to complete
+func (r *CryostatReconciler) isRestoreBackupAvailable(ctx context.Context,
+	restore *operatorv1beta1.CryostatRestore) (bool, error) {
+	backup := &operatorv1beta1.CryostatBackup{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupName, Namespace: restore.Namespace}, backup)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch backup.Status.Phase {
+	case operatorv1beta1.StorageOperationSucceeded:
+		return true, nil
+	case operatorv1beta1.StorageOperationFailed:
+		return false, nil
+	}
+	// An unfinished backup can only complete if the Cryostat it backs up exists
+	source := &operatorv1beta1.Cryostat{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: backup.Spec.CryostatName, Namespace: backup.Namespace}, source)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *CryostatReconciler) secretToCryostats(obj client.Object) []reconcile.Request {
+	return r.findReferencingCryostats(obj, func(cr *operatorv1beta1.Cryostat) bool {
+		for _, secret := range cr.Spec.TrustedCertSecrets {
+			if secret.SecretName == obj.GetName() {
+				return true
+			}
+		}
+		if cr.Spec.NetworkOptions != nil {
+			for _, config := range []*operatorv1beta1.NetworkConfiguration{cr.Spec.NetworkOptions.CoreConfig,
+				cr.Spec.NetworkOptions.GrafanaConfig} {
+				if config != nil && config.RouteConfig != nil &&
+					config.RouteConfig.CertificateSecretName == obj.GetName() {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+func (r *CryostatReconciler) configMapToCryostats(obj client.Object) []reconcile.Request {
+	return r.findReferencingCryostats(obj, func(cr *operatorv1beta1.Cryostat) bool {
+		for _, template := range cr.Spec.EventTemplates {
+			if template.ConfigMapName == obj.GetName() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (r *CryostatReconciler) findReferencingCryostats(obj client.Object,
+	references func(cr *operatorv1beta1.Cryostat) bool) []reconcile.Request {
+	cryostats := &operatorv1beta1.CryostatList{}
+	err := r.Client.List(context.Background(), cryostats, &client.ListOptions{
+		Namespace: obj.GetNamespace(),
+	})
+	if err != nil {
+		r.Log.Error(err, "Failed to list Cryostats", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for i := range cryostats.Items {
+		cr := &cryostats.Items[i]
+		if references(cr) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: cr.Namespace,
+					Name:      cr.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// computeConfigHash returns a digest of the contents of the provided secrets and
+// config maps. Stamping this on a pod template causes a rollout whenever the
+// contents change. Missing objects are skipped, since their creation will
+// change the digest.
+func (r *CryostatReconciler) computeConfigHash(ctx context.Context, namespace string, secretNames []string,
+	configMapNames []string) (string, error) {
+	hash := sha256.New()
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		writeHashEntries(hash, "secret/"+name, secret.Data)
+	}
+	for _, name := range configMapNames {
+		cm := &corev1.ConfigMap{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cm)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+		for key, val := range cm.Data {
+			data[key] = []byte(val)
+		}
+		for key, val := range cm.BinaryData {
+			data[key] = val
+		}
+		writeHashEntries(hash, "configmap/"+name, data)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func writeHashEntries(hash io.Writer, prefix string, data map[string][]byte) {
+	// Sort keys for a stable digest
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s/%s=", prefix, key)
+		hash.Write(data[key])
+		hash.Write([]byte{0})
+	}
+}
+
+// getCoreConfigHash computes a digest of the secrets and config maps mounted
+// into the main Cryostat deployment
+func (r *CryostatReconciler) getCoreConfigHash(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	tls *resources.TLSConfig) (string, error) {
+	secrets := []string{}
+	for _, secret := range cr.Spec.TrustedCertSecrets {
+		secrets = append(secrets, secret.SecretName)
+	}
+	if tls != nil {
+		secrets = append(secrets, tls.CryostatSecret)
+		if !cr.Spec.Minimal {
+			secrets = append(secrets, tls.GrafanaSecret)
+		}
+	}
+	// CA certificates of target JVMs, added by the FlightRecorder controller
+	secrets = append(secrets, cr.Name+resources.TargetCASecretNameSuffix)
+	configMaps := []string{}
+	for _, template := range cr.Spec.EventTemplates {
+		configMaps = append(configMaps, template.ConfigMapName)
+	}
+	return r.computeConfigHash(ctx, cr.Namespace, secrets, configMaps)
+}
+
+// reconcileReports creates or updates the reports deployment, if enabled, and returns
+// its pod spec after any pod template override is applied
 func (r *CryostatReconciler) reconcileReports(ctx context.Context, reqLogger logr.Logger, instance *operatorv1beta1.Cryostat,
-	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (reconcile.Result, error) {
+	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (*corev1.PodSpec, reconcile.Result, error) {
 	reqLogger.Info("Spec", "Reports", instance.Spec.ReportOptions)
 
 	if instance.Spec.ReportOptions == nil {
@@ -462,74 +755,92 @@ func (r *CryostatReconciler) reconcileReports(ctx context.Context, reqLogger log
 	desired := instance.Spec.ReportOptions.Replicas
 
 	deployment := resources.NewDeploymentForReports(instance, imageTags, tls)
-	if desired == 0 {
-		svc := resources.NewReportService(instance)
-		if err := r.Client.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
-			return reconcile.Result{}, err
-		}
-		if err := r.Client.Delete(ctx, deployment); err != nil && !errors.IsNotFound(err) {
-			return reconcile.Result{}, err
-		}
-
+	if !resources.IsReportsEnabled(instance) {
+		// The reports service and deployment are pruned once reconciled
 		removeConditionIfPresent(instance, operatorv1beta1.ConditionTypeReportsDeploymentAvailable,
 			operatorv1beta1.ConditionTypeReportsDeploymentProgressing,
-			operatorv1beta1.ConditionTypeReportsDeploymentReplicaFailure)
+			operatorv1beta1.ConditionTypeReportsDeploymentReplicaFailure,
+			operatorv1beta1.ConditionTypeReportsDeploymentOverrideApplied)
 		err := r.Client.Status().Update(ctx, instance)
 		if err != nil {
-			return reconcile.Result{}, err
+			return nil, reconcile.Result{}, err
 		}
-		return reconcile.Result{}, nil
+		return nil, reconcile.Result{}, nil
 	}
 
-	if desired > 0 {
-		svc := resources.NewReportService(instance)
-		if err := controllerutil.SetControllerReference(instance, svc, r.Scheme); err != nil {
-			return reconcile.Result{}, err
-		}
-		if err := r.createObjectIfNotExists(ctx, types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, &corev1.Service{}, svc); err != nil {
-			return reconcile.Result{}, err
-		}
+	svc := resources.NewReportService(instance)
+	if err := r.createOrUpdateService(ctx, svc, instance); err != nil {
+		return nil, reconcile.Result{}, err
+	}
 
-		if err := controllerutil.SetControllerReference(instance, deployment, r.Scheme); err != nil {
-			return reconcile.Result{}, err
-		}
+	overridePodTemplate(reqLogger, instance, deployment, instance.Spec.ReportOptions.PodTemplateOverride,
+		operatorv1beta1.ConditionTypeReportsDeploymentOverrideApplied)
+
+	if err := controllerutil.SetControllerReference(instance, deployment, r.Scheme); err != nil {
+		return nil, reconcile.Result{}, err
+	}
 
-		podTemplate := deployment.Spec.Template.DeepCopy()
-		op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
-			deployment.Spec.Template.Spec = podTemplate.Spec
+	secrets := []string{}
+	if tls != nil {
+		secrets = append(secrets, tls.ReportsSecret)
+	}
+	configHash, err := r.computeConfigHash(ctx, instance.Namespace, secrets, nil)
+	if err != nil {
+		return nil, reconcile.Result{}, err
+	}
+	metav1.SetMetaDataAnnotation(&deployment.Spec.Template.ObjectMeta, configHashAnnotation, configHash)
+
+	podTemplate := deployment.Spec.Template.DeepCopy()
+	autoscaling := instance.Spec.ReportOptions.Autoscaling
+	op, err := r.createOrUpdate(ctx, deployment, func() error {
+		deployment.Spec.Template.Spec = podTemplate.Spec
+		replaceManagedLabelsAndAnnotations(&deployment.Spec.Template.ObjectMeta, podTemplate.Labels,
+			podTemplate.Annotations)
+		// Replicas are managed by the autoscaler once the deployment is created
+		if autoscaling == nil {
 			deployment.Spec.Replicas = &desired
-			return nil
-		})
-		if err != nil {
-			return reconcile.Result{}, err
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, reconcile.Result{}, err
+	}
 
-		scheme := "https"
-		if tls == nil {
-			scheme = "http"
+	if autoscaling != nil {
+		hpa := resources.NewReportsHorizontalPodAutoscaler(instance)
+		if err := r.createOrUpdateHorizontalPodAutoscaler(ctx, hpa, instance); err != nil {
+			return nil, reconcile.Result{}, err
 		}
-		serviceSpecs.ReportsURL = &url.URL{
-			Scheme: scheme,
-			Host:   svc.Name + ":" + strconv.Itoa(int(svc.Spec.Ports[0].Port)),
+	}
+	if instance.Spec.ReportOptions.PodDisruptionBudget != nil {
+		pdb := resources.NewReportsPodDisruptionBudget(instance)
+		if err := r.createOrUpdatePodDisruptionBudget(ctx, pdb, instance); err != nil {
+			return nil, reconcile.Result{}, err
 		}
-		reqLogger.Info(fmt.Sprintf("Reports Deployment %s", op))
+	}
 
-		// Check deployment status and update conditions
-		err = r.updateConditionsFromDeployment(ctx, instance, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace},
-			reportsDeploymentConditions)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
+	scheme := "https"
+	if tls == nil {
+		scheme = "http"
+	}
+	serviceSpecs.ReportsURL = &url.URL{
+		Scheme: scheme,
+		Host:   svc.Name + ":" + strconv.Itoa(int(svc.Spec.Ports[0].Port)),
+	}
+	reqLogger.Info(fmt.Sprintf("Reports Deployment %s", op))
+
+	// Check deployment status and update conditions
+	err = r.updateConditionsFromDeployment(ctx, instance, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace},
+		reportsDeploymentConditions)
+	if err != nil {
+		return nil, reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	return &podTemplate.Spec, reconcile.Result{}, nil
 }
 
 func (r *CryostatReconciler) createService(ctx context.Context, controller *operatorv1beta1.Cryostat, svc *corev1.Service, exposePort *corev1.ServicePort,
 	tlsConfig *openshiftv1.TLSConfig) (*url.URL, error) {
-	if err := controllerutil.SetControllerReference(controller, svc, r.Scheme); err != nil {
-		return nil, err
-	}
-	if err := r.createObjectIfNotExists(context.Background(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, &corev1.Service{}, svc); err != nil {
+	if err := r.createOrUpdateService(ctx, svc, controller); err != nil {
 		return nil, err
 	}
 
@@ -541,11 +852,23 @@ func (r *CryostatReconciler) createService(ctx context.Context, controller *oper
 		}
 	}
 	if r.IsOpenShift {
-		return r.createRouteForService(ctx, controller, svc, *exposePort, tlsConfig)
+		var networkConfig *operatorv1beta1.NetworkConfiguration
+		if controller.Spec.NetworkOptions != nil {
+			var err error
+			networkConfig, err = getNetworkConfig(controller, svc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return r.createRouteForService(ctx, controller, svc, *exposePort, tlsConfig, networkConfig)
 	} else {
 		if controller.Spec.NetworkOptions == nil {
 			return nil, nil
 		}
+		if controller.Spec.NetworkOptions.GatewayConfig != nil {
+			return r.createHTTPRouteForService(ctx, controller, svc, *exposePort,
+				controller.Spec.NetworkOptions.GatewayConfig)
+		}
 		networkConfig, err := getNetworkConfig(controller, svc)
 		if err != nil {
 			return nil, err
@@ -553,7 +876,7 @@ func (r *CryostatReconciler) createService(ctx context.Context, controller *oper
 		if networkConfig == nil || networkConfig.IngressSpec == nil {
 			return nil, nil
 		}
-		return r.createIngressForService(controller, svc, networkConfig)
+		return r.createIngressForService(ctx, controller, svc, networkConfig)
 	}
 }
 
@@ -562,27 +885,57 @@ func (r *CryostatReconciler) createService(ctx context.Context, controller *oper
 var ErrIngressNotReady = goerrors.New("Ingress configuration not yet available")
 
 func (r *CryostatReconciler) createRouteForService(ctx context.Context, cr *operatorv1beta1.Cryostat,
-	svc *corev1.Service, exposePort corev1.ServicePort, tlsConfig *openshiftv1.TLSConfig) (*url.URL, error) {
+	svc *corev1.Service, exposePort corev1.ServicePort, tlsConfig *openshiftv1.TLSConfig,
+	networkConfig *operatorv1beta1.NetworkConfiguration) (*url.URL, error) {
 	logger := r.Log.WithValues("Request.Namespace", svc.Namespace, "Name", svc.Name, "Kind", fmt.Sprintf("%T", &openshiftv1.Route{}))
+	routeConfig := &operatorv1beta1.RouteConfiguration{}
+	var labels, annotations map[string]string
+	if networkConfig != nil {
+		if networkConfig.RouteConfig != nil {
+			routeConfig = networkConfig.RouteConfig
+		}
+		labels = networkConfig.Labels
+		annotations = networkConfig.Annotations
+	}
+
+	tlsConfig = tlsConfig.DeepCopy()
+	if len(routeConfig.CertificateSecretName) > 0 {
+		if err := r.setRouteCertificate(ctx, svc.Namespace, routeConfig.CertificateSecretName, tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+	wildcardPolicy := openshiftv1.WildcardPolicyType(routeConfig.WildcardPolicy)
+	if len(wildcardPolicy) == 0 {
+		wildcardPolicy = openshiftv1.WildcardPolicyNone
+	}
+
 	route := &openshiftv1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      svc.Name,
 			Namespace: svc.Namespace,
 		},
 	}
-	if err := controllerutil.SetControllerReference(cr, route, r.Scheme); err != nil {
-		return nil, err
-	}
-
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+	op, err := r.createOrUpdate(ctx, route, func() error {
+		if err := controllerutil.SetControllerReference(cr, route, r.Scheme); err != nil {
+			return err
+		}
+		replaceManagedLabelsAndAnnotations(&route.ObjectMeta, labels, annotations)
+		// Keep the host generated by OpenShift, unless one is specified
+		host := route.Spec.Host
+		if len(routeConfig.Host) > 0 {
+			host = routeConfig.Host
+		}
 		// Update Route spec
 		route.Spec = openshiftv1.RouteSpec{
+			Host: host,
+			Path: routeConfig.Path,
 			To: openshiftv1.RouteTargetReference{
 				Kind: "Service",
 				Name: svc.Name,
 			},
-			Port: &openshiftv1.RoutePort{TargetPort: exposePort.TargetPort},
-			TLS:  tlsConfig,
+			Port:           &openshiftv1.RoutePort{TargetPort: exposePort.TargetPort},
+			TLS:            tlsConfig,
+			WildcardPolicy: wildcardPolicy,
 		}
 		return nil
 	})
@@ -595,45 +948,69 @@ func (r *CryostatReconciler) createRouteForService(ctx context.Context, cr *oper
 		return nil, ErrIngressNotReady
 	}
 
+	// Prefer the router's status for the route's current host
+	host := route.Status.Ingress[0].Host
+	for _, ingress := range route.Status.Ingress {
+		if ingress.Host == route.Spec.Host {
+			host = ingress.Host
+			break
+		}
+	}
 	return &url.URL{
 		Scheme: getProtocol(tlsConfig),
-		Host:   route.Status.Ingress[0].Host,
+		Host:   host,
+		Path:   routeConfig.Path,
 	}, nil
 }
 
-func (r *CryostatReconciler) createIngressForService(controller *operatorv1beta1.Cryostat, svc *corev1.Service,
-	networkConfig *operatorv1beta1.NetworkConfiguration) (*url.URL, error) {
+// setRouteCertificate configures the route to present the certificate in the
+// provided TLS secret
+func (r *CryostatReconciler) setRouteCertificate(ctx context.Context, namespace string, secretName string,
+	tlsConfig *openshiftv1.TLSConfig) error {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+	if err != nil {
+		return err
+	}
+	cert, pres := secret.Data[corev1.TLSCertKey]
+	if !pres {
+		return fmt.Errorf("secret %s is missing key %s", secretName, corev1.TLSCertKey)
+	}
+	key, pres := secret.Data[corev1.TLSPrivateKeyKey]
+	if !pres {
+		return fmt.Errorf("secret %s is missing key %s", secretName, corev1.TLSPrivateKeyKey)
+	}
+	tlsConfig.Certificate = string(cert)
+	tlsConfig.Key = string(key)
+	tlsConfig.CACertificate = string(secret.Data[resources.CAKey])
+	return nil
+}
+
+func (r *CryostatReconciler) createIngressForService(ctx context.Context, controller *operatorv1beta1.Cryostat,
+	svc *corev1.Service, networkConfig *operatorv1beta1.NetworkConfiguration) (*url.URL, error) {
 	logger := r.Log.WithValues("Request.Namespace", svc.Namespace, "Name", svc.Name, "Kind", fmt.Sprintf("%T", &netv1.Ingress{}))
 
 	ingress := &netv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        svc.Name,
-			Namespace:   svc.Namespace,
-			Annotations: networkConfig.Annotations,
-			Labels:      networkConfig.Labels,
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
 		},
-		Spec: *networkConfig.IngressSpec,
-	}
-	if err := controllerutil.SetControllerReference(controller, ingress, r.Scheme); err != nil {
-		return nil, err
 	}
-
-	found := &netv1.Ingress{}
-	err := r.Client.Get(context.Background(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		logger.Info("Not found")
-		if err := r.Client.Create(context.Background(), ingress); err != nil {
-			logger.Error(err, "Could not be created")
-			return nil, err
+	op, err := r.createOrUpdate(ctx, ingress, func() error {
+		if err := controllerutil.SetControllerReference(controller, ingress, r.Scheme); err != nil {
+			return err
 		}
-		logger.Info("Created")
-		found = ingress
-	} else if err != nil {
-		logger.Error(err, "Could not be read")
+		replaceManagedLabelsAndAnnotations(&ingress.ObjectMeta, networkConfig.Labels, networkConfig.Annotations)
+		// Update Ingress spec
+		ingress.Spec = *networkConfig.IngressSpec.DeepCopy()
+		return nil
+	})
+	if err != nil {
+		logger.Error(err, "Could not be created or updated")
 		return nil, err
 	}
 
-	logger.Info("Ingress created", "Service.Status", fmt.Sprintf("%#v", found.Status))
+	logger.Info(fmt.Sprintf("Ingress %s", op), "Service.Status", fmt.Sprintf("%#v", ingress.Status))
 	host := ""
 	if networkConfig.IngressSpec.Rules != nil && networkConfig.IngressSpec.Rules[0].Host != "" {
 		host = networkConfig.IngressSpec.Rules[0].Host
@@ -649,26 +1026,257 @@ func (r *CryostatReconciler) createIngressForService(controller *operatorv1beta1
 	}, nil
 }
 
-func (r *CryostatReconciler) createObjectIfNotExists(ctx context.Context, ns types.NamespacedName, found client.Object, toCreate client.Object) error {
-	logger := r.Log.WithValues("Request.Namespace", ns.Namespace, "Name", ns.Name, "Kind", fmt.Sprintf("%T", toCreate))
-	err := r.Client.Get(ctx, ns, found)
-	if err != nil && errors.IsNotFound(err) {
-		logger.Info("Not found")
-		if err := r.Client.Create(ctx, toCreate); err != nil {
-			logger.Error(err, "Could not be created")
+// Field manager used to identify changes made by the operator
+const operatorFieldManager = "cryostat-operator"
+
+func (r *CryostatReconciler) createOrUpdate(ctx context.Context, obj client.Object,
+	mutate controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	return createOrUpdate(ctx, r.Client, r.Scheme, obj, mutate)
+}
+
+// createOrUpdate behaves like controllerutil.CreateOrUpdate, but identifies the operator
+// as the field manager of any changes it makes. The mutate function must only modify
+// the fields managed by the operator, so that changes made by users to other fields
+// are preserved.
+func createOrUpdate(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj client.Object,
+	mutate controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	key := client.ObjectKeyFromObject(obj)
+	// Decoding into a populated object merges map fields, such as labels, rather
+	// than replacing them. Fetch into a zeroed object so the existing state is exact.
+	desired := obj.DeepCopyObject()
+	objValue := reflect.ValueOf(obj).Elem()
+	objValue.Set(reflect.Zero(objValue.Type()))
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		// Unstructured objects need their type to be fetched
+		u.SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+	}
+	err := c.Get(ctx, key, obj)
+	if err != nil {
+		objValue.Set(reflect.ValueOf(desired).Elem())
+		if !errors.IsNotFound(err) {
+			return controllerutil.OperationResultNone, err
+		}
+		if err := mutate(); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+		if err := addToInventory(ctx, scheme, obj, true); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+		if err := c.Create(ctx, obj, client.FieldOwner(operatorFieldManager)); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+		return controllerutil.OperationResultCreated, nil
+	}
+
+	existing := obj.DeepCopyObject()
+	if err := mutate(); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if client.ObjectKeyFromObject(obj) != key {
+		return controllerutil.OperationResultNone, fmt.Errorf("mutate function must not change the name or namespace of %s", key)
+	}
+	if err := addToInventory(ctx, scheme, obj, true); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if equality.Semantic.DeepEqual(existing, obj) {
+		return controllerutil.OperationResultNone, nil
+	}
+	if err := c.Update(ctx, obj, client.FieldOwner(operatorFieldManager)); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	return controllerutil.OperationResultUpdated, nil
+}
+
+// mergeLabelsAndAnnotations sets the provided labels and annotations on an object,
+// while retaining any other labels and annotations added by users
+func mergeLabelsAndAnnotations(dest *metav1.ObjectMeta, labels map[string]string, annotations map[string]string) {
+	for key, val := range labels {
+		if dest.Labels == nil {
+			dest.Labels = map[string]string{}
+		}
+		dest.Labels[key] = val
+	}
+	for key, val := range annotations {
+		metav1.SetMetaDataAnnotation(dest, key, val)
+	}
+}
+
+// replaceManagedLabelsAndAnnotations sets the provided labels and annotations on an object,
+// and removes any labels and annotations that the operator previously set but are no longer
+// provided. The keys set by the operator are recorded in annotations on the object, so that
+// labels and annotations added by users are retained.
+func replaceManagedLabelsAndAnnotations(dest *metav1.ObjectMeta, labels map[string]string,
+	annotations map[string]string) {
+	removeStaleKeys(dest.Labels, dest.Annotations[managedLabelsAnnotation], labels)
+	removeStaleKeys(dest.Annotations, dest.Annotations[managedAnnotationsAnnotation], annotations)
+	mergeLabelsAndAnnotations(dest, labels, annotations)
+	setManagedKeys(dest, managedLabelsAnnotation, labels)
+	setManagedKeys(dest, managedAnnotationsAnnotation, annotations)
+}
+
+// removeStaleKeys deletes the keys in the comma-separated list of previously managed keys
+// that are not present in the desired map
+func removeStaleKeys(current map[string]string, managed string, desired map[string]string) {
+	if len(managed) == 0 {
+		return
+	}
+	for _, key := range strings.Split(managed, ",") {
+		if _, pres := desired[key]; !pres {
+			delete(current, key)
+		}
+	}
+}
+
+// setManagedKeys records the keys of the provided map in the named annotation
+func setManagedKeys(dest *metav1.ObjectMeta, annotation string, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		delete(dest.Annotations, annotation)
+		return
+	}
+	sort.Strings(keys)
+	metav1.SetMetaDataAnnotation(dest, annotation, strings.Join(keys, ","))
+}
+
+func (r *CryostatReconciler) createOrUpdateHorizontalPodAutoscaler(ctx context.Context,
+	hpa *autoscalingv2beta2.HorizontalPodAutoscaler, owner metav1.Object) error {
+	hpaCopy := hpa.DeepCopy()
+	op, err := r.createOrUpdate(ctx, hpa, func() error {
+		if err := controllerutil.SetControllerReference(owner, hpa, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&hpa.ObjectMeta, hpaCopy.Labels, hpaCopy.Annotations)
+		// Update HorizontalPodAutoscaler spec
+		hpa.Spec = hpaCopy.Spec
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("HorizontalPodAutoscaler %s", op), "name", hpa.Name, "namespace", hpa.Namespace)
+	return nil
+}
+
+func (r *CryostatReconciler) createOrUpdatePodDisruptionBudget(ctx context.Context,
+	pdb *policyv1beta1.PodDisruptionBudget, owner metav1.Object) error {
+	pdbCopy := pdb.DeepCopy()
+	op, err := r.createOrUpdate(ctx, pdb, func() error {
+		if err := controllerutil.SetControllerReference(owner, pdb, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&pdb.ObjectMeta, pdbCopy.Labels, pdbCopy.Annotations)
+		// Update PodDisruptionBudget spec
+		pdb.Spec = pdbCopy.Spec
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("PodDisruptionBudget %s", op), "name", pdb.Name, "namespace", pdb.Namespace)
+	return nil
+}
+
+func (r *CryostatReconciler) createOrUpdatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim,
+	owner metav1.Object, expand bool) error {
+	pvcCopy := pvc.DeepCopy()
+	op, err := r.createOrUpdate(ctx, pvc, func() error {
+		if err := controllerutil.SetControllerReference(owner, pvc, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&pvc.ObjectMeta, pvcCopy.Labels, pvcCopy.Annotations)
+		// The PVC spec is mostly immutable once created, so it is only set when creating the PVC.
+		// The requested storage may be increased if supported by the PVC's StorageClass.
+		if expand {
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = pvcCopy.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("PersistentVolumeClaim %s", op), "name", pvc.Name, "namespace", pvc.Namespace)
+	return nil
+}
+
+func (r *CryostatReconciler) createOrUpdateCredentialsSecret(ctx context.Context, secret *corev1.Secret,
+	owner metav1.Object) error {
+	secretCopy := secret.DeepCopy()
+	op, err := r.createOrUpdate(ctx, secret, func() error {
+		if err := controllerutil.SetControllerReference(owner, secret, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&secret.ObjectMeta, secretCopy.Labels, secretCopy.Annotations)
+		// Don't replace existing credentials, since passwords are psuedorandomly generated.
+		// Only restore any keys that have been removed.
+		for key, val := range secretCopy.StringData {
+			_, inData := secret.Data[key]
+			_, inStringData := secret.StringData[key]
+			if !inData && !inStringData {
+				if secret.StringData == nil {
+					secret.StringData = map[string]string{}
+				}
+				secret.StringData[key] = val
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("Secret %s", op), "name", secret.Name, "namespace", secret.Namespace)
+	return nil
+}
+
+func (r *CryostatReconciler) createOrUpdateService(ctx context.Context, svc *corev1.Service, owner metav1.Object) error {
+	svcCopy := svc.DeepCopy()
+	op, err := r.createOrUpdate(ctx, svc, func() error {
+		if err := controllerutil.SetControllerReference(owner, svc, r.Scheme); err != nil {
 			return err
-		} else {
-			logger.Info("Created")
-			found = toCreate
 		}
-	} else if err != nil {
-		logger.Error(err, "Could not be read")
+		replaceManagedLabelsAndAnnotations(&svc.ObjectMeta, svcCopy.Labels, svcCopy.Annotations)
+		// Update the Service spec fields managed by the operator. ClusterIP is
+		// assigned by Kubernetes and must be left alone.
+		svc.Spec.Type = svcCopy.Spec.Type
+		svc.Spec.Selector = svcCopy.Spec.Selector
+		svc.Spec.Ports = mergeServicePorts(svc.Spec.Type, svc.Spec.Ports, svcCopy.Spec.Ports)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
-	logger.Info("Already exists")
+	r.Log.Info(fmt.Sprintf("Service %s", op), "name", svc.Name, "namespace", svc.Namespace)
 	return nil
 }
 
+// mergeServicePorts returns the desired Service ports, retaining any node ports and
+// protocols that were assigned to the existing ports with the same names
+func mergeServicePorts(svcType corev1.ServiceType, existing []corev1.ServicePort,
+	desired []corev1.ServicePort) []corev1.ServicePort {
+	result := make([]corev1.ServicePort, len(desired))
+	for idx, port := range desired {
+		result[idx] = port
+		for _, old := range existing {
+			if old.Name != port.Name {
+				continue
+			}
+			if len(port.Protocol) == 0 {
+				result[idx].Protocol = old.Protocol
+			}
+			// Node ports are only valid for NodePort and LoadBalancer Services
+			if port.NodePort == 0 && svcType != corev1.ServiceTypeClusterIP {
+				result[idx].NodePort = old.NodePort
+			}
+		}
+	}
+	return result
+}
+
 func (r *CryostatReconciler) createRBAC(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
 	// Create ServiceAccount
 	sa, err := resources.NewServiceAccountForCR(cr, r.IsOpenShift)
@@ -676,22 +1284,12 @@ func (r *CryostatReconciler) createRBAC(ctx context.Context, cr *operatorv1beta1
 		return err
 	}
 	newSA := sa.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
+	op, err := r.createOrUpdate(ctx, sa, func() error {
 		if err := controllerutil.SetControllerReference(cr, sa, r.Scheme); err != nil {
 			return err
 		}
-		// TODO just replace the labels and annotations we manage, once we allow the user to configure
-		// ServiceAccount annotations/labels in the CR, we can simply overwrite them all
-		for key, val := range newSA.GetAnnotations() {
-			metav1.SetMetaDataAnnotation(&sa.ObjectMeta, key, val)
-		}
-		if sa.Labels == nil {
-			sa.Labels = map[string]string{}
-		}
-		for key, val := range newSA.GetLabels() {
-			// TODO use metav1.SetMetaDataLabel when updating client-go, replace above initialization
-			sa.Labels[key] = val
-		}
+		// Just replace the labels and annotations we manage
+		mergeLabelsAndAnnotations(&sa.ObjectMeta, newSA.GetLabels(), newSA.GetAnnotations())
 		// Pod needs SA token, do not allow to be disabled
 		sa.AutomountServiceAccountToken = newSA.AutomountServiceAccountToken
 		// Secrets, ImagePullSecrets are modified by Kubernetes/OpenShift
@@ -704,30 +1302,51 @@ func (r *CryostatReconciler) createRBAC(ctx context.Context, cr *operatorv1beta1
 
 	// Create Role
 	role := resources.NewRoleForCR(cr)
-	if err := controllerutil.SetControllerReference(cr, role, r.Scheme); err != nil {
-		return err
-	}
-	if err := r.createObjectIfNotExists(ctx, types.NamespacedName{Name: role.Name, Namespace: role.Namespace},
-		&rbacv1.Role{}, role); err != nil {
+	newRole := role.DeepCopy()
+	op, err = r.createOrUpdate(ctx, role, func() error {
+		if err := controllerutil.SetControllerReference(cr, role, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&role.ObjectMeta, newRole.Labels, newRole.Annotations)
+		role.Rules = newRole.Rules
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	r.Log.Info(fmt.Sprintf("Role %s", op), "name", role.Name, "namespace", role.Namespace)
 
 	// Create RoleBinding
 	binding := resources.NewRoleBindingForCR(cr)
-	if err := controllerutil.SetControllerReference(cr, binding, r.Scheme); err != nil {
-		return err
-	}
-	if err := r.createObjectIfNotExists(ctx, types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace},
-		&rbacv1.RoleBinding{}, binding); err != nil {
+	newBinding := binding.DeepCopy()
+	op, err = r.createOrUpdate(ctx, binding, func() error {
+		if err := controllerutil.SetControllerReference(cr, binding, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&binding.ObjectMeta, newBinding.Labels, newBinding.Annotations)
+		binding.Subjects = newBinding.Subjects
+		// RoleRef is immutable, but never changes for a given Cryostat
+		binding.RoleRef = newBinding.RoleRef
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	r.Log.Info(fmt.Sprintf("RoleBinding %s", op), "name", binding.Name, "namespace", binding.Namespace)
 
 	// Create ClusterRoleBinding
 	clusterBinding := resources.NewClusterRoleBindingForCR(cr)
-	if err := r.createObjectIfNotExists(ctx, types.NamespacedName{Name: clusterBinding.Name},
-		&rbacv1.ClusterRoleBinding{}, clusterBinding); err != nil {
+	newClusterBinding := clusterBinding.DeepCopy()
+	op, err = r.createOrUpdate(ctx, clusterBinding, func() error {
+		mergeLabelsAndAnnotations(&clusterBinding.ObjectMeta, newClusterBinding.Labels, newClusterBinding.Annotations)
+		clusterBinding.Subjects = newClusterBinding.Subjects
+		clusterBinding.RoleRef = newClusterBinding.RoleRef
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	r.Log.Info(fmt.Sprintf("ClusterRoleBinding %s", op), "name", clusterBinding.Name)
 	// ClusterRoleBinding can't be owned by namespaced CR, clean up using finalizer
 
 	return nil
@@ -752,29 +1371,43 @@ func (r *CryostatReconciler) deleteClusterRoleBinding(ctx context.Context, cr *o
 
 func (r *CryostatReconciler) getImageTags() *resources.ImageTags {
 	return &resources.ImageTags{
-		CoreImageTag:       r.getEnvOrDefault(coreImageTagEnv, resources.DefaultCoreImageTag),
-		DatasourceImageTag: r.getEnvOrDefault(datasourceImageTagEnv, resources.DefaultDatasourceImageTag),
-		GrafanaImageTag:    r.getEnvOrDefault(grafanaImageTagEnv, resources.DefaultGrafanaImageTag),
-		ReportsImageTag:    r.getEnvOrDefault(reportsImageTagEnv, resources.DefaultReportsImageTag),
+		CoreImageTag:       getEnvOrDefault(r, coreImageTagEnv, resources.DefaultCoreImageTag),
+		DatasourceImageTag: getEnvOrDefault(r, datasourceImageTagEnv, resources.DefaultDatasourceImageTag),
+		GrafanaImageTag:    getEnvOrDefault(r, grafanaImageTagEnv, resources.DefaultGrafanaImageTag),
+		ReportsImageTag:    getEnvOrDefault(r, reportsImageTagEnv, resources.DefaultReportsImageTag),
 	}
 }
 
-func (r *CryostatReconciler) getEnvOrDefault(name string, defaultVal string) string {
-	val := r.GetEnv(name)
+func getEnvOrDefault(env common.OSUtils, name string, defaultVal string) string {
+	val := env.GetEnv(name)
 	if len(val) > 0 {
 		return val
 	}
 	return defaultVal
 }
 
+// Pod template annotation containing a digest of the secrets and config maps
+// mounted by the pod
+const configHashAnnotation = "operator.cryostat.io/config-hash"
+
+// Deployment annotation marking that Cryostat was scaled down to migrate its storage,
+// and should be scaled up again once the migration is no longer in progress
+const storageScaledDownAnnotation = "operator.cryostat.io/storage-scaled-down"
+
+// Annotations listing the labels and annotations that the operator has set on an object
+const (
+	managedLabelsAnnotation      = "operator.cryostat.io/managed-labels"
+	managedAnnotationsAnnotation = "operator.cryostat.io/managed-annotations"
+)
+
 // fsGroup to use when not constrained
 const defaultFSGroup int64 = 18500
 
-func (r *CryostatReconciler) getFSGroup(ctx context.Context, namespace string) (*int64, error) {
-	if r.IsOpenShift {
+func getFSGroup(ctx context.Context, c client.Client, isOpenShift bool, namespace string) (*int64, error) {
+	if isOpenShift {
 		// Check namespace for supplemental groups annotation
 		ns := &corev1.Namespace{}
-		err := r.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+		err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns)
 		if err != nil {
 			return nil, err
 		}
@@ -804,7 +1437,16 @@ func parseSupGroups(supGroups string) (*int64, error) {
 
 func (r *CryostatReconciler) createConsoleLink(ctx context.Context, cr *operatorv1beta1.Cryostat, url string) error {
 	link := resources.NewConsoleLink(cr, url)
-	return r.createObjectIfNotExists(ctx, types.NamespacedName{Name: link.Name}, &consolev1.ConsoleLink{}, link)
+	linkSpec := link.Spec.DeepCopy()
+	op, err := r.createOrUpdate(ctx, link, func() error {
+		link.Spec = *linkSpec
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("ConsoleLink %s", op), "name", link.Name)
+	return nil
 }
 
 func (r *CryostatReconciler) deleteConsoleLink(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
@@ -886,7 +1528,7 @@ func (r *CryostatReconciler) deleteCorsAllowedOrigins(ctx context.Context, allow
 func (r *CryostatReconciler) updateCondition(ctx context.Context, cr *operatorv1beta1.Cryostat,
 	condType operatorv1beta1.CryostatConditionType, status metav1.ConditionStatus, reason string, message string) error {
 	reqLogger := r.Log.WithValues("Request.Namespace", cr.Namespace, "Request.Name", cr.Name)
-	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+	setStatusCondition(cr, metav1.Condition{
 		Type:    string(condType),
 		Status:  status,
 		Reason:  reason,
@@ -899,6 +1541,43 @@ func (r *CryostatReconciler) updateCondition(ctx context.Context, cr *operatorv1
 	return err
 }
 
+// updateReadyCondition sets the Ready condition based on the TLS and
+// deployment conditions of the Cryostat, and updates its status
+func (r *CryostatReconciler) updateReadyCondition(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
+	// Conditions that must be true for this Cryostat to be ready
+	required := []operatorv1beta1.CryostatConditionType{
+		operatorv1beta1.ConditionTypeTLSSetupComplete,
+		operatorv1beta1.ConditionTypeMainDeploymentAvailable,
+	}
+	// Conditions that must not be true for this Cryostat to be ready
+	failures := []operatorv1beta1.CryostatConditionType{
+		operatorv1beta1.ConditionTypeMainDeploymentReplicaFailure,
+	}
+	if resources.IsReportsEnabled(cr) {
+		required = append(required, operatorv1beta1.ConditionTypeReportsDeploymentAvailable)
+		failures = append(failures, operatorv1beta1.ConditionTypeReportsDeploymentReplicaFailure)
+	}
+
+	notReady := []string{}
+	for _, condType := range required {
+		if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(condType)) {
+			notReady = append(notReady, string(condType))
+		}
+	}
+	for _, condType := range failures {
+		if meta.IsStatusConditionTrue(cr.Status.Conditions, string(condType)) {
+			notReady = append(notReady, string(condType))
+		}
+	}
+
+	if len(notReady) > 0 {
+		return r.updateCondition(ctx, cr, operatorv1beta1.ConditionTypeReady, metav1.ConditionFalse,
+			reasonComponentsNotReady, "Conditions not satisfied: "+strings.Join(notReady, ", ")+".")
+	}
+	return r.updateCondition(ctx, cr, operatorv1beta1.ConditionTypeReady, metav1.ConditionTrue,
+		reasonAllComponentsReady, "All Cryostat components are ready.")
+}
+
 func (r *CryostatReconciler) updateConditionsFromDeployment(ctx context.Context, cr *operatorv1beta1.Cryostat,
 	deployKey types.NamespacedName, mapping deploymentConditionTypeMap) error {
 	reqLogger := r.Log.WithValues("Request.Namespace", cr.Namespace, "Request.Name", cr.Name)
@@ -916,7 +1595,7 @@ func (r *CryostatReconciler) updateConditionsFromDeployment(ctx context.Context,
 		if condition == nil {
 			removeConditionIfPresent(cr, condType)
 		} else {
-			meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			setStatusCondition(cr, metav1.Condition{
 				Type:    string(condType),
 				Status:  metav1.ConditionStatus(condition.Status),
 				Reason:  condition.Reason,
@@ -931,6 +1610,51 @@ func (r *CryostatReconciler) updateConditionsFromDeployment(ctx context.Context,
 	return err
 }
 
+// setDeploymentStatus records the addresses and images of the deployed
+// Cryostat components in the status of the Cryostat CR
+func setDeploymentStatus(cr *operatorv1beta1.Cryostat, specs *resources.ServiceSpecs, imageTags *resources.ImageTags) {
+	images := &operatorv1beta1.ImageStatus{
+		Core: imageTags.CoreImageTag,
+	}
+	if !cr.Spec.Minimal {
+		images.Datasource = imageTags.DatasourceImageTag
+		images.Grafana = imageTags.GrafanaImageTag
+	}
+	if specs.ReportsURL != nil {
+		images.Reports = imageTags.ReportsImageTag
+	}
+
+	// The application and Grafana URLs are only known if exposed outside the cluster
+	cr.Status.ApplicationURL = ""
+	if specs.CoreURL != nil {
+		cr.Status.ApplicationURL = specs.CoreURL.String()
+	}
+	cr.Status.GrafanaURL = ""
+	if specs.GrafanaURL != nil {
+		cr.Status.GrafanaURL = specs.GrafanaURL.String()
+	}
+	cr.Status.ReportsURL = ""
+	if specs.ReportsURL != nil {
+		cr.Status.ReportsURL = specs.ReportsURL.String()
+	}
+	cr.Status.Images = images
+	cr.Status.Version = getImageVersion(imageTags.CoreImageTag)
+}
+
+// getImageVersion returns the tag of an image reference, or an empty
+// string if the image is referenced by digest or has no tag
+func getImageVersion(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	// A colon before the last slash separates a registry host from its port
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 || idx < strings.LastIndex(image, "/") {
+		return ""
+	}
+	return image[idx+1:]
+}
+
 func getProtocol(tlsConfig *openshiftv1.TLSConfig) string {
 	if tlsConfig == nil {
 		return "http"
@@ -956,6 +1680,81 @@ func getNetworkConfig(controller *operatorv1beta1.Cryostat, svc *corev1.Service)
 	}
 }
 
+// setStatusCondition sets a condition in the status of the Cryostat CR,
+// recording the generation of the CR the condition is based on
+func setStatusCondition(cr *operatorv1beta1.Cryostat, condition metav1.Condition) {
+	meta.SetStatusCondition(&cr.Status.Conditions, condition)
+	// SetStatusCondition does not update the generation of an existing condition
+	found := meta.FindStatusCondition(cr.Status.Conditions, condition.Type)
+	found.ObservedGeneration = cr.Generation
+}
+
+// overridePodTemplate applies the override, if any, to the pod template of the
+// deployment and reports the outcome in the provided condition. An override that
+// cannot be applied is ignored, so the deployment is still kept up to date.
+func overridePodTemplate(reqLogger logr.Logger, cr *operatorv1beta1.Cryostat, deployment *appsv1.Deployment,
+	override *operatorv1beta1.PodTemplateOverride, condType operatorv1beta1.CryostatConditionType) {
+	if override == nil {
+		removeConditionIfPresent(cr, condType)
+		return
+	}
+	template, err := resources.ApplyPodTemplateOverride(&deployment.Spec.Template, override)
+	if err != nil {
+		reqLogger.Error(err, "failed to apply pod template override", "Deployment.Name", deployment.Name)
+		reason := reasonOperatorFieldsModified
+		if goerrors.Is(err, resources.ErrPodTemplatePatchInvalid) {
+			reason = reasonInvalidPatch
+		}
+		setStatusCondition(cr, metav1.Condition{
+			Type:    string(condType),
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: err.Error(),
+		})
+		return
+	}
+	deployment.Spec.Template = *template
+	setStatusCondition(cr, metav1.Condition{
+		Type:    string(condType),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonOverrideApplied,
+		Message: "The pod template override was applied",
+	})
+}
+
+// updateEnvironmentConflictCondition reports whether any extra environment
+// variables requested in the Cryostat CR were ignored because the operator sets them
+func updateEnvironmentConflictCondition(cr *operatorv1beta1.Cryostat, conflicts map[string][]string) {
+	if cr.Spec.EnvironmentOptions == nil && (cr.Spec.ReportOptions == nil || cr.Spec.ReportOptions.EnvironmentOptions == nil) {
+		removeConditionIfPresent(cr, operatorv1beta1.ConditionTypeEnvironmentConflict)
+		return
+	}
+	if len(conflicts) == 0 {
+		setStatusCondition(cr, metav1.Condition{
+			Type:    string(operatorv1beta1.ConditionTypeEnvironmentConflict),
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonNoEnvironmentConflicts,
+			Message: "All extra environment variables were applied",
+		})
+		return
+	}
+	containers := make([]string, 0, len(conflicts))
+	for container := range conflicts {
+		containers = append(containers, container)
+	}
+	sort.Strings(containers)
+	details := make([]string, 0, len(containers))
+	for _, container := range containers {
+		details = append(details, fmt.Sprintf("%s (%s)", container, strings.Join(conflicts[container], ", ")))
+	}
+	setStatusCondition(cr, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeEnvironmentConflict),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonReservedEnvironmentVariables,
+		Message: "Ignored extra environment variables that are set by the operator: " + strings.Join(details, "; "),
+	})
+}
+
 func removeConditionIfPresent(cr *operatorv1beta1.Cryostat, condType ...operatorv1beta1.CryostatConditionType) {
 	for _, ct := range condType {
 		found := meta.FindStatusCondition(cr.Status.Conditions, string(ct))
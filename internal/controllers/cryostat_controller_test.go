@@ -38,7 +38,11 @@ package controllers_test
 
 import (
 	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -49,23 +53,31 @@ import (
 	consolev1 "github.com/openshift/api/console/v1"
 	openshiftv1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"software.sslmate.com/src/go-pkcs12"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	"github.com/cryostatio/cryostat-operator/internal/test"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -77,6 +89,7 @@ type cryostatTestInput struct {
 	minimal        bool
 	reportReplicas int32
 	externalTLS    bool
+	operatorCA     bool
 	test.TestReconcilerConfig
 }
 
@@ -166,9 +179,34 @@ var _ = Describe("CryostatController", func() {
 			It("should set ApplicationURL in CR Status", func() {
 				t.expectStatusApplicationURL()
 			})
+			It("should report deployment details in CR Status", func() {
+				t.reconcileCryostatFully()
+				t.checkStatusDeploymentDetails()
+			})
 			It("should create deployment and set owner", func() {
 				t.expectDeployment()
 			})
+			It("should set Ready condition", func() {
+				t.reconcileCryostatFully()
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeReady, metav1.ConditionFalse,
+					"ComponentsNotReady")
+				t.checkConditionMessage(operatorv1beta1.ConditionTypeReady, "MainDeploymentAvailable")
+			})
+			It("should set ObservedGeneration in CR Status", func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Generation = 2
+				})
+				t.reconcileCryostat()
+
+				cr := &operatorv1beta1.Cryostat{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cr.Status.ObservedGeneration).To(Equal(int64(2)))
+				condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeReady))
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+			})
 			It("should set TLSSetupComplete condition", func() {
 				t.reconcileCryostatFully()
 				t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
@@ -185,6 +223,8 @@ var _ = Describe("CryostatController", func() {
 					t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentProgressing, metav1.ConditionTrue,
 						"TestProgressing")
 					t.checkConditionAbsent(operatorv1beta1.ConditionTypeMainDeploymentReplicaFailure)
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeReady, metav1.ConditionFalse,
+						"ComponentsNotReady")
 				})
 				Context("then becomes available", func() {
 					JustBeforeEach(func() {
@@ -196,6 +236,8 @@ var _ = Describe("CryostatController", func() {
 						t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentProgressing, metav1.ConditionTrue,
 							"TestProgressing")
 						t.checkConditionAbsent(operatorv1beta1.ConditionTypeMainDeploymentReplicaFailure)
+						t.checkConditionPresent(operatorv1beta1.ConditionTypeReady, metav1.ConditionTrue,
+							"AllComponentsReady")
 					})
 				})
 				Context("then fails to roll out", func() {
@@ -209,6 +251,9 @@ var _ = Describe("CryostatController", func() {
 							"TestProgressing")
 						t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentReplicaFailure, metav1.ConditionTrue,
 							"TestReplicaFailure")
+						t.checkConditionPresent(operatorv1beta1.ConditionTypeReady, metav1.ConditionFalse,
+							"ComponentsNotReady")
+						t.checkConditionMessage(operatorv1beta1.ConditionTypeReady, "MainDeploymentReplicaFailure")
 					})
 				})
 			})
@@ -239,6 +284,10 @@ var _ = Describe("CryostatController", func() {
 			It("should set ApplicationURL in CR Status", func() {
 				t.expectStatusApplicationURL()
 			})
+			It("should report deployment details in CR Status", func() {
+				t.reconcileCryostatFully()
+				t.checkStatusDeploymentDetails()
+			})
 			It("should create deployment and set owner", func() {
 				t.expectDeployment()
 			})
@@ -396,6 +445,19 @@ var _ = Describe("CryostatController", func() {
 				t.checkReportsDeployment()
 				t.checkService("cryostat-reports", test.NewReportsService())
 			})
+			It("should report deployment details in CR Status", func() {
+				t.checkStatusDeploymentDetails()
+			})
+			It("should require the reports deployment for the Ready condition", func() {
+				t.makeDeploymentAvailable("cryostat")
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeReady, metav1.ConditionFalse,
+					"ComponentsNotReady")
+				t.checkConditionMessage(operatorv1beta1.ConditionTypeReady, "ReportsDeploymentAvailable")
+
+				t.makeDeploymentAvailable("cryostat-reports")
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeReady, metav1.ConditionTrue,
+					"AllComponentsReady")
+			})
 			Context("with cert-manager disabled", func() {
 				BeforeEach(func() {
 					disable := false
@@ -711,6 +773,47 @@ var _ = Describe("CryostatController", func() {
 				t.expectEmptyDir(test.NewDefaultEmptyDir())
 			})
 		})
+		Context("Switching from a PVC to an EmptyDir", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.StorageOptions = test.NewCryostatWithDefaultEmptyDir().Spec.StorageOptions
+				})
+				t.reconcileCryostat()
+			})
+			It("should keep the PVC", func() {
+				t.getPVC("cryostat")
+			})
+			It("should keep mounting the PVC", func() {
+				template := t.getDeploymentTemplate("cryostat")
+				volume := template.Spec.Volumes[0]
+				Expect(volume.EmptyDir).To(BeNil())
+				Expect(volume.PersistentVolumeClaim).ToNot(BeNil())
+				Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("cryostat"))
+			})
+			It("should report the switch is blocked", func() {
+				t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageMigrated, metav1.ConditionFalse, "EmptyDirBlocked")
+			})
+			Context("after the PVC is deleted", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.getPVC("cryostat"))
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostat()
+				})
+				It("should switch to the EmptyDir", func() {
+					template := t.getDeploymentTemplate("cryostat")
+					volume := template.Spec.Volumes[0]
+					Expect(volume.PersistentVolumeClaim).To(BeNil())
+					Expect(volume.EmptyDir).ToNot(BeNil())
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.Storage).To(BeNil())
+					t.checkConditionAbsent(operatorv1beta1.ConditionTypeStorageMigrated)
+				})
+			})
+		})
 		Context("with custom EmptyDir config with requested spec", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewCryostatWithEmptyDirSpec())
@@ -719,6 +822,304 @@ var _ = Describe("CryostatController", func() {
 				t.expectEmptyDir(test.NewEmptyDirWithSpec())
 			})
 		})
+		Context("with an unfinished CryostatRestore", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat(), test.NewCryostatRestoreRunning())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the PVC", func() {
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should not create the deployment", func() {
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+			It("should set RestoreComplete condition", func() {
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeRestoreComplete, metav1.ConditionFalse,
+					"WaitingForRestore")
+			})
+			Context("that then succeeds", func() {
+				JustBeforeEach(func() {
+					restore := &operatorv1beta1.CryostatRestore{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-restore", Namespace: "default"}, restore)
+					Expect(err).ToNot(HaveOccurred())
+					restore.Status.Phase = operatorv1beta1.StorageOperationSucceeded
+					err = t.Client.Status().Update(context.Background(), restore)
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostat()
+				})
+				It("should create the deployment", func() {
+					t.checkMainDeployment()
+				})
+				It("should remove RestoreComplete condition", func() {
+					t.checkConditionAbsent(operatorv1beta1.ConditionTypeRestoreComplete)
+				})
+			})
+		})
+		Context("with a CryostatRestore from a completed backup", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat(), test.NewCryostatBackupSucceeded(), test.NewCryostatRestore())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not create the deployment", func() {
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+		Context("with a CryostatRestore from a backup that does not exist", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat(), test.NewCryostatRestore())
+			})
+			It("should create the deployment", func() {
+				t.expectDeployment()
+			})
+		})
+		Context("with a CryostatRestore from a backup of a Cryostat that does not exist", func() {
+			BeforeEach(func() {
+				backup := test.NewCryostatBackup()
+				backup.Spec.CryostatName = "other-cryostat"
+				t.objs = append(t.objs, test.NewCryostat(), backup, test.NewCryostatRestore())
+			})
+			It("should create the deployment", func() {
+				t.expectDeployment()
+			})
+		})
+		Context("with a finished CryostatRestore", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat(), test.NewCryostatRestoreSucceeded())
+			})
+			It("should create the deployment", func() {
+				t.expectDeployment()
+			})
+		})
+		Context("with more requested storage", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithPVCSpec())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.StorageOptions.PVC.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("20Gi")
+				})
+				t.setPVCCapacity("cryostat", "10Gi")
+				t.reconcileCryostat()
+			})
+			Context("and an expandable StorageClass", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewStorageClass("cool-storage", true))
+				})
+				It("should expand the PVC", func() {
+					pvc := t.getPVC("cryostat")
+					Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("20Gi")))
+				})
+				It("should not migrate the PVC", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.Storage).To(Equal(&operatorv1beta1.StorageStatus{ClaimName: "cryostat"}))
+					Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageMigrated))).To(BeNil())
+				})
+				It("should report the PVC is resizing", func() {
+					t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageResized, metav1.ConditionFalse, "Resizing")
+				})
+				Context("that then finishes resizing", func() {
+					JustBeforeEach(func() {
+						t.setPVCCapacity("cryostat", "20Gi")
+						t.reconcileCryostat()
+					})
+					It("should report the PVC is resized", func() {
+						t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageResized, metav1.ConditionTrue, "Resized")
+					})
+				})
+			})
+			Context("and a StorageClass that cannot expand", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewStorageClass("cool-storage", false))
+				})
+				It("should not expand the PVC", func() {
+					pvc := t.getPVC("cryostat")
+					Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("10Gi")))
+				})
+				It("should migrate to a new PVC", func() {
+					t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageMigrated, metav1.ConditionFalse, "ScalingDown")
+					pvc := t.getPVC(t.getMigrationClaimName())
+					Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("20Gi")))
+				})
+			})
+		})
+		Context("with more requested storage and a default StorageClass", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat(), test.NewDefaultStorageClass(true))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.StorageOptions = &operatorv1beta1.StorageConfiguration{
+						PVC: &operatorv1beta1.PersistentVolumeClaimConfig{
+							Spec: &corev1.PersistentVolumeClaimSpec{
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: resource.MustParse("1Gi"),
+									},
+								},
+							},
+						},
+					}
+				})
+				t.reconcileCryostat()
+			})
+			It("should expand the PVC", func() {
+				pvc := t.getPVC("cryostat")
+				Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1Gi")))
+			})
+		})
+		Context("with a new StorageClass", func() {
+			var targetClaim string
+			BeforeEach(func() {
+				coreImg := "my/core-image:1.0"
+				t.EnvCoreImageTag = &coreImg
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.StorageOptions = test.NewCryostatWithPVCSpec().Spec.StorageOptions
+				})
+				t.reconcileCryostat()
+				targetClaim = t.getMigrationClaimName()
+			})
+			It("should create a new PVC", func() {
+				pvc := t.getPVC(targetClaim)
+				expected := test.NewCustomPVC()
+				expected.Name = targetClaim
+				delete(expected.Labels, "operator.cryostat.io/active-storage")
+				checkMetadata(pvc, expected)
+				Expect(pvc.Spec).To(Equal(expected.Spec))
+			})
+			It("should keep the existing PVC", func() {
+				pvc := t.getPVC("cryostat")
+				Expect(pvc.Spec.StorageClassName).To(BeNil())
+			})
+			It("should scale down the deployment", func() {
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deployment.Spec.Replicas).ToNot(BeNil())
+				Expect(*deployment.Spec.Replicas).To(Equal(int32(0)))
+			})
+			It("should report the deployment is scaling down", func() {
+				t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageMigrated, metav1.ConditionFalse, "ScalingDown")
+			})
+			Context("after scaling down", func() {
+				JustBeforeEach(func() {
+					t.reconcileCryostat()
+				})
+				It("should create a Job to copy the data", func() {
+					checkStorageJob(t.Client, test.NewMigrationJob(targetClaim))
+				})
+				It("should report the data is being copied", func() {
+					t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageMigrated, metav1.ConditionFalse, "CopyingData")
+				})
+				Context("when the Job succeeds", func() {
+					JustBeforeEach(func() {
+						setJobCondition(t.Client, targetClaim+"-migration", batchv1.JobComplete, "")
+						t.reconcileCryostat()
+					})
+					It("should switch to the new PVC", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.Storage).To(Equal(&operatorv1beta1.StorageStatus{ClaimName: targetClaim}))
+						deployment := &appsv1.Deployment{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+						Expect(err).ToNot(HaveOccurred())
+						volume := deployment.Spec.Template.Spec.Volumes[0]
+						Expect(volume.PersistentVolumeClaim).ToNot(BeNil())
+						Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal(targetClaim))
+					})
+					It("should scale up the deployment", func() {
+						deployment := &appsv1.Deployment{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(deployment.Spec.Replicas).To(BeNil())
+					})
+					It("should delete the old PVC", func() {
+						pvc := &corev1.PersistentVolumeClaim{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, pvc)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should delete the Job", func() {
+						job := &batchv1.Job{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: targetClaim + "-migration", Namespace: "default"}, job)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should report the migration is complete", func() {
+						t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageMigrated, metav1.ConditionTrue, "MigrationComplete")
+					})
+					It("should label the new PVC as active", func() {
+						pvc := t.getPVC(targetClaim)
+						Expect(pvc.Labels).To(HaveKeyWithValue("operator.cryostat.io/active-storage", "cryostat"))
+					})
+					Context("when the storage status is lost", func() {
+						JustBeforeEach(func() {
+							cr := t.getCryostatInstance()
+							cr.Status.Storage = nil
+							err := t.Client.Status().Update(context.Background(), cr)
+							Expect(err).ToNot(HaveOccurred())
+							t.reconcileCryostat()
+						})
+						It("should keep using the new PVC", func() {
+							cr := t.getCryostatInstance()
+							Expect(cr.Status.Storage).To(Equal(&operatorv1beta1.StorageStatus{ClaimName: targetClaim}))
+							t.getPVC(targetClaim)
+						})
+					})
+					Context("when the user scales down the deployment", func() {
+						JustBeforeEach(func() {
+							deployment := &appsv1.Deployment{}
+							err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+							Expect(err).ToNot(HaveOccurred())
+							replicas := int32(0)
+							deployment.Spec.Replicas = &replicas
+							err = t.Client.Update(context.Background(), deployment)
+							Expect(err).ToNot(HaveOccurred())
+							t.reconcileCryostat()
+						})
+						It("should leave the deployment scaled down", func() {
+							deployment := &appsv1.Deployment{}
+							err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(deployment.Spec.Replicas).ToNot(BeNil())
+							Expect(*deployment.Spec.Replicas).To(Equal(int32(0)))
+						})
+					})
+				})
+				Context("when the Job fails", func() {
+					JustBeforeEach(func() {
+						setJobCondition(t.Client, targetClaim+"-migration", batchv1.JobFailed, "BackoffLimitExceeded")
+						t.reconcileCryostat()
+					})
+					It("should keep using the existing PVC", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.Storage).To(Equal(&operatorv1beta1.StorageStatus{ClaimName: "cryostat"}))
+						t.getPVC("cryostat")
+					})
+					It("should scale up the deployment", func() {
+						deployment := &appsv1.Deployment{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(deployment.Spec.Replicas).To(BeNil())
+					})
+					It("should report the migration failed", func() {
+						t.checkStorageCondition(operatorv1beta1.ConditionTypeStorageMigrated, metav1.ConditionFalse, "MigrationFailed")
+					})
+				})
+			})
+		})
 		Context("with overriden image tags", func() {
 			var mainDeploy, reportsDeploy *appsv1.Deployment
 			BeforeEach(func() {
@@ -749,6 +1150,9 @@ var _ = Describe("CryostatController", func() {
 					t.checkMainDeployment()
 					t.checkReportsDeployment()
 				})
+				It("should report the images and version in CR Status", func() {
+					t.checkStatusDeploymentDetails()
+				})
 				It("should set ImagePullPolicy to Always", func() {
 					containers := mainDeploy.Spec.Template.Spec.Containers
 					Expect(containers).To(HaveLen(3))
@@ -1036,6 +1440,51 @@ var _ = Describe("CryostatController", func() {
 				t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
 					"CertManagerDisabled")
 			})
+			It("should delete certificates and issuers", func() {
+				certs := &certv1.CertificateList{}
+				err := t.Client.List(context.Background(), certs, ctrlclient.InNamespace("default"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(certs.Items).To(BeEmpty())
+				issuers := &certv1.IssuerList{}
+				err = t.Client.List(context.Background(), issuers, ctrlclient.InNamespace("default"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(issuers.Items).To(BeEmpty())
+			})
+			It("should delete the keystore secret", func() {
+				secret := &corev1.Secret{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-keystore", Namespace: "default"}, secret)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+		Context("with objects labelled for the Cryostat", func() {
+			var unowned *corev1.Service
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+				unowned = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "unowned",
+						Namespace: "default",
+						Labels: map[string]string{
+							"operator.cryostat.io/cryostat": "cryostat",
+						},
+					},
+				}
+				t.objs = append(t.objs, unowned)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not delete objects it does not own", func() {
+				svc := &corev1.Service{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "unowned", Namespace: "default"}, svc)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should not delete objects that are still desired", func() {
+				t.reconcileCryostat()
+				t.checkMainDeployment()
+				t.checkService("cryostat", test.NewCryostatService())
+				t.checkService("cryostat-grafana", test.NewGrafanaService())
+			})
 		})
 		Context("Enable cert-manager after being disabled", func() {
 			BeforeEach(func() {
@@ -1079,54 +1528,265 @@ var _ = Describe("CryostatController", func() {
 					"AllCertificatesReady")
 			})
 		})
-		Context("cert-manager missing", func() {
+		Context("with certificate options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithCertificateOptions())
+			})
 			JustBeforeEach(func() {
-				// Replace with an empty RESTMapper
-				t.controller.RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.reconcileCryostatFully()
 			})
-			Context("and enabled", func() {
-				BeforeEach(func() {
-					t.objs = append(t.objs, test.NewCryostat())
+			It("should create certificates signed by the issuer", func() {
+				t.checkCertificatesWithOptions()
+			})
+			It("should not create a self-signed CA", func() {
+				t.expectNoSelfSignedCA()
+			})
+			It("should create deployment with TLS", func() {
+				t.checkMainDeployment()
+			})
+			It("should use the issuer's CA for routes", func() {
+				t.checkRoutes()
+			})
+			It("should set TLSSetupComplete condition", func() {
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"AllCertificatesReady")
+			})
+		})
+		Context("with an issuer that does not provide a CA certificate", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithCertificateOptions())
+			})
+			JustBeforeEach(func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+
+				t.makeCertificatesReady()
+				t.initializeSecrets()
+				secret := t.getSecret("cryostat-tls")
+				delete(secret.Data, resource_definitions.CAKey)
+				err = t.Client.Update(context.Background(), secret)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should requeue with error", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).To(MatchError(ContainSubstring("ca.crt")))
+			})
+			It("should set TLSSetupComplete condition", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).To(HaveOccurred())
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+					"CAUnavailable")
+			})
+		})
+		Context("Use an external issuer after a self-signed CA", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.CertificateOptions = test.NewCertificateConfiguration()
 				})
+				t.reconcileCryostat()
+			})
+			It("should update the certificates", func() {
+				t.checkCertificatesWithOptions()
+			})
+			It("should delete the self-signed CA", func() {
+				t.expectNoSelfSignedCA()
+				secret := &corev1.Secret{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-ca", Namespace: "default"}, secret)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+			It("should use the issuer's CA for routes", func() {
+				t.checkRoutes()
+			})
+		})
+		Context("with the operator TLS provider", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithOperatorCA())
+				t.operatorCA = true
+			})
+			Context("when reconciled", func() {
 				JustBeforeEach(func() {
-					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
-					_, err := t.controller.Reconcile(context.Background(), req)
-					Expect(err).To(HaveOccurred())
+					t.reconcileCryostatFully()
 				})
-				It("should emit a CertManagerUnavailable Event", func() {
-					recorder := t.controller.EventRecorder.(*record.FakeRecorder)
-					var eventMsg string
-					Expect(recorder.Events).To(Receive(&eventMsg))
-					Expect(eventMsg).To(ContainSubstring("CertManagerUnavailable"))
+				It("should create a CA secret", func() {
+					ca := t.checkOperatorCA()
+					Expect(ca.Certificate.Subject.CommonName).To(Equal("ca.cryostat.cryostat"))
 				})
-				It("should set TLSSetupComplete Condition", func() {
-					t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
-						"CertManagerUnavailable")
+				It("should issue certificates signed by the CA", func() {
+					t.checkOperatorCertificates(t.checkOperatorCA())
 				})
-			})
-			Context("and disabled", func() {
-				BeforeEach(func() {
-					t.objs = append(t.objs, test.NewCryostatCertManagerDisabled())
-					t.TLS = false
+				It("should create a keystore for Cryostat", func() {
+					t.checkOperatorKeystore()
 				})
-				JustBeforeEach(func() {
-					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
-					_, err := t.controller.Reconcile(context.Background(), req)
+				It("should not create cert-manager resources", func() {
+					certs := &certv1.CertificateList{}
+					err := t.Client.List(context.Background(), certs, ctrlclient.InNamespace("default"))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(certs.Items).To(BeEmpty())
+					issuers := &certv1.IssuerList{}
+					err = t.Client.List(context.Background(), issuers, ctrlclient.InNamespace("default"))
 					Expect(err).ToNot(HaveOccurred())
+					Expect(issuers.Items).To(BeEmpty())
 				})
-				It("should not emit a CertManagerUnavailable Event", func() {
-					recorder := t.controller.EventRecorder.(*record.FakeRecorder)
-					Expect(recorder.Events).ToNot(Receive())
+				It("should create deployment with TLS", func() {
+					t.checkMainDeployment()
 				})
-				It("should set TLSSetupComplete Condition", func() {
+				It("should create routes with re-encrypt TLS termination", func() {
+					t.checkRoutes()
+				})
+				It("should set TLSSetupComplete condition", func() {
 					t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
-						"CertManagerDisabled")
+						"AllCertificatesReady")
+				})
+				It("should not reissue certificates when reconciled again", func() {
+					secretNames := []string{"cryostat-ca", "cryostat-tls", "cryostat-grafana-tls", "cryostat-reports-tls"}
+					versions := map[string]string{}
+					for _, name := range secretNames {
+						versions[name] = t.getSecret(name).ResourceVersion
+					}
+					t.reconcileCryostat()
+					for _, name := range secretNames {
+						Expect(t.getSecret(name).ResourceVersion).To(Equal(versions[name]), name)
+					}
 				})
 			})
-		})
-		Context("with service options", func() {
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
+			Context("with a certificate due for renewal soon", func() {
+				BeforeEach(func() {
+					ca, err := common.NewCertificateAuthority("ca.cryostat.cryostat", 24*time.Hour)
+					Expect(err).ToNot(HaveOccurred())
+					t.objs = append(t.objs, newOperatorTLSSecret("cryostat-ca", ca, ca))
+				})
+				It("should requeue when the certificate is due for renewal", func() {
+					t.reconcileCryostatFully()
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+					result, err := t.controller.Reconcile(context.Background(), req)
+					Expect(err).ToNot(HaveOccurred())
+					// The CA has a third of its lifetime remaining after 16 hours
+					Expect(result.RequeueAfter).To(BeNumerically("~", 16*time.Hour, 5*time.Minute))
+				})
+			})
+			Context("with a CA due for renewal", func() {
+				var oldCA *common.KeyPair
+				BeforeEach(func() {
+					var err error
+					oldCA, err = common.NewCertificateAuthority("old-ca", time.Minute)
+					Expect(err).ToNot(HaveOccurred())
+					t.objs = append(t.objs, newOperatorTLSSecret("cryostat-ca", oldCA, oldCA))
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should generate a new CA", func() {
+					ca := t.checkOperatorCA()
+					Expect(ca.CertPEM).ToNot(Equal(oldCA.CertPEM))
+				})
+				It("should reissue certificates using the new CA", func() {
+					t.checkOperatorCertificates(t.checkOperatorCA())
+				})
+			})
+			Context("with a certificate issued by another CA", func() {
+				BeforeEach(func() {
+					otherCA, err := common.NewCertificateAuthority("other-ca", time.Hour)
+					Expect(err).ToNot(HaveOccurred())
+					cert, err := common.NewCertificate(otherCA, "cryostat", []string{"cryostat"}, nil, time.Hour)
+					Expect(err).ToNot(HaveOccurred())
+					t.objs = append(t.objs, newOperatorTLSSecret("cryostat-grafana-tls", cert, otherCA))
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should reissue the certificate", func() {
+					t.checkOperatorCertificates(t.checkOperatorCA())
+				})
+			})
+			Context("after using cert-manager", func() {
+				BeforeEach(func() {
+					t.objs[len(t.objs)-1] = test.NewCryostat()
+					t.operatorCA = false
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+					t.operatorCA = true
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						provider := operatorv1beta1.TLSProviderOperator
+						cr.Spec.TLSProvider = &provider
+					})
+					t.reconcileCryostat()
+				})
+				It("should delete cert-manager resources", func() {
+					certs := &certv1.CertificateList{}
+					err := t.Client.List(context.Background(), certs, ctrlclient.InNamespace("default"))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(certs.Items).To(BeEmpty())
+					issuers := &certv1.IssuerList{}
+					err = t.Client.List(context.Background(), issuers, ctrlclient.InNamespace("default"))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(issuers.Items).To(BeEmpty())
+				})
+				It("should replace the certificate secrets", func() {
+					t.checkOperatorCertificates(t.checkOperatorCA())
+					t.checkOperatorKeystore()
+				})
+				It("should update the routes", func() {
+					t.checkRoutes()
+				})
+			})
+		})
+		Context("cert-manager missing", func() {
+			JustBeforeEach(func() {
+				// Replace with an empty RESTMapper
+				t.controller.RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+			})
+			Context("and enabled", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewCryostat())
+				})
+				JustBeforeEach(func() {
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+					_, err := t.controller.Reconcile(context.Background(), req)
+					Expect(err).To(HaveOccurred())
+				})
+				It("should emit a CertManagerUnavailable Event", func() {
+					recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+					var eventMsg string
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("CertManagerUnavailable"))
+				})
+				It("should set TLSSetupComplete Condition", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+						"CertManagerUnavailable")
+				})
+			})
+			Context("and disabled", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewCryostatCertManagerDisabled())
+					t.TLS = false
+				})
+				JustBeforeEach(func() {
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+					_, err := t.controller.Reconcile(context.Background(), req)
+					Expect(err).ToNot(HaveOccurred())
+				})
+				It("should not emit a CertManagerUnavailable Event", func() {
+					recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+					Expect(recorder.Events).ToNot(Receive())
+				})
+				It("should set TLSSetupComplete Condition", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+						"CertManagerDisabled")
+				})
+			})
+		})
+		Context("with service options", func() {
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
 			})
 			Context("containing core config", func() {
 				BeforeEach(func() {
@@ -1183,6 +1843,212 @@ var _ = Describe("CryostatController", func() {
 				})
 			})
 		})
+		Context("after managed resources have been modified", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			Context("by changing core service options", func() {
+				JustBeforeEach(func() {
+					t.addUserAnnotation(&corev1.Service{}, "cryostat", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.ServiceOptions = test.NewCryostatWithCoreSvc().Spec.ServiceOptions
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the service", func() {
+					t.checkUpdatedService("cryostat", test.NewCustomizedCoreService())
+				})
+				Context("and later removing them", func() {
+					JustBeforeEach(func() {
+						t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+							cr.Spec.ServiceOptions = nil
+						})
+						t.reconcileCryostat()
+					})
+					It("should remove the labels and annotations", func() {
+						service := &corev1.Service{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, service)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(service.Labels).ToNot(HaveKey("my"))
+						Expect(service.Labels).To(HaveKeyWithValue("app", "cryostat"))
+						Expect(service.Annotations).ToNot(HaveKey("my/custom"))
+						Expect(service.Annotations).To(HaveKeyWithValue("user", "annotation"))
+					})
+				})
+			})
+			Context("by changing grafana service options", func() {
+				JustBeforeEach(func() {
+					t.addUserAnnotation(&corev1.Service{}, "cryostat-grafana", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.ServiceOptions = test.NewCryostatWithGrafanaSvc().Spec.ServiceOptions
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the service", func() {
+					t.checkUpdatedService("cryostat-grafana", test.NewCustomizedGrafanaService())
+				})
+			})
+			Context("by changing reports service options", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostat()
+					cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{Replicas: 1}
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+					t.reportReplicas = 1
+				})
+				JustBeforeEach(func() {
+					t.addUserAnnotation(&corev1.Service{}, "cryostat-reports", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.ServiceOptions = test.NewCryostatWithReportsSvc().Spec.ServiceOptions
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the service", func() {
+					t.checkUpdatedService("cryostat-reports", test.NewCustomizedReportsService())
+				})
+			})
+			Context("by changing PVC options", func() {
+				JustBeforeEach(func() {
+					t.addUserAnnotation(&corev1.PersistentVolumeClaim{}, "cryostat", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.StorageOptions = test.NewCryostatWithPVCSpec().Spec.StorageOptions
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the PVC metadata", func() {
+					pvc := &corev1.PersistentVolumeClaim{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, pvc)
+					Expect(err).ToNot(HaveOccurred())
+					expected := test.NewCustomPVC()
+					checkMergedMetadata(pvc, expected)
+				})
+				It("should not change the PVC spec", func() {
+					pvc := &corev1.PersistentVolumeClaim{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, pvc)
+					Expect(err).ToNot(HaveOccurred())
+					expected := test.NewDefaultPVC()
+					Expect(pvc.Spec.AccessModes).To(Equal(expected.Spec.AccessModes))
+					Expect(pvc.Spec.StorageClassName).To(Equal(expected.Spec.StorageClassName))
+				})
+			})
+			Context("by removing a key from the JMX secret", func() {
+				var password string
+				JustBeforeEach(func() {
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-jmx-auth", Namespace: "default"}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					password = secret.StringData["CRYOSTAT_RJMX_PASS"]
+					delete(secret.StringData, "CRYOSTAT_RJMX_USER")
+					err = t.Client.Update(context.Background(), secret)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostat()
+				})
+				It("should restore the key without changing the password", func() {
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-jmx-auth", Namespace: "default"}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(secret.StringData).To(HaveKeyWithValue("CRYOSTAT_RJMX_USER", "cryostat"))
+					Expect(secret.StringData).To(HaveKeyWithValue("CRYOSTAT_RJMX_PASS", password))
+				})
+			})
+			Context("by changing the Grafana secret", func() {
+				JustBeforeEach(func() {
+					secret := t.addUserAnnotation(&corev1.Secret{}, "cryostat-grafana-basic", "default").(*corev1.Secret)
+					secret.StringData["GF_SECURITY_ADMIN_PASSWORD"] = "custom-password"
+					err := t.Client.Update(context.Background(), secret)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostat()
+				})
+				It("should preserve the user's changes", func() {
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-grafana-basic", Namespace: "default"}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(secret.StringData).To(HaveKeyWithValue("GF_SECURITY_ADMIN_USER", "admin"))
+					Expect(secret.StringData).To(HaveKeyWithValue("GF_SECURITY_ADMIN_PASSWORD", "custom-password"))
+					Expect(secret.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				})
+			})
+			Context("by changing RBAC resources", func() {
+				JustBeforeEach(func() {
+					role := t.addUserAnnotation(&rbacv1.Role{}, "cryostat", "default").(*rbacv1.Role)
+					role.Rules = role.Rules[:1]
+					err := t.Client.Update(context.Background(), role)
+					Expect(err).ToNot(HaveOccurred())
+
+					binding := t.addUserAnnotation(&rbacv1.RoleBinding{}, "cryostat", "default").(*rbacv1.RoleBinding)
+					binding.Subjects[0].Name = "other"
+					err = t.Client.Update(context.Background(), binding)
+					Expect(err).ToNot(HaveOccurred())
+
+					clusterBinding := t.addUserAnnotation(&rbacv1.ClusterRoleBinding{},
+						"cryostat-9ecd5050500c2566765bc593edfcce12434283e5da32a27476bc4a1569304a02", "").(*rbacv1.ClusterRoleBinding)
+					clusterBinding.Subjects[0].Name = "other"
+					err = t.Client.Update(context.Background(), clusterBinding)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostat()
+				})
+				It("should restore the Role", func() {
+					role := &rbacv1.Role{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, role)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(role.Rules).To(Equal(test.NewRole().Rules))
+					Expect(role.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				})
+				It("should restore the RoleBinding", func() {
+					binding := &rbacv1.RoleBinding{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, binding)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(binding.Subjects).To(Equal(test.NewRoleBinding().Subjects))
+					Expect(binding.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				})
+				It("should restore the ClusterRoleBinding", func() {
+					binding := &rbacv1.ClusterRoleBinding{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{
+						Name: "cryostat-9ecd5050500c2566765bc593edfcce12434283e5da32a27476bc4a1569304a02"}, binding)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(binding.Subjects).To(Equal(test.NewClusterRoleBinding().Subjects))
+					Expect(binding.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				})
+			})
+			Context("by changing the ConsoleLink", func() {
+				JustBeforeEach(func() {
+					link := t.addUserAnnotation(&consolev1.ConsoleLink{},
+						"cryostat-9ecd5050500c2566765bc593edfcce12434283e5da32a27476bc4a1569304a02", "").(*consolev1.ConsoleLink)
+					link.Spec.Text = "Other"
+					err := t.Client.Update(context.Background(), link)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostat()
+				})
+				It("should restore the ConsoleLink", func() {
+					link := &consolev1.ConsoleLink{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{
+						Name: "cryostat-9ecd5050500c2566765bc593edfcce12434283e5da32a27476bc4a1569304a02"}, link)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(link.Spec).To(Equal(test.NewConsoleLink().Spec))
+					Expect(link.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				})
+			})
+			Context("and reconciled again without changes", func() {
+				It("should not update any resources", func() {
+					svc := &corev1.Service{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, svc)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostat()
+
+					svc2 := &corev1.Service{}
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, svc2)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(svc2.ResourceVersion).To(Equal(svc.ResourceVersion))
+				})
+			})
+		})
 		Context("with resource requirements", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, test.NewCryostatWithResources())
@@ -1191,46 +2057,1023 @@ var _ = Describe("CryostatController", func() {
 				t.expectDeployment()
 			})
 		})
-	})
-	Describe("reconciling a request in Kubernetes", func() {
-		JustBeforeEach(func() {
-			t.controller.IsOpenShift = false
+		Context("with security options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithSecurityOptions())
+			})
+			It("should create expected deployment", func() {
+				t.expectDeployment()
+			})
+		})
+		Context("with reports security options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithReportSecurityOptions())
+				t.reportReplicas = 1
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should configure the reports deployment", func() {
+				t.checkReportsDeployment()
+			})
+		})
+		Context("with environment options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithEnvironmentOptions())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should add extra environment variables to the containers", func() {
+				template := t.getDeploymentTemplate("cryostat")
+				coreContainer := template.Spec.Containers[0]
+				Expect(coreContainer.Env).To(ContainElements(
+					corev1.EnvVar{Name: "CRYOSTAT_DISCOVERY_PING_PERIOD", Value: "30000"},
+					corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-XX:+UseG1GC -Xmx512m"}))
+				grafanaContainer := template.Spec.Containers[1]
+				Expect(grafanaContainer.Env).To(ContainElement(corev1.EnvVar{Name: "GF_LOG_LEVEL", Value: "debug"}))
+				datasourceContainer := template.Spec.Containers[2]
+				Expect(datasourceContainer.Env).To(ContainElement(corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx256m"}))
+			})
+			It("should not override variables set by the operator", func() {
+				coreContainer := t.getDeploymentTemplate("cryostat").Spec.Containers[0]
+				Expect(coreContainer.Env).To(ConsistOf(append(test.NewCoreEnvironmentVariables(t.minimal, t.TLS, t.externalTLS,
+					t.controller.IsOpenShift, ""),
+					corev1.EnvVar{Name: "CRYOSTAT_DISCOVERY_PING_PERIOD", Value: "30000"},
+					corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-XX:+UseG1GC -Xmx512m"})))
+			})
+			It("should add extra sources before the operator's sources", func() {
+				template := t.getDeploymentTemplate("cryostat")
+				cr := test.NewCryostatWithEnvironmentOptions()
+				Expect(template.Spec.Containers[0].EnvFrom).To(Equal(append(cr.Spec.EnvironmentOptions.CoreEnvironment.ExtraEnvFrom,
+					test.NewCoreEnvFromSource(t.TLS)...)))
+				Expect(template.Spec.Containers[1].EnvFrom).To(Equal(append(cr.Spec.EnvironmentOptions.GrafanaEnvironment.ExtraEnvFrom,
+					test.NewGrafanaEnvFromSource()...)))
+			})
+			It("should report the conflicting variables", func() {
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeEnvironmentConflict, metav1.ConditionTrue, "ReservedVariables")
+				t.checkConditionMessage(operatorv1beta1.ConditionTypeEnvironmentConflict, "cryostat (CRYOSTAT_WEB_PORT)")
+			})
+			Context("without conflicts", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						core := cr.Spec.EnvironmentOptions.CoreEnvironment
+						core.ExtraEnv = core.ExtraEnv[:1]
+					})
+					t.reconcileCryostat()
+				})
+				It("should report no conflicts", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeEnvironmentConflict, metav1.ConditionFalse, "NoConflicts")
+				})
+			})
+			Context("when removed", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.EnvironmentOptions = nil
+					})
+					t.reconcileCryostat()
+				})
+				It("should restore the default deployment", func() {
+					t.checkMainDeployment()
+				})
+				It("should remove the condition", func() {
+					t.checkConditionAbsent(operatorv1beta1.ConditionTypeEnvironmentConflict)
+				})
+			})
+		})
+		Context("with reports environment options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithReportEnvironmentOptions())
+				t.reportReplicas = 1
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should add extra environment variables to the reports container", func() {
+				container := t.getDeploymentTemplate("cryostat-reports").Spec.Containers[0]
+				Expect(container.Env).To(ConsistOf(append(test.NewReportsEnvironmentVariables(t.TLS, corev1.ResourceRequirements{}),
+					corev1.EnvVar{Name: "QUARKUS_LOG_LEVEL", Value: "DEBUG"},
+					corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g"})))
+			})
+			It("should report the conflicting variables", func() {
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeEnvironmentConflict, metav1.ConditionTrue, "ReservedVariables")
+				t.checkConditionMessage(operatorv1beta1.ConditionTypeEnvironmentConflict, "cryostat-reports (JAVA_OPTIONS)")
+			})
+			Context("and a pod template override", func() {
+				BeforeEach(func() {
+					cr := t.objs[len(t.objs)-1].(*operatorv1beta1.Cryostat)
+					cr.Spec.ReportOptions.PodTemplateOverride = test.NewCryostatWithReportsPodTemplateOverride().Spec.ReportOptions.PodTemplateOverride
+				})
+				It("should apply the override", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeReportsDeploymentOverrideApplied, metav1.ConditionTrue,
+						"OverrideApplied")
+					template := t.getDeploymentTemplate("cryostat-reports")
+					Expect(template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry-credentials"}))
+				})
+				It("should report the conflicting variables", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeEnvironmentConflict, metav1.ConditionTrue, "ReservedVariables")
+					t.checkConditionMessage(operatorv1beta1.ConditionTypeEnvironmentConflict, "cryostat-reports (JAVA_OPTIONS)")
+				})
+			})
+		})
+		Context("with a pod template override", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithPodTemplateOverride())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should apply the override to the deployment", func() {
+				template := t.getDeploymentTemplate("cryostat")
+				Expect(template.Labels).To(HaveKeyWithValue("sidecar.istio.io/inject", "true"))
+				Expect(template.Annotations).To(HaveKeyWithValue("example.com/owner", "team-a"))
+				Expect(template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry-credentials"}))
+				Expect(template.Spec.Containers).To(HaveLen(4))
+				Expect(template.Spec.Containers).To(ContainElement(WithTransform(func(c corev1.Container) string {
+					return c.Name + "@" + c.Image
+				}, Equal("log-forwarder@quay.io/example/log-forwarder:latest"))))
+				Expect(template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name:      "extra-config",
+					MountPath: "/opt/extra",
+				}))
+				Expect(template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "extra-config",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "extra-config"},
+						},
+					},
+				}))
+			})
+			It("should keep the operator's configuration", func() {
+				template := t.getDeploymentTemplate("cryostat")
+				Expect(template.Spec.Containers[0].Env).To(ConsistOf(test.NewCoreEnvironmentVariables(t.minimal, t.TLS,
+					t.externalTLS, t.controller.IsOpenShift, "")))
+				Expect(template.Spec.Volumes).To(ContainElements(test.NewVolumes(t.minimal, t.TLS)))
+				Expect(template.Spec.ServiceAccountName).To(Equal("cryostat"))
+			})
+			It("should report the override as applied", func() {
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, metav1.ConditionTrue,
+					"OverrideApplied")
+			})
+			Context("that is malformed", func() {
+				BeforeEach(func() {
+					cr := t.objs[len(t.objs)-1].(*operatorv1beta1.Cryostat)
+					cr.Spec.PodTemplateOverride.Patch = "spec: [containers"
+				})
+				It("should create the default deployment", func() {
+					t.checkMainDeployment()
+				})
+				It("should report the invalid patch", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, metav1.ConditionFalse,
+						"InvalidPatch")
+				})
+			})
+			Context("with fields that are not part of a pod template", func() {
+				BeforeEach(func() {
+					cr := t.objs[len(t.objs)-1].(*operatorv1beta1.Cryostat)
+					cr.Spec.PodTemplateOverride.Patch = "spec:\n  imagePullSecret: registry-credentials\n"
+				})
+				It("should report the invalid patch", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, metav1.ConditionFalse,
+						"InvalidPatch")
+					t.checkConditionMessage(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, "imagePullSecret")
+				})
+			})
+			Context("that removes an operator container", func() {
+				BeforeEach(func() {
+					cr := t.objs[len(t.objs)-1].(*operatorv1beta1.Cryostat)
+					patchType := operatorv1beta1.PodTemplatePatchTypeJSON
+					cr.Spec.PodTemplateOverride = &operatorv1beta1.PodTemplateOverride{
+						Type:  &patchType,
+						Patch: `[{"op": "remove", "path": "/spec/containers/1"}]`,
+					}
+				})
+				It("should create the default deployment", func() {
+					t.checkMainDeployment()
+				})
+				It("should report the modified fields", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, metav1.ConditionFalse,
+						"OperatorFieldsModified")
+					t.checkConditionMessage(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied,
+						"container \"cryostat-grafana\" must not be removed")
+				})
+			})
+			Context("that changes an operator environment variable", func() {
+				BeforeEach(func() {
+					cr := t.objs[len(t.objs)-1].(*operatorv1beta1.Cryostat)
+					cr.Spec.PodTemplateOverride.Patch = `{"spec": {"containers": [{"name": "cryostat", "env": [{"name": "CRYOSTAT_WEB_PORT", "value": "9000"}]}]}}`
+				})
+				It("should report the modified fields", func() {
+					t.checkConditionPresent(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, metav1.ConditionFalse,
+						"OperatorFieldsModified")
+					t.checkConditionMessage(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied, "CRYOSTAT_WEB_PORT")
+				})
+			})
+			Context("when removed", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.PodTemplateOverride = nil
+					})
+					t.reconcileCryostat()
+				})
+				It("should remove the override from the pod spec", func() {
+					template := t.getDeploymentTemplate("cryostat")
+					Expect(template.Spec.Containers).To(HaveLen(3))
+					Expect(template.Spec.Volumes).To(Equal(test.NewVolumes(t.minimal, t.TLS)))
+					Expect(template.Spec.ImagePullSecrets).To(BeEmpty())
+				})
+				It("should remove the override's labels and annotations", func() {
+					template := t.getDeploymentTemplate("cryostat")
+					Expect(template.Labels).ToNot(HaveKey("sidecar.istio.io/inject"))
+					Expect(template.Annotations).ToNot(HaveKey("example.com/owner"))
+					Expect(template.Labels).To(HaveKeyWithValue("app", "cryostat"))
+					Expect(template.Annotations).To(HaveKey("operator.cryostat.io/config-hash"))
+				})
+				It("should remove the condition", func() {
+					t.checkConditionAbsent(operatorv1beta1.ConditionTypeMainDeploymentOverrideApplied)
+				})
+			})
+		})
+		Context("with a reports pod template override", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithReportsPodTemplateOverride())
+				t.reportReplicas = 1
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should apply the override to the reports deployment", func() {
+				template := t.getDeploymentTemplate("cryostat-reports")
+				Expect(template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry-credentials"}))
+				Expect(template.Spec.Containers).To(HaveLen(1))
+				Expect(template.Spec.Containers[0].Env).To(ConsistOf(test.NewReportsEnvironmentVariables(t.TLS,
+					corev1.ResourceRequirements{})))
+			})
+			It("should report the override as applied", func() {
+				t.checkConditionPresent(operatorv1beta1.ConditionTypeReportsDeploymentOverrideApplied, metav1.ConditionTrue,
+					"OverrideApplied")
+			})
+			Context("when reports are disabled", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.ReportOptions.Replicas = 0
+					})
+					t.reconcileCryostat()
+				})
+				It("should remove the condition", func() {
+					t.checkConditionAbsent(operatorv1beta1.ConditionTypeReportsDeploymentOverrideApplied)
+				})
+			})
+		})
+		Context("with mounted secrets and config maps", func() {
+			var coreHash, reportsHash string
+			BeforeEach(func() {
+				cr := test.NewCryostatWithSecrets()
+				cr.Spec.EventTemplates = test.NewCryostatWithTemplates().Spec.EventTemplates
+				cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{Replicas: 1}
+				t.objs = append(t.objs, cr, test.NewTemplateConfigMap(), test.NewOtherTemplateConfigMap(),
+					test.NewTrustedCertSecret("testCert1", "test.crt"), test.NewTrustedCertSecret("testCert2", "tls.crt"))
+				t.reportReplicas = 1
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				coreHash = t.getConfigHash("cryostat")
+				reportsHash = t.getConfigHash("cryostat-reports")
+			})
+			It("should add a config hash to the pod templates", func() {
+				Expect(coreHash).ToNot(BeEmpty())
+				Expect(reportsHash).ToNot(BeEmpty())
+			})
+			It("should not change the config hash when reconciled again", func() {
+				t.reconcileCryostat()
+				Expect(t.getConfigHash("cryostat")).To(Equal(coreHash))
+				Expect(t.getConfigHash("cryostat-reports")).To(Equal(reportsHash))
+			})
+			It("should update the config hash when a trusted certificate changes", func() {
+				t.updateSecretData("testCert1", "test.crt", "renewed-cert-bytes")
+				t.reconcileCryostat()
+				Expect(t.getConfigHash("cryostat")).ToNot(Equal(coreHash))
+				Expect(t.getConfigHash("cryostat-reports")).To(Equal(reportsHash))
+			})
+			It("should update the config hash when an event template changes", func() {
+				cm := &corev1.ConfigMap{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "templateCM1", Namespace: "default"}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				cm.Data["template.jfc"] = "updated XML template data"
+				err = t.Client.Update(context.Background(), cm)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileCryostat()
+				Expect(t.getConfigHash("cryostat")).ToNot(Equal(coreHash))
+			})
+			It("should update the config hash when the Cryostat certificate is renewed", func() {
+				t.updateSecretData("cryostat-tls", corev1.TLSCertKey, "renewed-cert-bytes")
+				t.reconcileCryostat()
+				Expect(t.getConfigHash("cryostat")).ToNot(Equal(coreHash))
+				Expect(t.getConfigHash("cryostat-reports")).To(Equal(reportsHash))
+			})
+			It("should update the config hash when a target CA certificate is added", func() {
+				err := t.Client.Create(context.Background(), test.NewTargetCASecretForCryostat(map[string][]byte{
+					"default_test-pod.crt": []byte(test.TargetCACert),
+				}))
+				Expect(err).ToNot(HaveOccurred())
+				t.reconcileCryostat()
+				Expect(t.getConfigHash("cryostat")).ToNot(Equal(coreHash))
+				Expect(t.getConfigHash("cryostat-reports")).To(Equal(reportsHash))
+			})
+			It("should update the config hash when the reports certificate is renewed", func() {
+				t.updateSecretData("cryostat-reports-tls", corev1.TLSCertKey, "renewed-cert-bytes")
+				t.reconcileCryostat()
+				Expect(t.getConfigHash("cryostat")).To(Equal(coreHash))
+				Expect(t.getConfigHash("cryostat-reports")).ToNot(Equal(reportsHash))
+			})
+			It("should preserve other pod template annotations", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = "now"
+				err = t.Client.Update(context.Background(), deploy)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.updateSecretData("testCert2", "tls.crt", "renewed-cert-bytes")
+				t.reconcileCryostat()
+
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Annotations).To(HaveKeyWithValue("kubectl.kubernetes.io/restartedAt", "now"))
+				Expect(deploy.Spec.Template.Annotations["operator.cryostat.io/config-hash"]).ToNot(Equal(coreHash))
+			})
+		})
+		Context("with scheduling options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithScheduling())
+			})
+			It("should create expected deployment", func() {
+				t.expectDeployment()
+			})
+			Context("that are later changed", func() {
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.SchedulingOptions = test.NewSchedulingConfiguration("other-node")
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the deployment", func() {
+					t.checkMainDeployment()
+				})
+			})
+		})
+		Context("with reports autoscaling", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithReportsAutoscaling())
+				t.reportReplicas = 2
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the reports deployment with the minimum replicas", func() {
+				t.checkReportsDeployment()
+			})
+			It("should create a HorizontalPodAutoscaler", func() {
+				t.checkReportsHorizontalPodAutoscaler(test.NewReportsHorizontalPodAutoscaler(2, 5, test.NewReportsCPUMetric(60)))
+			})
+			It("should create a PodDisruptionBudget", func() {
+				maxUnavailable := intstr.FromInt(1)
+				t.checkReportsPodDisruptionBudget(test.NewReportsPodDisruptionBudget(nil, &maxUnavailable))
+			})
+			It("should configure Cryostat to use the reports service", func() {
+				t.checkMainDeployment()
+			})
+			Context("after the autoscaler scales the deployment", func() {
+				JustBeforeEach(func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-reports", Namespace: "default"}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					replicas := int32(4)
+					deploy.Spec.Replicas = &replicas
+					err = t.Client.Update(context.Background(), deploy)
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostat()
+				})
+				It("should not overwrite the replicas", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-reports", Namespace: "default"}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(*deploy.Spec.Replicas).To(Equal(int32(4)))
+				})
+			})
+			Context("with custom metrics", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostatWithReportsAutoscaling()
+					cr.Spec.ReportOptions.Autoscaling.TargetCPUUtilizationPercentage = nil
+					cr.Spec.ReportOptions.Autoscaling.Metrics = []autoscalingv2beta2.MetricSpec{test.NewReportsCustomMetric()}
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+				})
+				It("should only scale using the custom metrics", func() {
+					t.checkReportsHorizontalPodAutoscaler(test.NewReportsHorizontalPodAutoscaler(2, 5, test.NewReportsCustomMetric()))
+				})
+			})
+			Context("with default options", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostat()
+					cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{
+						Autoscaling:         &operatorv1beta1.ReportsAutoscalingConfiguration{MaxReplicas: 3},
+						PodDisruptionBudget: &operatorv1beta1.PodDisruptionBudgetConfiguration{},
+					}
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+					t.reportReplicas = 1
+				})
+				It("should scale using CPU utilization", func() {
+					t.checkReportsDeployment()
+					t.checkReportsHorizontalPodAutoscaler(test.NewReportsHorizontalPodAutoscaler(1, 3, test.NewReportsCPUMetric(80)))
+				})
+				It("should require one available replica", func() {
+					minAvailable := intstr.FromInt(1)
+					t.checkReportsPodDisruptionBudget(test.NewReportsPodDisruptionBudget(&minAvailable, nil))
+				})
+			})
+			Context("that is later disabled", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.ReportOptions.Autoscaling = nil
+						cr.Spec.ReportOptions.PodDisruptionBudget = nil
+						cr.Spec.ReportOptions.Replicas = 3
+					})
+					t.reportReplicas = 3
+					t.reconcileCryostat()
+				})
+				It("should delete the HorizontalPodAutoscaler", func() {
+					hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-reports", Namespace: "default"}, hpa)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should delete the PodDisruptionBudget", func() {
+					pdb := &policyv1beta1.PodDisruptionBudget{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-reports", Namespace: "default"}, pdb)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should set the replicas of the deployment", func() {
+					t.checkReportsDeployment()
+				})
+			})
+		})
+		Context("with reports scheduling options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithReportsScheduling())
+				t.reportReplicas = 1
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should configure the reports deployment", func() {
+				t.checkReportsDeployment()
+			})
+			It("should not configure the main deployment", func() {
+				t.checkMainDeployment()
+			})
+		})
+		Context("with route configuration", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithRouteConfig(), test.NewRouteCertificateSecret())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should configure the route", func() {
+				route := t.checkRoute("cryostat")
+				Expect(route.Spec.Host).To(Equal("cryostat.apps.example.com"))
+				Expect(route.Spec.Path).To(Equal("/cryostat"))
+				Expect(route.Spec.WildcardPolicy).To(Equal(openshiftv1.WildcardPolicySubdomain))
+				Expect(route.Labels).To(Equal(withInventoryLabel(map[string]string{"my": "label"})))
+				Expect(route.Annotations).To(Equal(withManagedKeys(map[string]string{"my": "label"},
+					map[string]string{"haproxy.router.openshift.io/timeout": "5m"})))
+			})
+			It("should use the custom certificate", func() {
+				route := t.checkRoute("cryostat")
+				Expect(route.Spec.TLS.Certificate).To(Equal("route-cert-bytes"))
+				Expect(route.Spec.TLS.Key).To(Equal("route-key-bytes"))
+				Expect(route.Spec.TLS.CACertificate).To(Equal("route-ca-bytes"))
+			})
+			It("should apply labels and annotations to the other route", func() {
+				route := t.checkRoute("cryostat-grafana")
+				Expect(route.Spec.Host).To(BeEmpty())
+				Expect(route.Spec.WildcardPolicy).To(Equal(openshiftv1.WildcardPolicyNone))
+				Expect(route.Spec.TLS.Certificate).To(BeEmpty())
+				Expect(route.Labels).To(Equal(withInventoryLabel(map[string]string{"grafana": "label"})))
+				Expect(route.Annotations).To(Equal(withManagedKeys(map[string]string{"grafana": "label"},
+					map[string]string{"grafana": "annotation"})))
+			})
+			It("should include the path in the application URL", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.ApplicationURL).To(Equal("https://cryostat.example.com/cryostat"))
+			})
+			Context("that is later changed", func() {
+				JustBeforeEach(func() {
+					// Simulate OpenShift generating a host
+					route := t.addUserAnnotation(&openshiftv1.Route{}, "cryostat-grafana", "default").(*openshiftv1.Route)
+					route.Spec.Host = "cryostat-grafana-default.apps.example.com"
+					err := t.Client.Update(context.Background(), route)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.addUserAnnotation(&openshiftv1.Route{}, "cryostat", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						routeConfig := cr.Spec.NetworkOptions.CoreConfig.RouteConfig
+						routeConfig.Host = "other.apps.example.com"
+						routeConfig.Path = ""
+						routeConfig.WildcardPolicy = ""
+						routeConfig.CertificateSecretName = ""
+						cr.Spec.NetworkOptions.CoreConfig.Annotations["haproxy.router.openshift.io/timeout"] = "10m"
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the route in place", func() {
+					route := t.checkRoute("cryostat")
+					Expect(route.Spec.Host).To(Equal("other.apps.example.com"))
+					Expect(route.Spec.Path).To(BeEmpty())
+					Expect(route.Spec.WildcardPolicy).To(Equal(openshiftv1.WildcardPolicyNone))
+					Expect(route.Spec.TLS.Certificate).To(BeEmpty())
+					Expect(route.Spec.TLS.Key).To(BeEmpty())
+					Expect(route.Annotations).To(Equal(map[string]string{
+						"haproxy.router.openshift.io/timeout":      "10m",
+						"user":                                     "annotation",
+						"operator.cryostat.io/managed-labels":      "my",
+						"operator.cryostat.io/managed-annotations": "haproxy.router.openshift.io/timeout",
+					}))
+					Expect(route.Status.Ingress).ToNot(BeEmpty())
+				})
+				It("should keep the generated host", func() {
+					route := t.checkRoute("cryostat-grafana")
+					Expect(route.Spec.Host).To(Equal("cryostat-grafana-default.apps.example.com"))
+					Expect(route.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				})
+			})
+			Context("that has labels and annotations removed", func() {
+				JustBeforeEach(func() {
+					t.addUserAnnotation(&openshiftv1.Route{}, "cryostat", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.NetworkOptions.CoreConfig.Labels = nil
+						cr.Spec.NetworkOptions.CoreConfig.Annotations = nil
+					})
+					t.reconcileCryostat()
+				})
+				It("should remove them from the route", func() {
+					route := t.checkRoute("cryostat")
+					Expect(route.Labels).To(Equal(withInventoryLabel(nil)))
+					Expect(route.Annotations).To(Equal(map[string]string{"user": "annotation"}))
+				})
+			})
+			Context("when the certificate is changed", func() {
+				JustBeforeEach(func() {
+					t.updateSecretData("cryostat-route-tls", corev1.TLSCertKey, "new-cert-bytes")
+					t.reconcileCryostat()
+				})
+				It("should update the route", func() {
+					route := t.checkRoute("cryostat")
+					Expect(route.Spec.TLS.Certificate).To(Equal("new-cert-bytes"))
+				})
+			})
+		})
+		Context("with a missing route certificate", func() {
+			BeforeEach(func() {
+				cr := test.NewCryostatWithRouteConfig()
+				cr.Spec.Minimal = true
+				t.objs = append(t.objs, cr)
+				t.minimal = true
+			})
+			It("should return an error", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				t.makeCertificatesReady()
+				t.initializeSecrets()
+
+				_, err = t.controller.Reconcile(context.Background(), req)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				t.expectNoRoutes()
+			})
+		})
+		Context("with network policies", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithNetworkPolicies())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the Cryostat network policy", func() {
+				t.checkNetworkPolicy(test.NewCoreNetworkPolicy(true, false))
+			})
+			It("should not create the reports network policy", func() {
+				t.expectNoNetworkPolicy("cryostat-reports")
+			})
+			Context("with reports enabled", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostatWithNetworkPolicies()
+					cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{Replicas: 1}
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+					t.reportReplicas = 1
+				})
+				It("should allow Cryostat to connect to reports", func() {
+					t.checkNetworkPolicy(test.NewCoreNetworkPolicy(true, true))
+				})
+				It("should create the reports network policy", func() {
+					t.checkNetworkPolicy(test.NewReportsNetworkPolicy())
+				})
+				Context("that are later disabled", func() {
+					JustBeforeEach(func() {
+						t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+							cr.Spec.ReportOptions.Replicas = 0
+						})
+						t.reconcileCryostat()
+					})
+					It("should delete the reports network policy", func() {
+						t.expectNoNetworkPolicy("cryostat-reports")
+					})
+					It("should update the Cryostat network policy", func() {
+						t.checkNetworkPolicy(test.NewCoreNetworkPolicy(true, false))
+					})
+				})
+			})
+			Context("with target discovery options", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostatWithJMXPortOptions()
+					selector := test.NewCryostatWithNamespaceSelector().Spec.TargetDiscoveryOptions.NamespaceSelector
+					cr.Spec.TargetDiscoveryOptions.NamespaceSelector = selector
+					cr.Spec.TargetDiscoveryOptions.AgentPortNames = []string{"custom-agent"}
+					cr.Spec.NetworkPolicyOptions = test.NewCryostatWithNetworkPolicies().Spec.NetworkPolicyOptions
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+				})
+				It("should only allow connections to targets using those options", func() {
+					policy := t.getNetworkPolicy("cryostat")
+					selector := test.NewCryostatWithNamespaceSelector().Spec.TargetDiscoveryOptions.NamespaceSelector
+					tcp := corev1.ProtocolTCP
+					jmxName := intstr.FromString("custom-jmx")
+					agentName := intstr.FromString("custom-agent")
+					jmxNumber := intstr.FromInt(5678)
+					Expect(policy.Spec.Egress[0]).To(Equal(netv1.NetworkPolicyEgressRule{
+						Ports: []netv1.NetworkPolicyPort{
+							{Protocol: &tcp, Port: &jmxName},
+							{Protocol: &tcp, Port: &agentName},
+							{Protocol: &tcp, Port: &jmxNumber},
+						},
+						To: []netv1.NetworkPolicyPeer{{NamespaceSelector: selector}},
+					}))
+					Expect(policy.Spec.Ingress[0].From).To(ContainElement(netv1.NetworkPolicyPeer{NamespaceSelector: selector}))
+				})
+			})
+			Context("with the operator in another namespace", func() {
+				BeforeEach(func() {
+					operatorNamespace := "cryostat-operator-system"
+					t.EnvOperatorNamespace = &operatorNamespace
+				})
+				It("should allow connections from the operator", func() {
+					policy := t.getNetworkPolicy("cryostat")
+					Expect(policy.Spec.Ingress[0].From).To(ContainElement(netv1.NetworkPolicyPeer{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "cryostat-operator-system"},
+						},
+					}))
+				})
+			})
+			Context("with additional peers", func() {
+				var ingressPeer, egressPeer, controllerPeer netv1.NetworkPolicyPeer
+				BeforeEach(func() {
+					ingressPeer = netv1.NetworkPolicyPeer{
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "dashboard"}},
+					}
+					egressPeer = netv1.NetworkPolicyPeer{
+						IPBlock: &netv1.IPBlock{CIDR: "192.0.2.0/24"},
+					}
+					controllerPeer = netv1.NetworkPolicyPeer{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "router"}},
+					}
+					cr := test.NewCryostatWithNetworkPolicies()
+					cr.Spec.NetworkPolicyOptions.IngressControllerPeers = []netv1.NetworkPolicyPeer{controllerPeer}
+					cr.Spec.NetworkPolicyOptions.AdditionalIngressPeers = []netv1.NetworkPolicyPeer{ingressPeer}
+					cr.Spec.NetworkPolicyOptions.AdditionalEgressPeers = []netv1.NetworkPolicyPeer{egressPeer}
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+				})
+				It("should allow connections from the peers", func() {
+					policy := t.getNetworkPolicy("cryostat")
+					operatorNamespace := netv1.NetworkPolicyPeer{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "default"},
+						},
+					}
+					allNamespaces := netv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{}}
+					Expect(policy.Spec.Ingress[0].From).To(Equal([]netv1.NetworkPolicyPeer{controllerPeer, operatorNamespace,
+						allNamespaces, ingressPeer}))
+					Expect(policy.Spec.Ingress[1].From).To(Equal([]netv1.NetworkPolicyPeer{controllerPeer, ingressPeer}))
+				})
+				It("should allow connections to the peers", func() {
+					policy := t.getNetworkPolicy("cryostat")
+					Expect(policy.Spec.Egress).To(ContainElement(netv1.NetworkPolicyEgressRule{
+						To: []netv1.NetworkPolicyPeer{egressPeer},
+					}))
+				})
+			})
+			Context("when minimal", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostatWithNetworkPolicies()
+					cr.Spec.Minimal = true
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr}
+					t.minimal = true
+				})
+				It("should not allow connections to Grafana", func() {
+					policy := t.getNetworkPolicy("cryostat")
+					Expect(policy.Spec.Ingress).To(HaveLen(1))
+				})
+			})
+			Context("that are later disabled", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.NetworkPolicyOptions.Enabled = false
+					})
+					t.reconcileCryostat()
+				})
+				It("should delete the network policy", func() {
+					t.expectNoNetworkPolicy("cryostat")
+				})
+			})
+		})
+		Context("without network policies", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			It("should not create network policies", func() {
+				t.reconcileCryostatFully()
+				t.expectNoNetworkPolicy("cryostat")
+				t.expectNoNetworkPolicy("cryostat-reports")
+			})
+		})
+	})
+	Describe("reconciling a request in Kubernetes", func() {
+		JustBeforeEach(func() {
+			t.controller.IsOpenShift = false
+		})
+		Context("with TLS ingress", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithIngress())
+			})
+			It("should create ingresses", func() {
+				t.expectIngresses()
+			})
+			It("should not create routes", func() {
+				t.reconcileCryostatFully()
+				t.expectNoRoutes()
+			})
+			It("should create deployment and set owner", func() {
+				t.expectDeployment()
+			})
+			It("should create RBAC", func() {
+				t.expectRBAC()
+			})
+		})
+		Context("with non-TLS ingress", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithIngressNoTLS())
+				t.externalTLS = false
+			})
+			It("should create ingresses", func() {
+				t.expectIngresses()
+			})
+			It("should not create routes", func() {
+				t.reconcileCryostatFully()
+				t.expectNoRoutes()
+			})
+			It("should create deployment and set owner", func() {
+				t.expectDeployment()
+			})
+			It("should create RBAC", func() {
+				t.expectRBAC()
+			})
+		})
+		Context("after changing the ingress configuration", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithIngress())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.addUserAnnotation(&netv1.Ingress{}, "cryostat", "default")
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.NetworkOptions.CoreConfig.IngressSpec.Rules[0].Host = "other.example.com"
+					cr.Spec.NetworkOptions.CoreConfig.Labels["my"] = "label"
+				})
+				t.reconcileCryostat()
+			})
+			It("should update the ingress", func() {
+				expectedConfig := test.NewNetworkConfigurationList(t.externalTLS)
+				expectedConfig.CoreConfig.IngressSpec.Rules[0].Host = "other.example.com"
+				expectedConfig.CoreConfig.Labels["my"] = "label"
+
+				ingress := &netv1.Ingress{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, ingress)
+				Expect(err).ToNot(HaveOccurred())
+				for key, val := range expectedConfig.CoreConfig.Labels {
+					Expect(ingress.Labels).To(HaveKeyWithValue(key, val))
+				}
+				Expect(ingress.Annotations).To(HaveKeyWithValue("user", "annotation"))
+				Expect(ingress.Spec).To(Equal(*expectedConfig.CoreConfig.IngressSpec))
+			})
+		})
+		Context("after removing the ingress configuration", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithIngress())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.NetworkOptions.GrafanaConfig = nil
+				})
+				t.reconcileCryostat()
+			})
+			It("should delete the ingress", func() {
+				ingress := &netv1.Ingress{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-grafana", Namespace: "default"}, ingress)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+			It("should keep the remaining ingress", func() {
+				ingress := &netv1.Ingress{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, ingress)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+		Context("with a Gateway API configuration", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithGateway(), test.NewGateway(true))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create HTTPRoutes", func() {
+				config := test.NewGatewayConfiguration()
+				t.checkHTTPRoute("cryostat", 8181, config.CoreRoute)
+				t.checkHTTPRoute("cryostat-grafana", 3000, config.GrafanaRoute)
+			})
+			It("should not create ingresses or routes", func() {
+				t.expectNoIngresses()
+				t.expectNoRoutes()
+			})
+			It("should use the HTTPS listener for the URLs", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.ApplicationURL).To(Equal("https://cryostat.example.com"))
+				Expect(cr.Status.GrafanaURL).To(Equal("https://cryostat-grafana.example.com"))
+			})
+			Context("with a listener name", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostatWithGateway()
+					cr.Spec.NetworkOptions.GatewayConfig.GatewayRef.SectionName = "http"
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr, test.NewGateway(true)}
+				})
+				It("should attach to the listener", func() {
+					route := t.getHTTPRoute("cryostat")
+					parentRefs, _, err := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(parentRefs).To(HaveLen(1))
+					Expect(parentRefs[0]).To(HaveKeyWithValue("sectionName", "http"))
+				})
+				It("should use the listener's scheme and port for the URLs", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.ApplicationURL).To(Equal("http://cryostat.example.com:8080"))
+					Expect(cr.Status.GrafanaURL).To(Equal("http://cryostat-grafana.example.com:8080"))
+				})
+			})
+			Context("without hostnames", func() {
+				BeforeEach(func() {
+					cr := test.NewCryostatWithGateway()
+					cr.Spec.NetworkOptions.GatewayConfig.CoreRoute = nil
+					cr.Spec.NetworkOptions.GatewayConfig.GrafanaRoute = nil
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), cr, test.NewGateway(true)}
+				})
+				It("should not set hostnames in the HTTPRoutes", func() {
+					route := t.getHTTPRoute("cryostat")
+					_, found, err := unstructured.NestedSlice(route.Object, "spec", "hostnames")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+				It("should use the Gateway's address for the URLs", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.ApplicationURL).To(Equal("https://192.0.2.10"))
+					Expect(cr.Status.GrafanaURL).To(Equal("https://192.0.2.10"))
+				})
+			})
+			Context("after changing the HTTPRoute configuration", func() {
+				JustBeforeEach(func() {
+					t.addUserAnnotation(t.getHTTPRoute("cryostat"), "cryostat", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.NetworkOptions.GatewayConfig.CoreRoute.Hostnames = []string{"other.example.com"}
+						cr.Spec.NetworkOptions.GatewayConfig.CoreRoute.Labels["other"] = "label"
+					})
+					t.reconcileCryostat()
+				})
+				It("should update the HTTPRoute", func() {
+					config := test.NewGatewayConfiguration()
+					config.CoreRoute.Hostnames = []string{"other.example.com"}
+					config.CoreRoute.Labels["other"] = "label"
+					route := t.checkHTTPRoute("cryostat", 8181, config.CoreRoute)
+					Expect(route.GetAnnotations()).To(HaveKeyWithValue("user", "annotation"))
+				})
+				It("should update the application URL", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.ApplicationURL).To(Equal("https://other.example.com"))
+				})
+			})
+			Context("after removing HTTPRoute labels and annotations", func() {
+				JustBeforeEach(func() {
+					t.addUserAnnotation(t.getHTTPRoute("cryostat"), "cryostat", "default")
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.NetworkOptions.GatewayConfig.CoreRoute.Labels = nil
+						cr.Spec.NetworkOptions.GatewayConfig.CoreRoute.Annotations = nil
+					})
+					t.reconcileCryostat()
+				})
+				It("should remove them from the HTTPRoute", func() {
+					route := t.getHTTPRoute("cryostat")
+					Expect(route.GetLabels()).To(Equal(withInventoryLabel(nil)))
+					Expect(route.GetAnnotations()).To(Equal(map[string]string{"user": "annotation"}))
+				})
+			})
+			Context("after switching from ingresses", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{test.NewNamespace(), test.NewApiServer(), test.NewCryostatWithIngress(),
+						test.NewGateway(true)}
+				})
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.NetworkOptions = &operatorv1beta1.NetworkConfigurationList{
+							GatewayConfig: test.NewGatewayConfiguration(),
+						}
+					})
+					t.reconcileCryostat()
+				})
+				It("should delete the ingresses", func() {
+					t.expectNoIngresses()
+				})
+				It("should create HTTPRoutes", func() {
+					config := test.NewGatewayConfiguration()
+					t.checkHTTPRoute("cryostat", 8181, config.CoreRoute)
+					t.checkHTTPRoute("cryostat-grafana", 3000, config.GrafanaRoute)
+				})
+			})
+			Context("after removing the Gateway API configuration", func() {
+				JustBeforeEach(func() {
+					t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+						cr.Spec.NetworkOptions = nil
+					})
+					t.reconcileCryostat()
+				})
+				It("should delete the HTTPRoutes", func() {
+					t.expectNoHTTPRoutes()
+				})
+			})
+		})
+		Context("with a Gateway that does not exist", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithGateway())
+			})
+			It("should create HTTPRoutes and requeue", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+
+				t.makeCertificatesReady()
+				t.initializeSecrets()
+
+				result, err = t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+				t.getHTTPRoute("cryostat-grafana")
+			})
 		})
-		Context("with TLS ingress", func() {
+		Context("with Gateway API unavailable", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, test.NewCryostatWithIngress())
-			})
-			It("should create ingresses", func() {
-				t.expectIngresses()
+				t.objs = append(t.objs, test.NewCryostatWithGateway(), test.NewGateway(true))
 			})
-			It("should not create routes", func() {
-				t.reconcileCryostatFully()
-				t.expectNoRoutes()
-			})
-			It("should create deployment and set owner", func() {
-				t.expectDeployment()
+			JustBeforeEach(func() {
+				t.controller.RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.controller.IsOpenShift = false
 			})
-			It("should create RBAC", func() {
-				t.expectRBAC()
+			It("should return an error", func() {
+				// cert-manager is also unavailable, so disable it
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					certManager := false
+					cr.Spec.EnableCertManager = &certManager
+				})
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Gateway API"))
 			})
 		})
-		Context("with non-TLS ingress", func() {
+		Context("with network policies", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, test.NewCryostatWithIngressNoTLS())
-				t.externalTLS = false
-			})
-			It("should create ingresses", func() {
-				t.expectIngresses()
+				t.objs = append(t.objs, test.NewCryostatWithNetworkPolicies())
 			})
-			It("should not create routes", func() {
+			It("should allow connections from the default ingress controller", func() {
 				t.reconcileCryostatFully()
-				t.expectNoRoutes()
-			})
-			It("should create deployment and set owner", func() {
-				t.expectDeployment()
-			})
-			It("should create RBAC", func() {
-				t.expectRBAC()
+				t.checkNetworkPolicy(test.NewCoreNetworkPolicy(false, false))
 			})
 		})
 		Context("no ingress configuration is provided", func() {
@@ -1260,14 +3103,14 @@ var _ = Describe("CryostatController", func() {
 				ingress := &netv1.Ingress{}
 				err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, ingress)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(ingress.Annotations).To(Equal(expectedConfig.CoreConfig.Annotations))
-				Expect(ingress.Labels).To(Equal(expectedConfig.CoreConfig.Labels))
+				Expect(ingress.Annotations).To(Equal(withManagedKeys(expectedConfig.CoreConfig.Labels, expectedConfig.CoreConfig.Annotations)))
+				Expect(ingress.Labels).To(Equal(withInventoryLabel(expectedConfig.CoreConfig.Labels)))
 				Expect(ingress.Spec).To(Equal(*expectedConfig.CoreConfig.IngressSpec))
 
 				err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-grafana", Namespace: "default"}, ingress)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(ingress.Annotations).To(Equal(expectedConfig.GrafanaConfig.Annotations))
-				Expect(ingress.Labels).To(Equal(expectedConfig.GrafanaConfig.Labels))
+				Expect(ingress.Annotations).To(Equal(withManagedKeys(expectedConfig.GrafanaConfig.Labels, expectedConfig.GrafanaConfig.Annotations)))
+				Expect(ingress.Labels).To(Equal(withInventoryLabel(expectedConfig.GrafanaConfig.Labels)))
 				Expect(ingress.Spec).To(Equal(*expectedConfig.GrafanaConfig.IngressSpec))
 
 			})
@@ -1290,14 +3133,31 @@ var _ = Describe("CryostatController", func() {
 				ingress := &netv1.Ingress{}
 				err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-grafana", Namespace: "default"}, ingress)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(ingress.Annotations).To(Equal(expectedConfig.GrafanaConfig.Annotations))
-				Expect(ingress.Labels).To(Equal(expectedConfig.GrafanaConfig.Labels))
+				Expect(ingress.Annotations).To(Equal(withManagedKeys(expectedConfig.GrafanaConfig.Labels, expectedConfig.GrafanaConfig.Annotations)))
+				Expect(ingress.Labels).To(Equal(withInventoryLabel(expectedConfig.GrafanaConfig.Labels)))
 				Expect(ingress.Spec).To(Equal(*expectedConfig.GrafanaConfig.IngressSpec))
 
 				err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, ingress)
 				Expect(kerrors.IsNotFound(err)).To(BeTrue())
 			})
 		})
+		Context("with an ingress that is later removed", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostatWithIngress())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.updateCryostat(func(cr *operatorv1beta1.Cryostat) {
+					cr.Spec.NetworkOptions.CoreConfig.IngressSpec = nil
+				})
+				t.reconcileCryostat()
+			})
+			It("should clear the application URL", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.ApplicationURL).To(BeEmpty())
+				Expect(cr.Status.GrafanaURL).To(Equal("https://cryostat-grafana.example.com"))
+			})
+		})
 	})
 })
 
@@ -1308,17 +3168,18 @@ func newFakeSecret(name string) *corev1.Secret {
 			Namespace: "default",
 		},
 		Data: map[string][]byte{
-			corev1.TLSCertKey: []byte(name + "-bytes"),
+			corev1.TLSCertKey:          []byte(name + "-bytes"),
+			resource_definitions.CAKey: []byte("cryostat-ca-bytes"),
 		},
 	}
 }
 
 func (t *cryostatTestInput) makeCertificatesReady() {
-	certNames := []string{"cryostat", "cryostat-ca", "cryostat-grafana", "cryostat-reports"}
-	for _, certName := range certNames {
-		cert := &certv1.Certificate{}
-		err := t.Client.Get(context.Background(), types.NamespacedName{Name: certName, Namespace: "default"}, cert)
-		Expect(err).ToNot(HaveOccurred())
+	certs := &certv1.CertificateList{}
+	err := t.Client.List(context.Background(), certs, ctrlclient.InNamespace("default"))
+	Expect(err).ToNot(HaveOccurred())
+	for i := range certs.Items {
+		cert := &certs.Items[i]
 		cert.Status.Conditions = append(cert.Status.Conditions, certv1.CertificateCondition{
 			Type:   certv1.CertificateConditionReady,
 			Status: certMeta.ConditionTrue,
@@ -1376,7 +3237,12 @@ func (t *cryostatTestInput) checkRoute(name string) *openshiftv1.Route {
 		Expect(route.Spec.TLS.InsecureEdgeTerminationPolicy).To(Equal(openshiftv1.InsecureEdgeTerminationPolicyRedirect))
 	} else {
 		Expect(route.Spec.TLS.Termination).To(Equal(openshiftv1.TLSTerminationReencrypt))
-		Expect(route.Spec.TLS.DestinationCACertificate).To(Equal("cryostat-ca-bytes"))
+		if t.operatorCA {
+			caSecret := t.getSecret("cryostat-ca")
+			Expect(route.Spec.TLS.DestinationCACertificate).To(Equal(string(caSecret.Data[corev1.TLSCertKey])))
+		} else {
+			Expect(route.Spec.TLS.DestinationCACertificate).To(Equal("cryostat-ca-bytes"))
+		}
 	}
 	return route
 }
@@ -1408,7 +3274,7 @@ func (t *cryostatTestInput) reconcileCryostatFully() {
 	Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
 
 	// Update certificate status
-	if t.TLS {
+	if t.TLS && !t.operatorCA {
 		t.makeCertificatesReady()
 		t.initializeSecrets()
 	}
@@ -1423,9 +3289,258 @@ func (t *cryostatTestInput) reconcileCryostatFully() {
 
 	result, err = t.controller.Reconcile(context.Background(), req)
 	Expect(err).ToNot(HaveOccurred())
+	t.checkReconciledResult(result)
+}
+
+func (t *cryostatTestInput) reconcileCryostat() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	t.checkReconciledResult(result)
+}
+
+func (t *cryostatTestInput) checkReconciledResult(result reconcile.Result) {
+	if t.operatorCA {
+		// Expect to be requeued when the operator's certificates are due for renewal
+		Expect(result.Requeue).To(BeFalse())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 60*24*time.Hour))
+		return
+	}
 	Expect(result).To(Equal(reconcile.Result{}))
 }
 
+func (t *cryostatTestInput) getDeploymentTemplate(deployName string) *corev1.PodTemplateSpec {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: deployName, Namespace: "default"}, deployment)
+	Expect(err).ToNot(HaveOccurred())
+	return &deployment.Spec.Template
+}
+
+func (t *cryostatTestInput) getConfigHash(deployName string) string {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: deployName, Namespace: "default"}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	return deploy.Spec.Template.Annotations["operator.cryostat.io/config-hash"]
+}
+
+func (t *cryostatTestInput) updateSecretData(name string, key string, value string) {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	secret.Data[key] = []byte(value)
+	err = t.Client.Update(context.Background(), secret)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (t *cryostatTestInput) checkCertificatesWithOptions() {
+	certs := []*certv1.Certificate{test.NewCryostatCert(), test.NewGrafanaCert(), test.NewReportsCert()}
+	for _, expected := range certs {
+		expected.Spec.IssuerRef = certMeta.ObjectReference{
+			Name: "corporate-issuer",
+			Kind: "ClusterIssuer",
+		}
+		expected.Spec.Duration = &metav1.Duration{Duration: 720 * time.Hour}
+		expected.Spec.RenewBefore = &metav1.Duration{Duration: 240 * time.Hour}
+		expected.Spec.PrivateKey = &certv1.CertificatePrivateKey{
+			Algorithm: certv1.ECDSAKeyAlgorithm,
+			Size:      384,
+		}
+
+		actual := &certv1.Certificate{}
+		err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, actual)
+		Expect(err).ToNot(HaveOccurred())
+		checkMetadata(actual, expected)
+		Expect(actual.Spec).To(Equal(expected.Spec))
+	}
+}
+
+func (t *cryostatTestInput) expectNoSelfSignedCA() {
+	cert := &certv1.Certificate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-ca", Namespace: "default"}, cert)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+	issuers := &certv1.IssuerList{}
+	err = t.Client.List(context.Background(), issuers, ctrlclient.InNamespace("default"))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(issuers.Items).To(BeEmpty())
+}
+
+func (t *cryostatTestInput) getPVC(name string) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, pvc)
+	Expect(err).ToNot(HaveOccurred())
+	return pvc
+}
+
+// setPVCCapacity simulates the provisioner of a PVC updating its capacity
+func (t *cryostatTestInput) setPVCCapacity(name string, capacity string) {
+	pvc := t.getPVC(name)
+	pvc.Status.Phase = corev1.ClaimBound
+	pvc.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse(capacity),
+	}
+	err := t.Client.Status().Update(context.Background(), pvc)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (t *cryostatTestInput) getMigrationClaimName() string {
+	cr := t.getCryostatInstance()
+	name, err := resource_definitions.GetMigrationClaimName(cr, resource_definitions.NewPersistentVolumeClaimForCR(cr).Spec.DeepCopy())
+	Expect(err).ToNot(HaveOccurred())
+	return name
+}
+
+func (t *cryostatTestInput) checkStorageCondition(condType operatorv1beta1.CryostatConditionType,
+	status metav1.ConditionStatus, reason string) {
+	cr := t.getCryostatInstance()
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(condType))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *cryostatTestInput) getCryostatInstance() *operatorv1beta1.Cryostat {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+	return cr
+}
+
+func (t *cryostatTestInput) getSecret(name string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	return secret
+}
+
+func (t *cryostatTestInput) checkOperatorCA() *common.KeyPair {
+	secret := t.getSecret("cryostat-ca")
+	t.checkOperatorSecretOwner(secret)
+	ca, err := common.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	Expect(err).ToNot(HaveOccurred())
+	Expect(ca.Certificate.IsCA).To(BeTrue())
+	Expect(common.IsRenewalDue(ca.Certificate, time.Now())).To(BeFalse())
+	return ca
+}
+
+func (t *cryostatTestInput) checkOperatorCertificates(ca *common.KeyPair) {
+	cr := test.NewCryostat()
+	expectedCerts := []*certv1.Certificate{
+		resource_definitions.NewCryostatCert(cr),
+		resource_definitions.NewGrafanaCert(cr),
+		resource_definitions.NewReportsCert(cr),
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	for _, expected := range expectedCerts {
+		secret := t.getSecret(expected.Spec.SecretName)
+		t.checkOperatorSecretOwner(secret)
+		Expect(secret.Data[resource_definitions.CAKey]).To(Equal(ca.CertPEM))
+
+		pair, err := common.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pair.Certificate.Subject.CommonName).To(Equal(expected.Spec.CommonName))
+		Expect(pair.Certificate.DNSNames).To(ConsistOf(expected.Spec.DNSNames))
+		_, err = pair.Certificate.Verify(x509.VerifyOptions{
+			DNSName:   expected.Spec.DNSNames[0],
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+}
+
+func (t *cryostatTestInput) checkOperatorKeystore() {
+	keystoreSecret := t.getSecret("cryostat-keystore")
+	password, found := keystoreSecret.StringData["KEYSTORE_PASS"]
+	if !found {
+		password = string(keystoreSecret.Data["KEYSTORE_PASS"])
+	}
+	secret := t.getSecret("cryostat-tls")
+	key, cert, caCerts, err := pkcs12.DecodeChain(secret.Data["keystore.p12"], password)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Expect the private key, certificate and CA certificate
+	Expect(key).ToNot(BeNil())
+	Expect(cert.Subject.CommonName).To(Equal("cryostat.default.svc"))
+	Expect(caCerts).To(HaveLen(1))
+	Expect(caCerts[0].Subject.CommonName).To(Equal("ca.cryostat.cryostat"))
+}
+
+func (t *cryostatTestInput) checkOperatorSecretOwner(secret *corev1.Secret) {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(metav1.IsControlledBy(secret, cr)).To(BeTrue())
+}
+
+func newOperatorTLSSecret(name string, pair *common.KeyPair, ca *common.KeyPair) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:          pair.CertPEM,
+			corev1.TLSPrivateKeyKey:    pair.KeyPEM,
+			resource_definitions.CAKey: ca.CertPEM,
+		},
+	}
+}
+
+func (t *cryostatTestInput) updateCryostat(update func(cr *operatorv1beta1.Cryostat)) {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+	update(cr)
+	err = t.Client.Update(context.Background(), cr)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+// addUserAnnotation simulates a user annotating a resource managed by the operator,
+// and returns the updated resource
+func (t *cryostatTestInput) addUserAnnotation(obj ctrlclient.Object, name string, namespace string) ctrlclient.Object {
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, obj)
+	Expect(err).ToNot(HaveOccurred())
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["user"] = "annotation"
+	obj.SetAnnotations(annotations)
+	err = t.Client.Update(context.Background(), obj)
+	Expect(err).ToNot(HaveOccurred())
+	return obj
+}
+
+func (t *cryostatTestInput) checkUpdatedService(svcName string, expected *corev1.Service) {
+	service := &corev1.Service{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: svcName, Namespace: "default"}, service)
+	Expect(err).ToNot(HaveOccurred())
+
+	checkMergedMetadata(service, expected)
+	Expect(service.Spec.Type).To(Equal(expected.Spec.Type))
+	Expect(service.Spec.Selector).To(Equal(expected.Spec.Selector))
+	Expect(service.Spec.Ports).To(Equal(expected.Spec.Ports))
+}
+
+// checkMergedMetadata verifies that an object has the expected labels and annotations,
+// along with the annotation added by addUserAnnotation
+func checkMergedMetadata(object metav1.Object, expected metav1.Object) {
+	for key, val := range expected.GetLabels() {
+		Expect(object.GetLabels()).To(HaveKeyWithValue(key, val))
+	}
+	for key, val := range expected.GetAnnotations() {
+		Expect(object.GetAnnotations()).To(HaveKeyWithValue(key, val))
+	}
+	Expect(object.GetAnnotations()).To(HaveKeyWithValue("user", "annotation"))
+	ownerReferences := object.GetOwnerReferences()
+	Expect(ownerReferences).To(HaveLen(1))
+	Expect(ownerReferences[0].Kind).To(Equal("Cryostat"))
+}
+
 func (t *cryostatTestInput) reconcileDeletedCryostat() {
 	// Simulate deletion by setting DeletionTimestamp
 	cr := &operatorv1beta1.Cryostat{}
@@ -1444,10 +3559,22 @@ func (t *cryostatTestInput) reconcileDeletedCryostat() {
 	Expect(result).To(Equal(reconcile.Result{}))
 }
 
+// withInventoryLabel returns the expected labels of an object managed by
+// the operator, which are labelled with the Cryostat they belong to
+func withInventoryLabel(labels map[string]string) map[string]string {
+	result := map[string]string{
+		"operator.cryostat.io/cryostat": "cryostat",
+	}
+	for key, val := range labels {
+		result[key] = val
+	}
+	return result
+}
+
 func checkMetadata(object metav1.Object, expected metav1.Object) {
 	Expect(object.GetName()).To(Equal(expected.GetName()))
 	Expect(object.GetNamespace()).To(Equal(expected.GetNamespace()))
-	Expect(object.GetLabels()).To(Equal(expected.GetLabels()))
+	Expect(object.GetLabels()).To(Equal(withInventoryLabel(expected.GetLabels())))
 	Expect(object.GetAnnotations()).To(Equal(expected.GetAnnotations()))
 	ownerReferences := object.GetOwnerReferences()
 	Expect(ownerReferences).To(HaveLen(1))
@@ -1455,6 +3582,37 @@ func checkMetadata(object metav1.Object, expected metav1.Object) {
 	Expect(ownerReferences[0].Name).To(Equal("cryostat"))
 }
 
+// checkManagedMetadata verifies that an object has exactly the expected labels and annotations,
+// along with the annotations recording which of them the operator manages
+func checkManagedMetadata(object metav1.Object, expected metav1.Object) {
+	expected = expected.(runtime.Object).DeepCopyObject().(metav1.Object)
+	expected.SetAnnotations(withManagedKeys(expected.GetLabels(), expected.GetAnnotations()))
+	checkMetadata(object, expected)
+}
+
+// withManagedKeys returns the provided annotations along with the annotations
+// recording the keys of the provided labels and annotations
+func withManagedKeys(labels map[string]string, annotations map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, val := range annotations {
+		result[key] = val
+	}
+	for annotation, values := range map[string]map[string]string{
+		"operator.cryostat.io/managed-labels":      labels,
+		"operator.cryostat.io/managed-annotations": annotations,
+	} {
+		keys := []string{}
+		for key := range values {
+			keys = append(keys, key)
+		}
+		if len(keys) > 0 {
+			sort.Strings(keys)
+			result[annotation] = strings.Join(keys, ",")
+		}
+	}
+	return result
+}
+
 func (t *cryostatTestInput) expectCertificates() {
 	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
 	result, err := t.controller.Reconcile(context.Background(), req)
@@ -1579,14 +3737,14 @@ func (t *cryostatTestInput) expectIngresses() {
 	ingress := &netv1.Ingress{}
 	err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, ingress)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(ingress.Annotations).To(Equal(expectedConfig.CoreConfig.Annotations))
-	Expect(ingress.Labels).To(Equal(expectedConfig.CoreConfig.Labels))
+	Expect(ingress.Annotations).To(Equal(withManagedKeys(expectedConfig.CoreConfig.Labels, expectedConfig.CoreConfig.Annotations)))
+	Expect(ingress.Labels).To(Equal(withInventoryLabel(expectedConfig.CoreConfig.Labels)))
 	Expect(ingress.Spec).To(Equal(*expectedConfig.CoreConfig.IngressSpec))
 
 	err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-grafana", Namespace: "default"}, ingress)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(ingress.Annotations).To(Equal(expectedConfig.GrafanaConfig.Annotations))
-	Expect(ingress.Labels).To(Equal(expectedConfig.GrafanaConfig.Labels))
+	Expect(ingress.Annotations).To(Equal(withManagedKeys(expectedConfig.GrafanaConfig.Labels, expectedConfig.GrafanaConfig.Annotations)))
+	Expect(ingress.Labels).To(Equal(withInventoryLabel(expectedConfig.GrafanaConfig.Labels)))
 	Expect(ingress.Spec).To(Equal(*expectedConfig.GrafanaConfig.IngressSpec))
 }
 
@@ -1597,6 +3755,79 @@ func (t *cryostatTestInput) expectNoIngresses() {
 	err = t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-grafana", Namespace: "default"}, ing)
 	Expect(kerrors.IsNotFound(err)).To(BeTrue())
 }
+func (t *cryostatTestInput) getNetworkPolicy(name string) *netv1.NetworkPolicy {
+	policy := &netv1.NetworkPolicy{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, policy)
+	Expect(err).ToNot(HaveOccurred())
+	return policy
+}
+
+func (t *cryostatTestInput) checkNetworkPolicy(expected *netv1.NetworkPolicy) {
+	policy := t.getNetworkPolicy(expected.Name)
+	checkMetadata(policy, expected)
+	Expect(policy.Spec).To(Equal(expected.Spec))
+}
+
+func (t *cryostatTestInput) expectNoNetworkPolicy(name string) {
+	policy := &netv1.NetworkPolicy{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, policy)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func (t *cryostatTestInput) getHTTPRoute(name string) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, route)
+	Expect(err).ToNot(HaveOccurred())
+	return route
+}
+
+func (t *cryostatTestInput) checkHTTPRoute(name string, port int64,
+	config *operatorv1beta1.HTTPRouteConfiguration) *unstructured.Unstructured {
+	route := t.getHTTPRoute(name)
+	cr := t.getCryostatInstance()
+	Expect(metav1.IsControlledBy(route, cr)).To(BeTrue())
+	Expect(route.GetLabels()).To(Equal(withInventoryLabel(config.Labels)))
+	for key, val := range config.Annotations {
+		Expect(route.GetAnnotations()).To(HaveKeyWithValue(key, val))
+	}
+
+	hostnames, _, err := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(hostnames).To(Equal(config.Hostnames))
+
+	parentRefs, _, err := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(parentRefs).To(ConsistOf(map[string]interface{}{
+		"group":     "gateway.networking.k8s.io",
+		"kind":      "Gateway",
+		"name":      "cryostat-gateway",
+		"namespace": "gateways",
+	}))
+
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(rules).To(HaveLen(1))
+	backendRefs, _, err := unstructured.NestedSlice(rules[0].(map[string]interface{}), "backendRefs")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(backendRefs).To(ConsistOf(map[string]interface{}{
+		"group":  "",
+		"kind":   "Service",
+		"name":   name,
+		"port":   port,
+		"weight": int64(1),
+	}))
+	return route
+}
+
+func (t *cryostatTestInput) expectNoHTTPRoutes() {
+	for _, name := range []string{"cryostat", "cryostat-grafana"} {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+		err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, route)
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	}
+}
 
 func (t *cryostatTestInput) expectPVC(expectedPvc *corev1.PersistentVolumeClaim) {
 	pvc := &corev1.PersistentVolumeClaim{}
@@ -1670,6 +3901,63 @@ func (t *cryostatTestInput) expectStatusApplicationURL() {
 	Expect(instance.Status.ApplicationURL).To(Equal("https://cryostat.example.com"))
 }
 
+func (t *cryostatTestInput) checkStatusDeploymentDetails() {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+
+	Expect(cr.Status.ObservedGeneration).To(Equal(cr.Generation))
+
+	coreImg := resource_definitions.DefaultCoreImageTag
+	if t.EnvCoreImageTag != nil {
+		coreImg = *t.EnvCoreImageTag
+	}
+	expectedImages := &operatorv1beta1.ImageStatus{
+		Core: coreImg,
+	}
+	expectedGrafanaURL := ""
+	if !t.minimal {
+		expectedGrafanaURL = "https://cryostat-grafana.example.com"
+		expectedImages.Datasource = resource_definitions.DefaultDatasourceImageTag
+		if t.EnvDatasourceImageTag != nil {
+			expectedImages.Datasource = *t.EnvDatasourceImageTag
+		}
+		expectedImages.Grafana = resource_definitions.DefaultGrafanaImageTag
+		if t.EnvGrafanaImageTag != nil {
+			expectedImages.Grafana = *t.EnvGrafanaImageTag
+		}
+	}
+	expectedReportsURL := ""
+	if t.reportReplicas > 0 {
+		svc := &corev1.Service{}
+		err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-reports", Namespace: "default"}, svc)
+		Expect(err).ToNot(HaveOccurred())
+		scheme := "https"
+		if !t.TLS {
+			scheme = "http"
+		}
+		expectedReportsURL = fmt.Sprintf("%s://cryostat-reports:%d", scheme, svc.Spec.Ports[0].Port)
+		expectedImages.Reports = resource_definitions.DefaultReportsImageTag
+		if t.EnvReportsImageTag != nil {
+			expectedImages.Reports = *t.EnvReportsImageTag
+		}
+	}
+	Expect(cr.Status.GrafanaURL).To(Equal(expectedGrafanaURL))
+	Expect(cr.Status.ReportsURL).To(Equal(expectedReportsURL))
+	Expect(cr.Status.Images).To(Equal(expectedImages))
+	Expect(cr.Status.Version).To(Equal(coreImg[strings.LastIndex(coreImg, ":")+1:]))
+}
+
+func (t *cryostatTestInput) checkConditionMessage(condType operatorv1beta1.CryostatConditionType, substr string) {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(condType))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Message).To(ContainSubstring(substr))
+}
+
 func (t *cryostatTestInput) expectDeployment() {
 	deployment := &appsv1.Deployment{}
 	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, deployment)
@@ -1737,7 +4025,7 @@ func (t *cryostatTestInput) checkService(svcName string, expected *corev1.Servic
 	err := t.Client.Get(context.Background(), types.NamespacedName{Name: svcName, Namespace: "default"}, service)
 	Expect(err).ToNot(HaveOccurred())
 
-	checkMetadata(service, expected)
+	checkManagedMetadata(service, expected)
 	Expect(service.Spec.Type).To(Equal(expected.Spec.Type))
 	Expect(service.Spec.Selector).To(Equal(expected.Spec.Selector))
 	Expect(service.Spec.Ports).To(Equal(expected.Spec.Ports))
@@ -1831,10 +4119,11 @@ func (t *cryostatTestInput) checkMainDeployment() {
 		"app.openshift.io/connects-to": "cryostat-operator-controller-manager",
 	}))
 	Expect(deployment.Labels).To(Equal(map[string]string{
-		"app":                    "cryostat",
-		"kind":                   "cryostat",
-		"component":              "cryostat",
-		"app.kubernetes.io/name": "cryostat",
+		"app":                           "cryostat",
+		"kind":                          "cryostat",
+		"component":                     "cryostat",
+		"app.kubernetes.io/name":        "cryostat",
+		"operator.cryostat.io/cryostat": "cryostat",
 	}))
 	Expect(metav1.IsControlledBy(deployment, cr)).To(BeTrue())
 	Expect(deployment.Spec.Selector).To(Equal(test.NewMainDeploymentSelector()))
@@ -1849,7 +4138,15 @@ func (t *cryostatTestInput) checkMainDeployment() {
 		"component": "cryostat",
 	}))
 	Expect(template.Spec.Volumes).To(Equal(test.NewVolumes(t.minimal, t.TLS)))
-	Expect(template.Spec.SecurityContext).To(Equal(test.NewPodSecurityContext()))
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.PodSecurityContext != nil {
+		Expect(template.Spec.SecurityContext).To(Equal(cr.Spec.SecurityOptions.PodSecurityContext))
+	} else {
+		Expect(template.Spec.SecurityContext).To(Equal(test.NewPodSecurityContext()))
+	}
+	securityOptions := cr.Spec.SecurityOptions
+	if securityOptions == nil {
+		securityOptions = &operatorv1beta1.SecurityOptions{}
+	}
 
 	// Check that the networking environment variables are set correctly
 	coreContainer := template.Spec.Containers[0]
@@ -1867,20 +4164,25 @@ func (t *cryostatTestInput) checkMainDeployment() {
 		reportsUrl = "http://cryostat-reports:" + port
 	}
 
-	checkCoreContainer(&coreContainer, t.minimal, t.TLS, t.externalTLS, t.EnvCoreImageTag, t.controller.IsOpenShift, reportsUrl, cr.Spec.Resources.CoreResources)
+	checkCoreContainer(&coreContainer, t.minimal, t.TLS, t.externalTLS, t.EnvCoreImageTag, t.controller.IsOpenShift, reportsUrl,
+		cr.Spec.Resources.CoreResources, securityOptions.CoreSecurityContext)
 
 	if !t.minimal {
 		// Check that Grafana is configured properly, depending on the environment
 		grafanaContainer := template.Spec.Containers[1]
-		checkGrafanaContainer(&grafanaContainer, t.TLS, t.EnvGrafanaImageTag, cr.Spec.Resources.GrafanaResources)
+		checkGrafanaContainer(&grafanaContainer, t.TLS, t.EnvGrafanaImageTag, cr.Spec.Resources.GrafanaResources,
+			securityOptions.GrafanaSecurityContext)
 
 		// Check that JFR Datasource is configured properly
 		datasourceContainer := template.Spec.Containers[2]
-		checkDatasourceContainer(&datasourceContainer, t.EnvDatasourceImageTag, cr.Spec.Resources.DataSourceResources)
+		checkDatasourceContainer(&datasourceContainer, t.EnvDatasourceImageTag, cr.Spec.Resources.DataSourceResources,
+			securityOptions.DataSourceSecurityContext)
 	}
 
 	// Check that the proper Service Account is set
 	Expect(template.Spec.ServiceAccountName).To(Equal("cryostat"))
+
+	checkSchedulingOptions(&template.Spec, cr.Spec.SchedulingOptions)
 }
 
 func (t *cryostatTestInput) checkReportsDeployment() {
@@ -1898,10 +4200,11 @@ func (t *cryostatTestInput) checkReportsDeployment() {
 		"app.openshift.io/connects-to": "cryostat",
 	}))
 	Expect(deployment.Labels).To(Equal(map[string]string{
-		"app":                    "cryostat",
-		"kind":                   "cryostat",
-		"component":              "reports",
-		"app.kubernetes.io/name": "cryostat-reports",
+		"app":                           "cryostat",
+		"kind":                          "cryostat",
+		"component":                     "reports",
+		"app.kubernetes.io/name":        "cryostat-reports",
+		"operator.cryostat.io/cryostat": "cryostat",
 	}))
 	Expect(metav1.IsControlledBy(deployment, cr)).To(BeTrue())
 	Expect(deployment.Spec.Selector).To(Equal(test.NewReportsDeploymentSelector()))
@@ -1919,12 +4222,54 @@ func (t *cryostatTestInput) checkReportsDeployment() {
 	Expect(template.Spec.Volumes).To(Equal(test.NewReportsVolumes(t.TLS)))
 
 	var resources corev1.ResourceRequirements
+	securityOptions := &operatorv1beta1.ReportsSecurityOptions{}
 	if cr.Spec.ReportOptions != nil {
 		resources = cr.Spec.ReportOptions.Resources
+		if cr.Spec.ReportOptions.SecurityOptions != nil {
+			securityOptions = cr.Spec.ReportOptions.SecurityOptions
+		}
 	}
-	checkReportsContainer(&template.Spec.Containers[0], t.TLS, t.EnvReportsImageTag, resources)
+	if securityOptions.PodSecurityContext != nil {
+		Expect(template.Spec.SecurityContext).To(Equal(securityOptions.PodSecurityContext))
+	} else {
+		Expect(template.Spec.SecurityContext).To(Equal(test.NewReportsPodSecurityContext()))
+	}
+	checkReportsContainer(&template.Spec.Containers[0], t.TLS, t.EnvReportsImageTag, resources,
+		securityOptions.ReportsSecurityContext)
 	// Check that the proper Service Account is set
 	Expect(template.Spec.ServiceAccountName).To(Equal("cryostat"))
+
+	var scheduling *operatorv1beta1.SchedulingConfiguration
+	if cr.Spec.ReportOptions != nil {
+		scheduling = cr.Spec.ReportOptions.SchedulingOptions
+	}
+	checkSchedulingOptions(&template.Spec, scheduling)
+}
+func (t *cryostatTestInput) checkReportsHorizontalPodAutoscaler(expected *autoscalingv2beta2.HorizontalPodAutoscaler) {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, hpa)
+	Expect(err).ToNot(HaveOccurred())
+	checkMetadata(hpa, expected)
+	Expect(hpa.Spec).To(Equal(expected.Spec))
+}
+
+func (t *cryostatTestInput) checkReportsPodDisruptionBudget(expected *policyv1beta1.PodDisruptionBudget) {
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, pdb)
+	Expect(err).ToNot(HaveOccurred())
+	checkMetadata(pdb, expected)
+	Expect(pdb.Spec).To(Equal(expected.Spec))
+}
+
+func checkSchedulingOptions(podSpec *corev1.PodSpec, expected *operatorv1beta1.SchedulingConfiguration) {
+	if expected == nil {
+		expected = &operatorv1beta1.SchedulingConfiguration{}
+	}
+	Expect(podSpec.NodeSelector).To(Equal(expected.NodeSelector))
+	Expect(podSpec.Affinity).To(Equal(expected.Affinity))
+	Expect(podSpec.Tolerations).To(Equal(expected.Tolerations))
+	Expect(podSpec.TopologySpreadConstraints).To(Equal(expected.TopologySpreadConstraints))
+	Expect(podSpec.PriorityClassName).To(Equal(expected.PriorityClassName))
 }
 
 func (t *cryostatTestInput) checkDeploymentHasTemplates() {
@@ -1942,7 +4287,8 @@ func (t *cryostatTestInput) checkDeploymentHasTemplates() {
 }
 
 func checkCoreContainer(container *corev1.Container, minimal bool, tls bool, externalTLS bool,
-	tag *string, openshift bool, reportsUrl string, resources corev1.ResourceRequirements) {
+	tag *string, openshift bool, reportsUrl string, resources corev1.ResourceRequirements,
+	securityContext *corev1.SecurityContext) {
 	Expect(container.Name).To(Equal("cryostat"))
 	if tag == nil {
 		Expect(container.Image).To(HavePrefix("quay.io/cryostat/cryostat:"))
@@ -1956,9 +4302,11 @@ func checkCoreContainer(container *corev1.Container, minimal bool, tls bool, ext
 	Expect(container.LivenessProbe).To(Equal(test.NewCoreLivenessProbe(tls)))
 	Expect(container.StartupProbe).To(Equal(test.NewCoreStartupProbe(tls)))
 	Expect(container.Resources).To(Equal(resources))
+	checkContainerSecurityContext(container, securityContext)
 }
 
-func checkGrafanaContainer(container *corev1.Container, tls bool, tag *string, resources corev1.ResourceRequirements) {
+func checkGrafanaContainer(container *corev1.Container, tls bool, tag *string, resources corev1.ResourceRequirements,
+	securityContext *corev1.SecurityContext) {
 	Expect(container.Name).To(Equal("cryostat-grafana"))
 	if tag == nil {
 		Expect(container.Image).To(HavePrefix("quay.io/cryostat/cryostat-grafana-dashboard:"))
@@ -1971,9 +4319,11 @@ func checkGrafanaContainer(container *corev1.Container, tls bool, tag *string, r
 	Expect(container.VolumeMounts).To(ConsistOf(test.NewGrafanaVolumeMounts(tls)))
 	Expect(container.LivenessProbe).To(Equal(test.NewGrafanaLivenessProbe(tls)))
 	Expect(container.Resources).To(Equal(resources))
+	checkContainerSecurityContext(container, securityContext)
 }
 
-func checkDatasourceContainer(container *corev1.Container, tag *string, resources corev1.ResourceRequirements) {
+func checkDatasourceContainer(container *corev1.Container, tag *string, resources corev1.ResourceRequirements,
+	securityContext *corev1.SecurityContext) {
 	Expect(container.Name).To(Equal("cryostat-jfr-datasource"))
 	if tag == nil {
 		Expect(container.Image).To(HavePrefix("quay.io/cryostat/jfr-datasource:"))
@@ -1986,9 +4336,11 @@ func checkDatasourceContainer(container *corev1.Container, tag *string, resource
 	Expect(container.VolumeMounts).To(BeEmpty())
 	Expect(container.LivenessProbe).To(Equal(test.NewDatasourceLivenessProbe()))
 	Expect(container.Resources).To(Equal(resources))
+	checkContainerSecurityContext(container, securityContext)
 }
 
-func checkReportsContainer(container *corev1.Container, tls bool, tag *string, resources corev1.ResourceRequirements) {
+func checkReportsContainer(container *corev1.Container, tls bool, tag *string, resources corev1.ResourceRequirements,
+	securityContext *corev1.SecurityContext) {
 	Expect(container.Name).To(Equal("cryostat-reports"))
 	if tag == nil {
 		Expect(container.Image).To(HavePrefix("quay.io/cryostat/cryostat-reports:"))
@@ -2000,6 +4352,14 @@ func checkReportsContainer(container *corev1.Container, tls bool, tag *string, r
 	Expect(container.VolumeMounts).To(ConsistOf(test.NewReportsVolumeMounts(tls)))
 	Expect(container.LivenessProbe).To(Equal(test.NewReportsLivenessProbe(tls)))
 	Expect(container.Resources).To(Equal(resources))
+	checkContainerSecurityContext(container, securityContext)
+}
+
+func checkContainerSecurityContext(container *corev1.Container, expected *corev1.SecurityContext) {
+	if expected == nil {
+		expected = test.NewContainerSecurityContext()
+	}
+	Expect(container.SecurityContext).To(Equal(expected))
 }
 
 func (t *cryostatTestInput) checkEnvironmentVariables(expectedEnvVars []corev1.EnvVar) {
@@ -0,0 +1,380 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+)
+
+// CryostatBackupReconciler reconciles a CryostatBackup object
+type CryostatBackupReconciler struct {
+	client.Client
+	Log         logr.Logger
+	Scheme      *runtime.Scheme
+	IsOpenShift bool
+	RESTMapper  meta.RESTMapper
+	common.ReconcilerTLS
+}
+
+// VolumeSnapshots are managed as unstructured objects, so that the operator
+// does not depend on the external snapshotter's client libraries
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   resources.VolumeSnapshotAPIGroup,
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// Reasons for CryostatBackup and CryostatRestore Conditions
+const (
+	reasonStorageInvalidSpec        = "InvalidSpec"
+	reasonStorageCryostatNotFound   = "CryostatNotFound"
+	reasonStorageNotPersistent      = "StorageNotPersistent"
+	reasonStorageInProgress         = "InProgress"
+	reasonStorageSucceeded          = "Succeeded"
+	reasonStorageJobFailed          = "JobFailed"
+	reasonVolumeSnapshotUnavailable = "VolumeSnapshotUnavailable"
+	reasonVolumeSnapshotFailed      = "VolumeSnapshotFailed"
+)
+
+// How long to wait before checking again for a missing prerequisite
+const storageOperationRequeueDelay = 10 * time.Second
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostatbackups,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostatbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostatbackups/finalizers,verbs=update
+// +kubebuilder:rbac:namespace=system,groups=batch,resources=jobs,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=create;get;list;watch;delete
+
+// Reconcile backs up the persistent storage of a Cryostat, using either a Job
+// or a VolumeSnapshot, and records the result in the CryostatBackup's status
+func (r *CryostatBackupReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling CryostatBackup")
+
+	// Fetch the CryostatBackup instance
+	backup := &operatorv1beta1.CryostatBackup{}
+	err := r.Client.Get(ctx, request.NamespacedName, backup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// A backup is only performed once
+	if isStorageOperationFinished(backup.Status.Phase) {
+		return reconcile.Result{}, nil
+	}
+
+	dest := backup.Spec.Destination
+	if (dest.PVC == nil) == (dest.VolumeSnapshot == nil) {
+		// Not recoverable without a change to the spec, so don't requeue
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationFailed,
+			reasonStorageInvalidSpec, "Exactly one of destination.pvc and destination.volumeSnapshot must be specified")
+	}
+
+	cr, err := getStorageCryostat(ctx, r.Client, backup.Namespace, backup.Spec.CryostatName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("Waiting for Cryostat", "name", backup.Spec.CryostatName)
+			return reconcile.Result{RequeueAfter: storageOperationRequeueDelay},
+				r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationPending, reasonStorageCryostatNotFound,
+					fmt.Sprintf("Cryostat %s does not exist", backup.Spec.CryostatName))
+		}
+		return reconcile.Result{}, err
+	}
+	if !hasPersistentStorage(cr) {
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationFailed,
+			reasonStorageNotPersistent, fmt.Sprintf("Cryostat %s does not use a persistent volume claim", cr.Name))
+	}
+
+	if backup.Status.StartTime == nil {
+		now := metav1.Now()
+		backup.Status.StartTime = &now
+		if dest.PVC != nil {
+			backup.Status.Paths = getStoragePaths(backup.Spec.Paths)
+		} else {
+			// Snapshots always contain the entire volume
+			backup.Status.Paths = operatorv1beta1.AllStoragePaths
+		}
+	}
+
+	if dest.PVC != nil {
+		return r.reconcileBackupJob(ctx, backup, cr)
+	}
+	return r.reconcileVolumeSnapshot(ctx, backup, cr)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CryostatBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.CryostatBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+func (r *CryostatBackupReconciler) reconcileBackupJob(ctx context.Context, backup *operatorv1beta1.CryostatBackup,
+	cr *operatorv1beta1.Cryostat) (reconcile.Result, error) {
+	fsGroup, err := getFSGroup(ctx, r.Client, r.IsOpenShift, cr.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	image := getEnvOrDefault(r, coreImageTagEnv, resources.DefaultCoreImageTag)
+	job := resources.NewJobForBackup(backup, cr, backup.Status.Paths, image, *fsGroup)
+	if err := getOrCreateStorageJob(ctx, r.Client, r.Scheme, r.Log, backup, job); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	succeeded, failure := getJobResult(job)
+	if succeeded {
+		backup.Status.Archive = &operatorv1beta1.BackupArchive{
+			ClaimName: backup.Spec.Destination.PVC.ClaimName,
+			Path:      resources.GetBackupArchivePath(backup),
+		}
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationSucceeded,
+			reasonStorageSucceeded, fmt.Sprintf("Backup archive written to %s", backup.Status.Archive.Path))
+	}
+	if len(failure) > 0 {
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationFailed,
+			reasonStorageJobFailed, failure)
+	}
+	// Job status changes trigger another reconcile
+	return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationRunning,
+		reasonStorageInProgress, fmt.Sprintf("Job %s is running", job.Name))
+}
+
+func (r *CryostatBackupReconciler) reconcileVolumeSnapshot(ctx context.Context, backup *operatorv1beta1.CryostatBackup,
+	cr *operatorv1beta1.Cryostat) (reconcile.Result, error) {
+	available, err := volumeSnapshotsAvailable(r.RESTMapper)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !available {
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationFailed,
+			reasonVolumeSnapshotUnavailable, "The VolumeSnapshot API is not available in the cluster")
+	}
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err = r.Client.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, snapshot)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		snapshot = newVolumeSnapshot(backup, cr)
+		if err := controllerutil.SetControllerReference(backup, snapshot, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.Client.Create(ctx, snapshot); err != nil {
+			return reconcile.Result{}, err
+		}
+		r.Log.Info("VolumeSnapshot created", "namespace", snapshot.GetNamespace(), "name", snapshot.GetName())
+	}
+
+	ready, _, err := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if ready {
+		backup.Status.VolumeSnapshotName = snapshot.GetName()
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationSucceeded,
+			reasonStorageSucceeded, fmt.Sprintf("VolumeSnapshot %s is ready to use", snapshot.GetName()))
+	}
+	message, found, err := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if found {
+		return reconcile.Result{}, r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationFailed,
+			reasonVolumeSnapshotFailed, message)
+	}
+	// VolumeSnapshots are not watched, since their API may not be installed
+	err = r.updateBackupStatus(ctx, backup, operatorv1beta1.StorageOperationRunning,
+		reasonStorageInProgress, fmt.Sprintf("Waiting for VolumeSnapshot %s to become ready", snapshot.GetName()))
+	return reconcile.Result{RequeueAfter: 5 * time.Second}, err
+}
+
+func (r *CryostatBackupReconciler) updateBackupStatus(ctx context.Context, backup *operatorv1beta1.CryostatBackup,
+	phase operatorv1beta1.StorageOperationPhase, reason string, message string) error {
+	setStorageOperationStatus(&backup.Status.Phase, &backup.Status.CompletionTime, &backup.Status.Conditions,
+		phase, reason, message)
+	return r.Client.Status().Update(ctx, backup)
+}
+
+func newVolumeSnapshot(backup *operatorv1beta1.CryostatBackup, cr *operatorv1beta1.Cryostat) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": resources.GetStorageClaimName(cr),
+		},
+	}
+	className := backup.Spec.Destination.VolumeSnapshot.VolumeSnapshotClassName
+	if className != nil {
+		spec["volumeSnapshotClassName"] = *className
+	}
+	snapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(backup.Name)
+	snapshot.SetNamespace(backup.Namespace)
+	snapshot.SetLabels(map[string]string{
+		"app": cr.Name,
+	})
+	return snapshot
+}
+
+func volumeSnapshotsAvailable(mapper meta.RESTMapper) (bool, error) {
+	_, err := mapper.RESTMapping(volumeSnapshotGVK.GroupKind(), volumeSnapshotGVK.Version)
+	if err != nil {
+		// No matches for VolumeSnapshot GVK
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		// Unexpected error occurred
+		return false, err
+	}
+	return true, nil
+}
+
+func getStorageCryostat(ctx context.Context, c client.Client, namespace string,
+	name string) (*operatorv1beta1.Cryostat, error) {
+	cr := &operatorv1beta1.Cryostat{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cr)
+	if err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func hasPersistentStorage(cr *operatorv1beta1.Cryostat) bool {
+	return !resources.UsesEmptyDir(cr)
+}
+
+func getStoragePaths(paths []operatorv1beta1.StoragePath) []operatorv1beta1.StoragePath {
+	if len(paths) == 0 {
+		return operatorv1beta1.AllStoragePaths
+	}
+	return paths
+}
+
+func isStorageOperationFinished(phase operatorv1beta1.StorageOperationPhase) bool {
+	return phase == operatorv1beta1.StorageOperationSucceeded || phase == operatorv1beta1.StorageOperationFailed
+}
+
+func getOrCreateStorageJob(ctx context.Context, c client.Client, scheme *runtime.Scheme, log logr.Logger,
+	owner metav1.Object, job *batchv1.Job) error {
+	// The pod template of a Job cannot be changed, so an existing Job is used as-is
+	err := c.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, job)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(owner, job, scheme); err != nil {
+		return err
+	}
+	if err := c.Create(ctx, job); err != nil {
+		return err
+	}
+	log.Info("Job created", "namespace", job.Namespace, "name", job.Name)
+	return nil
+}
+
+// getJobResult returns whether the Job completed successfully, or the
+// reason it failed
+func getJobResult(job *batchv1.Job) (bool, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, ""
+		case batchv1.JobFailed:
+			message := condition.Message
+			if len(message) == 0 {
+				message = fmt.Sprintf("Job %s failed", job.Name)
+			}
+			return false, message
+		}
+	}
+	return false, ""
+}
+
+func setStorageOperationStatus(currentPhase *operatorv1beta1.StorageOperationPhase, completionTime **metav1.Time,
+	conditions *[]metav1.Condition, phase operatorv1beta1.StorageOperationPhase, reason string, message string) {
+	*currentPhase = phase
+	status := metav1.ConditionFalse
+	if phase == operatorv1beta1.StorageOperationSucceeded {
+		status = metav1.ConditionTrue
+	}
+	if isStorageOperationFinished(phase) && *completionTime == nil {
+		now := metav1.Now()
+		*completionTime = &now
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeStorageOperationComplete),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,355 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type cryostatBackupTestInput struct {
+	controller *controllers.CryostatBackupReconciler
+	objs       []runtime.Object
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("CryostatBackupController", func() {
+	var t *cryostatBackupTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.controller = &controllers.CryostatBackupReconciler{
+			Client:        t.Client,
+			Scheme:        s,
+			Log:           logger,
+			RESTMapper:    test.NewTESTRESTMapper(),
+			ReconcilerTLS: test.NewTestReconcilerTLS(&t.TestReconcilerConfig),
+		}
+	})
+
+	BeforeEach(func() {
+		coreImg := "my/core-image:1.0"
+		t = &cryostatBackupTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackup(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				EnvCoreImageTag: &coreImg,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new CryostatBackup", func() {
+			It("should create a Job", func() {
+				t.expectBackupReconcileSuccess()
+				t.checkBackupJob(test.NewBackupJob())
+			})
+			It("should be running", func() {
+				t.expectBackupReconcileSuccess()
+				backup := t.getCryostatBackup()
+				Expect(backup.Status.Phase).To(Equal(operatorv1beta1.StorageOperationRunning))
+				Expect(backup.Status.StartTime).ToNot(BeNil())
+				Expect(backup.Status.CompletionTime).To(BeNil())
+				Expect(backup.Status.Paths).To(Equal(operatorv1beta1.AllStoragePaths))
+				t.checkBackupCondition(metav1.ConditionFalse, "InProgress")
+			})
+			Context("when the Job completes", func() {
+				JustBeforeEach(func() {
+					t.expectBackupReconcileSuccess()
+					setJobCondition(t.Client, "test-backup-backup", batchv1.JobComplete, "")
+					t.expectBackupReconcileSuccess()
+				})
+				It("should record the archive", func() {
+					backup := t.getCryostatBackup()
+					Expect(backup.Status.Phase).To(Equal(operatorv1beta1.StorageOperationSucceeded))
+					Expect(backup.Status.CompletionTime).ToNot(BeNil())
+					Expect(backup.Status.Archive).To(Equal(&operatorv1beta1.BackupArchive{
+						ClaimName: "cryostat-backups",
+						Path:      "backups/test-backup.tar.gz",
+					}))
+					t.checkBackupCondition(metav1.ConditionTrue, "Succeeded")
+				})
+				It("should not run again", func() {
+					job := &batchv1.Job{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-backup-backup",
+						Namespace: "default"}, job)
+					Expect(err).ToNot(HaveOccurred())
+					err = t.Client.Delete(context.Background(), job)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.expectBackupReconcileSuccess()
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: "test-backup-backup",
+						Namespace: "default"}, job)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+			Context("when the Job fails", func() {
+				JustBeforeEach(func() {
+					t.expectBackupReconcileSuccess()
+					setJobCondition(t.Client, "test-backup-backup", batchv1.JobFailed, "BackoffLimitExceeded")
+					t.expectBackupReconcileSuccess()
+				})
+				It("should fail", func() {
+					backup := t.getCryostatBackup()
+					Expect(backup.Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+					Expect(backup.Status.CompletionTime).ToNot(BeNil())
+					Expect(backup.Status.Archive).To(BeNil())
+					t.checkBackupCondition(metav1.ConditionFalse, "JobFailed")
+				})
+			})
+		})
+		Context("with selected paths", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupWithPaths(),
+				}
+			})
+			It("should only archive those paths", func() {
+				t.expectBackupReconcileSuccess()
+				expected := test.NewBackupJob()
+				expected.Spec.Template.Spec.Containers[0].Args = []string{"-czf", "/backup/test-backup.tar.gz",
+					"--ignore-failed-read", "-C", "/cryostat", "flightrecordings", "templates"}
+				t.checkBackupJob(expected)
+				Expect(t.getCryostatBackup().Status.Paths).To(ConsistOf(operatorv1beta1.StoragePathFlightRecordings,
+					operatorv1beta1.StoragePathTemplates))
+			})
+		})
+		Context("with a VolumeSnapshot destination", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupToSnapshot(),
+				}
+			})
+			It("should create a VolumeSnapshot", func() {
+				result, err := t.reconcileBackup()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).ToNot(BeZero())
+
+				snapshot := t.getVolumeSnapshot()
+				expected := test.NewVolumeSnapshot(false)
+				Expect(snapshot.GetLabels()).To(Equal(expected.GetLabels()))
+				Expect(snapshot.Object["spec"]).To(Equal(expected.Object["spec"]))
+				Expect(metav1.IsControlledBy(snapshot, t.getCryostatBackup())).To(BeTrue())
+				Expect(t.getCryostatBackup().Status.Phase).To(Equal(operatorv1beta1.StorageOperationRunning))
+			})
+			Context("that is ready", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewVolumeSnapshot(true))
+				})
+				It("should record the snapshot", func() {
+					t.expectBackupReconcileSuccess()
+					backup := t.getCryostatBackup()
+					Expect(backup.Status.Phase).To(Equal(operatorv1beta1.StorageOperationSucceeded))
+					Expect(backup.Status.VolumeSnapshotName).To(Equal("test-backup"))
+					Expect(backup.Status.Paths).To(Equal(operatorv1beta1.AllStoragePaths))
+					t.checkBackupCondition(metav1.ConditionTrue, "Succeeded")
+				})
+			})
+			Context("that failed", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewFailedVolumeSnapshot())
+				})
+				It("should fail", func() {
+					t.expectBackupReconcileSuccess()
+					Expect(t.getCryostatBackup().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+					t.checkBackupCondition(metav1.ConditionFalse, "VolumeSnapshotFailed")
+				})
+			})
+			Context("when the VolumeSnapshot API is unavailable", func() {
+				JustBeforeEach(func() {
+					t.controller.RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				})
+				It("should fail", func() {
+					t.expectBackupReconcileSuccess()
+					Expect(t.getCryostatBackup().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+					t.checkBackupCondition(metav1.ConditionFalse, "VolumeSnapshotUnavailable")
+				})
+			})
+		})
+		Context("with both destinations", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupInvalid(),
+				}
+			})
+			It("should fail without creating a Job", func() {
+				t.expectBackupReconcileSuccess()
+				Expect(t.getCryostatBackup().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+				t.checkBackupCondition(metav1.ConditionFalse, "InvalidSpec")
+				t.expectNoJob("test-backup-backup")
+			})
+		})
+		Context("with a Cryostat using an EmptyDir", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostatWithDefaultEmptyDir(), test.NewCryostatBackup(),
+				}
+			})
+			It("should fail", func() {
+				t.expectBackupReconcileSuccess()
+				Expect(t.getCryostatBackup().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+				t.checkBackupCondition(metav1.ConditionFalse, "StorageNotPersistent")
+			})
+		})
+		Context("when the Cryostat does not exist", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostatBackup(),
+				}
+			})
+			It("should wait for the Cryostat", func() {
+				result, err := t.reconcileBackup()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).ToNot(BeZero())
+				Expect(t.getCryostatBackup().Status.Phase).To(Equal(operatorv1beta1.StorageOperationPending))
+				t.checkBackupCondition(metav1.ConditionFalse, "CryostatNotFound")
+				t.expectNoJob("test-backup-backup")
+			})
+		})
+		Context("CryostatBackup does not exist", func() {
+			It("should do nothing", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+	})
+})
+
+func (t *cryostatBackupTestInput) reconcileBackup() (reconcile.Result, error) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-backup", Namespace: "default"}}
+	return t.controller.Reconcile(context.Background(), req)
+}
+
+func (t *cryostatBackupTestInput) expectBackupReconcileSuccess() {
+	result, err := t.reconcileBackup()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *cryostatBackupTestInput) getCryostatBackup() *operatorv1beta1.CryostatBackup {
+	backup := &operatorv1beta1.CryostatBackup{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-backup", Namespace: "default"}, backup)
+	Expect(err).ToNot(HaveOccurred())
+	return backup
+}
+
+func (t *cryostatBackupTestInput) getVolumeSnapshot() *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(test.NewVolumeSnapshot(false).GroupVersionKind())
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-backup", Namespace: "default"}, snapshot)
+	Expect(err).ToNot(HaveOccurred())
+	return snapshot
+}
+
+func (t *cryostatBackupTestInput) checkBackupJob(expected *batchv1.Job) {
+	job := checkStorageJob(t.Client, expected)
+	Expect(metav1.IsControlledBy(job, t.getCryostatBackup())).To(BeTrue())
+}
+
+func (t *cryostatBackupTestInput) checkBackupCondition(status metav1.ConditionStatus, reason string) {
+	backup := t.getCryostatBackup()
+	checkStorageOperationCondition(backup.Status.Conditions, status, reason)
+}
+
+func (t *cryostatBackupTestInput) expectNoJob(name string) {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, job)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func checkStorageJob(c ctrlclient.Client, expected *batchv1.Job) *batchv1.Job {
+	job := &batchv1.Job{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(job.Labels).To(Equal(expected.Labels))
+	Expect(job.Spec.BackoffLimit).To(Equal(expected.Spec.BackoffLimit))
+	Expect(job.Spec.Template.Labels).To(Equal(expected.Spec.Template.Labels))
+	Expect(job.Spec.Template.Spec).To(Equal(expected.Spec.Template.Spec))
+	return job
+}
+
+func checkStorageOperationCondition(conditions []metav1.Condition, status metav1.ConditionStatus, reason string) {
+	condition := meta.FindStatusCondition(conditions, string(operatorv1beta1.ConditionTypeStorageOperationComplete))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func setJobCondition(c ctrlclient.Client, name string, condType batchv1.JobConditionType, reason string) {
+	job := &batchv1.Job{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, job)
+	Expect(err).ToNot(HaveOccurred())
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:   condType,
+		Status: corev1.ConditionTrue,
+		Reason: reason,
+	})
+	err = c.Status().Update(context.Background(), job)
+	Expect(err).ToNot(HaveOccurred())
+}
@@ -0,0 +1,302 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+)
+
+// CryostatRestoreReconciler reconciles a CryostatRestore object
+type CryostatRestoreReconciler struct {
+	client.Client
+	Log         logr.Logger
+	Scheme      *runtime.Scheme
+	IsOpenShift bool
+	common.ReconcilerTLS
+}
+
+// Reasons for CryostatRestore Conditions
+const (
+	reasonRestoreBackupNotFound    = "BackupNotFound"
+	reasonRestoreWaitingForBackup  = "WaitingForBackup"
+	reasonRestoreBackupFailed      = "BackupFailed"
+	reasonRestoreAlreadyDeployed   = "CryostatAlreadyDeployed"
+	reasonRestoreWaitingForStorage = "WaitingForStorage"
+	reasonRestoreSnapshotNotFound  = "VolumeSnapshotNotFound"
+)
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostatrestores,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostatrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=cryostatrestores/finalizers,verbs=update
+
+// Reconcile restores a CryostatBackup into the persistent volume of a Cryostat that
+// has not been deployed yet, and records the result in the CryostatRestore's status
+func (r *CryostatRestoreReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling CryostatRestore")
+
+	// Fetch the CryostatRestore instance
+	restore := &operatorv1beta1.CryostatRestore{}
+	err := r.Client.Get(ctx, request.NamespacedName, restore)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// A restore is only performed once
+	if isStorageOperationFinished(restore.Status.Phase) {
+		return reconcile.Result{}, nil
+	}
+
+	backup := &operatorv1beta1.CryostatBackup{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupName, Namespace: restore.Namespace}, backup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.waitForPrerequisite(ctx, restore, reasonRestoreBackupNotFound,
+				fmt.Sprintf("CryostatBackup %s does not exist", restore.Spec.BackupName))
+		}
+		return reconcile.Result{}, err
+	}
+	switch backup.Status.Phase {
+	case operatorv1beta1.StorageOperationSucceeded:
+	case operatorv1beta1.StorageOperationFailed:
+		return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationFailed,
+			reasonRestoreBackupFailed, fmt.Sprintf("CryostatBackup %s failed", backup.Name))
+	default:
+		return r.waitForPrerequisite(ctx, restore, reasonRestoreWaitingForBackup,
+			fmt.Sprintf("Waiting for CryostatBackup %s to complete", backup.Name))
+	}
+
+	paths := restore.Spec.Paths
+	if len(paths) == 0 {
+		paths = backup.Status.Paths
+	}
+	for _, path := range paths {
+		if !containsStoragePath(backup.Status.Paths, path) {
+			return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationFailed,
+				reasonStorageInvalidSpec, fmt.Sprintf("CryostatBackup %s does not contain %s", backup.Name, path))
+		}
+	}
+
+	cr, err := getStorageCryostat(ctx, r.Client, restore.Namespace, restore.Spec.CryostatName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.waitForPrerequisite(ctx, restore, reasonStorageCryostatNotFound,
+				fmt.Sprintf("Cryostat %s does not exist", restore.Spec.CryostatName))
+		}
+		return reconcile.Result{}, err
+	}
+	if !hasPersistentStorage(cr) {
+		return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationFailed,
+			reasonStorageNotPersistent, fmt.Sprintf("Cryostat %s does not use a persistent volume claim", cr.Name))
+	}
+
+	if restore.Status.StartTime == nil {
+		// Only restore into a Cryostat whose deployment has not yet been created,
+		// so that its data is not overwritten while in use
+		deploy := &appsv1.Deployment{}
+		err = r.Client.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, deploy)
+		if err == nil {
+			return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationFailed,
+				reasonRestoreAlreadyDeployed, fmt.Sprintf("Cryostat %s has already been deployed", cr.Name))
+		} else if !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The Cryostat controller creates the persistent volume claim before holding back the deployment
+	storage := &corev1.PersistentVolumeClaim{}
+	claimName := resources.GetStorageClaimName(cr)
+	err = r.Client.Get(ctx, types.NamespacedName{Name: claimName, Namespace: cr.Namespace}, storage)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.waitForPrerequisite(ctx, restore, reasonRestoreWaitingForStorage,
+				fmt.Sprintf("Waiting for persistent volume claim %s", claimName))
+		}
+		return reconcile.Result{}, err
+	}
+
+	if restore.Status.StartTime == nil {
+		now := metav1.Now()
+		restore.Status.StartTime = &now
+	}
+
+	var source *resources.RestoreSource
+	if backup.Status.Archive != nil {
+		source = &resources.RestoreSource{
+			ClaimName:   backup.Status.Archive.ClaimName,
+			ArchivePath: backup.Status.Archive.Path,
+		}
+	} else {
+		source, err = r.createSnapshotSource(ctx, restore, backup, storage)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationFailed,
+					reasonRestoreSnapshotNotFound, fmt.Sprintf("VolumeSnapshot %s does not exist",
+						backup.Status.VolumeSnapshotName))
+			}
+			return reconcile.Result{}, err
+		}
+	}
+
+	fsGroup, err := getFSGroup(ctx, r.Client, r.IsOpenShift, cr.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	image := getEnvOrDefault(r, coreImageTagEnv, resources.DefaultCoreImageTag)
+	job := resources.NewJobForRestore(restore, cr, source, paths, image, *fsGroup)
+	if err := getOrCreateStorageJob(ctx, r.Client, r.Scheme, r.Log, restore, job); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	succeeded, failure := getJobResult(job)
+	if succeeded {
+		reqLogger.Info("Restore complete", "cryostat", cr.Name, "backup", backup.Name)
+		return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationSucceeded,
+			reasonStorageSucceeded, fmt.Sprintf("CryostatBackup %s restored into Cryostat %s", backup.Name, cr.Name))
+	}
+	if len(failure) > 0 {
+		return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationFailed,
+			reasonStorageJobFailed, failure)
+	}
+	// Job status changes trigger another reconcile
+	return reconcile.Result{}, r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationRunning,
+		reasonStorageInProgress, fmt.Sprintf("Job %s is running", job.Name))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CryostatRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.CryostatRestore{}).
+		Owns(&batchv1.Job{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Complete(r)
+}
+
+// createSnapshotSource provisions a persistent volume claim from the VolumeSnapshot
+// of a backup, so that its contents can be copied into Cryostat's volume
+func (r *CryostatRestoreReconciler) createSnapshotSource(ctx context.Context, restore *operatorv1beta1.CryostatRestore,
+	backup *operatorv1beta1.CryostatBackup, storage *corev1.PersistentVolumeClaim) (*resources.RestoreSource, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err := r.Client.Get(ctx, types.NamespacedName{Name: backup.Status.VolumeSnapshotName, Namespace: backup.Namespace},
+		snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new volume must be at least as large as the snapshot
+	size := storage.Spec.Resources.Requests[corev1.ResourceStorage]
+	restoreSize, found, err := unstructured.NestedString(snapshot.Object, "status", "restoreSize")
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		quantity, err := resource.ParseQuantity(restoreSize)
+		if err != nil {
+			return nil, err
+		}
+		if quantity.Cmp(size) > 0 {
+			size = quantity
+		}
+	}
+
+	pvc := resources.NewPersistentVolumeClaimForRestore(restore, storage, snapshot.GetName(), size)
+	err = r.Client.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, pvc)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := controllerutil.SetControllerReference(restore, pvc, r.Scheme); err != nil {
+			return nil, err
+		}
+		if err := r.Client.Create(ctx, pvc); err != nil {
+			return nil, err
+		}
+		r.Log.Info("PersistentVolumeClaim created", "namespace", pvc.Namespace, "name", pvc.Name)
+	}
+	return &resources.RestoreSource{ClaimName: pvc.Name}, nil
+}
+
+func (r *CryostatRestoreReconciler) waitForPrerequisite(ctx context.Context, restore *operatorv1beta1.CryostatRestore,
+	reason string, message string) (reconcile.Result, error) {
+	r.Log.Info(message, "namespace", restore.Namespace, "name", restore.Name)
+	err := r.updateRestoreStatus(ctx, restore, operatorv1beta1.StorageOperationPending, reason, message)
+	return reconcile.Result{RequeueAfter: storageOperationRequeueDelay}, err
+}
+
+func (r *CryostatRestoreReconciler) updateRestoreStatus(ctx context.Context, restore *operatorv1beta1.CryostatRestore,
+	phase operatorv1beta1.StorageOperationPhase, reason string, message string) error {
+	setStorageOperationStatus(&restore.Status.Phase, &restore.Status.CompletionTime, &restore.Status.Conditions,
+		phase, reason, message)
+	return r.Client.Status().Update(ctx, restore)
+}
+
+func containsStoragePath(paths []operatorv1beta1.StoragePath, path operatorv1beta1.StoragePath) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type cryostatRestoreTestInput struct {
+	controller *controllers.CryostatRestoreReconciler
+	objs       []runtime.Object
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("CryostatRestoreController", func() {
+	var t *cryostatRestoreTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.controller = &controllers.CryostatRestoreReconciler{
+			Client:        t.Client,
+			Scheme:        s,
+			Log:           logger,
+			ReconcilerTLS: test.NewTestReconcilerTLS(&t.TestReconcilerConfig),
+		}
+	})
+
+	BeforeEach(func() {
+		coreImg := "my/core-image:1.0"
+		t = &cryostatRestoreTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupSucceeded(), test.NewCryostatRestore(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				EnvCoreImageTag: &coreImg,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new CryostatRestore", func() {
+			It("should create a Job", func() {
+				t.expectRestoreReconcileSuccess()
+				job := checkStorageJob(t.Client, test.NewRestoreJob())
+				Expect(metav1.IsControlledBy(job, t.getCryostatRestore())).To(BeTrue())
+			})
+			It("should be running", func() {
+				t.expectRestoreReconcileSuccess()
+				restore := t.getCryostatRestore()
+				Expect(restore.Status.Phase).To(Equal(operatorv1beta1.StorageOperationRunning))
+				Expect(restore.Status.StartTime).ToNot(BeNil())
+				Expect(restore.Status.CompletionTime).To(BeNil())
+				t.checkRestoreCondition(metav1.ConditionFalse, "InProgress")
+			})
+			Context("when the Job completes", func() {
+				JustBeforeEach(func() {
+					t.expectRestoreReconcileSuccess()
+					setJobCondition(t.Client, "test-restore-restore", batchv1.JobComplete, "")
+					t.expectRestoreReconcileSuccess()
+				})
+				It("should succeed", func() {
+					restore := t.getCryostatRestore()
+					Expect(restore.Status.Phase).To(Equal(operatorv1beta1.StorageOperationSucceeded))
+					Expect(restore.Status.CompletionTime).ToNot(BeNil())
+					t.checkRestoreCondition(metav1.ConditionTrue, "Succeeded")
+				})
+			})
+			Context("when the Job fails", func() {
+				JustBeforeEach(func() {
+					t.expectRestoreReconcileSuccess()
+					setJobCondition(t.Client, "test-restore-restore", batchv1.JobFailed, "BackoffLimitExceeded")
+					t.expectRestoreReconcileSuccess()
+				})
+				It("should fail", func() {
+					restore := t.getCryostatRestore()
+					Expect(restore.Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+					Expect(restore.Status.CompletionTime).ToNot(BeNil())
+					t.checkRestoreCondition(metav1.ConditionFalse, "JobFailed")
+				})
+			})
+		})
+		Context("with selected paths", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupSucceeded(),
+					test.NewCryostatRestoreWithPaths(operatorv1beta1.StoragePathTemplates),
+				}
+			})
+			It("should only extract those paths", func() {
+				t.expectRestoreReconcileSuccess()
+				expected := test.NewRestoreJob()
+				expected.Spec.Template.Spec.Containers[0].Args = test.NewRestoreJobArgs("templates")
+				checkStorageJob(t.Client, expected)
+			})
+		})
+		Context("with paths missing from the backup", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupSucceeded(),
+					test.NewCryostatRestoreWithPaths(operatorv1beta1.StoragePathConfig),
+				}
+			})
+			It("should fail", func() {
+				t.expectRestoreReconcileSuccess()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+				t.checkRestoreCondition(metav1.ConditionFalse, "InvalidSpec")
+			})
+		})
+		Context("with a VolumeSnapshot backup", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupSnapshotSucceeded(),
+					test.NewVolumeSnapshot(true), test.NewCryostatRestore(),
+				}
+			})
+			It("should provision a volume from the snapshot", func() {
+				t.expectRestoreReconcileSuccess()
+				expected := test.NewRestoreSourcePVC()
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name,
+					Namespace: expected.Namespace}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pvc.Labels).To(Equal(expected.Labels))
+				Expect(pvc.Spec).To(Equal(expected.Spec))
+				Expect(metav1.IsControlledBy(pvc, t.getCryostatRestore())).To(BeTrue())
+			})
+			It("should copy from the provisioned volume", func() {
+				t.expectRestoreReconcileSuccess()
+				checkStorageJob(t.Client, test.NewSnapshotRestoreJob())
+			})
+		})
+		Context("with a missing VolumeSnapshot", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupSnapshotSucceeded(),
+					test.NewCryostatRestore(),
+				}
+			})
+			It("should fail", func() {
+				t.expectRestoreReconcileSuccess()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+				t.checkRestoreCondition(metav1.ConditionFalse, "VolumeSnapshotNotFound")
+			})
+		})
+		Context("when the backup does not exist", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatRestore(),
+				}
+			})
+			It("should wait for the backup", func() {
+				t.expectRestoreRequeue()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationPending))
+				t.checkRestoreCondition(metav1.ConditionFalse, "BackupNotFound")
+			})
+		})
+		Context("when the backup is in progress", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackup(), test.NewCryostatRestore(),
+				}
+			})
+			It("should wait for the backup", func() {
+				t.expectRestoreRequeue()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationPending))
+				t.checkRestoreCondition(metav1.ConditionFalse, "WaitingForBackup")
+			})
+		})
+		Context("when the backup failed", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewDefaultPVC(), test.NewCryostatBackupFailed(), test.NewCryostatRestore(),
+				}
+			})
+			It("should fail", func() {
+				t.expectRestoreReconcileSuccess()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+				t.checkRestoreCondition(metav1.ConditionFalse, "BackupFailed")
+			})
+		})
+		Context("when the Cryostat's volume does not exist yet", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCryostatBackupSucceeded(), test.NewCryostatRestore(),
+				}
+			})
+			It("should wait for the volume", func() {
+				t.expectRestoreRequeue()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationPending))
+				t.checkRestoreCondition(metav1.ConditionFalse, "WaitingForStorage")
+			})
+		})
+		Context("when the Cryostat has already been deployed", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cryostat",
+						Namespace: "default",
+					},
+				})
+			})
+			It("should fail without creating a Job", func() {
+				t.expectRestoreReconcileSuccess()
+				Expect(t.getCryostatRestore().Status.Phase).To(Equal(operatorv1beta1.StorageOperationFailed))
+				t.checkRestoreCondition(metav1.ConditionFalse, "CryostatAlreadyDeployed")
+				job := &batchv1.Job{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-restore-restore",
+					Namespace: "default"}, job)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+		Context("CryostatRestore does not exist", func() {
+			It("should do nothing", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+	})
+})
+
+func (t *cryostatRestoreTestInput) reconcileRestore() (reconcile.Result, error) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-restore", Namespace: "default"}}
+	return t.controller.Reconcile(context.Background(), req)
+}
+
+func (t *cryostatRestoreTestInput) expectRestoreReconcileSuccess() {
+	result, err := t.reconcileRestore()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *cryostatRestoreTestInput) expectRestoreRequeue() {
+	result, err := t.reconcileRestore()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result.RequeueAfter).ToNot(BeZero())
+}
+
+func (t *cryostatRestoreTestInput) getCryostatRestore() *operatorv1beta1.CryostatRestore {
+	restore := &operatorv1beta1.CryostatRestore{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-restore", Namespace: "default"}, restore)
+	Expect(err).ToNot(HaveOccurred())
+	return restore
+}
+
+func (t *cryostatRestoreTestInput) checkRestoreCondition(status metav1.ConditionStatus, reason string) {
+	restore := t.getCryostatRestore()
+	checkStorageOperationCondition(restore.Status.Conditions, status, reason)
+}
@@ -0,0 +1,277 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// CustomTargetReconciler reconciles a CustomTarget object
+type CustomTargetReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	common.Reconciler
+}
+
+const customTargetFinalizer = "operator.cryostat.io/customtarget.finalizer"
+
+const (
+	reasonTargetRegistered    = "TargetRegistered"
+	reasonTargetInvalidSpec   = "InvalidSpec"
+	reasonTargetRegisterError = "RegistrationFailed"
+)
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=customtargets;flightrecorders,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=customtargets/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=customtargets/finalizers,verbs=update
+
+// Reconcile registers a CustomTarget with Cryostat and creates a FlightRecorder for it
+func (r *CustomTargetReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling CustomTarget")
+
+	// Fetch the CustomTarget instance
+	target := &operatorv1beta1.CustomTarget{}
+	err := r.Client.Get(ctx, request.NamespacedName, target)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Check if this CustomTarget is being deleted
+	if target.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(target, customTargetFinalizer) {
+			err = r.deleteTarget(ctx, target)
+			if err != nil {
+				reqLogger.Error(err, "failed to remove custom target from Cryostat", "connectUrl",
+					target.Status.ConnectURL)
+				return common.RequeueIfNotReady(r.Log, err)
+			}
+			err = common.RemoveFinalizer(ctx, r.Client, target, customTargetFinalizer)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		// Ready for deletion
+		return reconcile.Result{}, nil
+	}
+
+	// Add our finalizer, so we can clean up Cryostat's registration when this CustomTarget is deleted
+	if !controllerutil.ContainsFinalizer(target, customTargetFinalizer) {
+		err = common.AddFinalizer(ctx, r.Client, target, customTargetFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Obtain a client configured to communicate with Cryostat
+	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, nil)
+	if err != nil {
+		return common.RequeueIfNotReady(r.Log, err)
+	}
+
+	targetAddr, err := r.GetCustomTarget(target)
+	if err != nil {
+		// Not recoverable without a change to the spec, so don't requeue
+		reqLogger.Error(err, "invalid CustomTarget")
+		return reconcile.Result{}, r.setRegisteredCondition(ctx, target, metav1.ConditionFalse,
+			reasonTargetInvalidSpec, err.Error())
+	}
+
+	alias := target.Spec.Alias
+	if len(alias) == 0 {
+		alias = target.Name
+	}
+
+	// Register the target with Cryostat, replacing any previous registration if the spec changed.
+	// The target is registered with the same URL used to look up its details.
+	connectURL := targetAddr.ServiceURL()
+	if target.Status.ConnectURL != connectURL || target.Status.Alias != alias {
+		err = r.unregisterTarget(cryostat, target)
+		if err != nil {
+			reqLogger.Error(err, "failed to remove previous custom target from Cryostat", "connectUrl",
+				target.Status.ConnectURL)
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("registering custom target with Cryostat", "connectUrl", connectURL, "alias", alias)
+		err = cryostat.CreateCustomTarget(targetAddr, alias)
+		if err != nil {
+			reqLogger.Error(err, "failed to register custom target with Cryostat", "connectUrl", connectURL)
+			condErr := r.setRegisteredCondition(ctx, target, metav1.ConditionFalse, reasonTargetRegisterError,
+				err.Error())
+			if condErr != nil {
+				return reconcile.Result{}, condErr
+			}
+			return reconcile.Result{}, err
+		}
+		// Record the registration right away, so it can be removed later even if a following step fails
+		target.Status.ConnectURL = connectURL
+		target.Status.Alias = alias
+		err = r.Client.Status().Update(ctx, target)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Create or update a FlightRecorder for this target
+	err = r.createOrUpdateFlightRecorder(ctx, target)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.setRegisteredCondition(ctx, target, metav1.ConditionTrue, reasonTargetRegistered,
+		"Target is registered with Cryostat")
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("CustomTarget successfully updated", "Namespace", request.Namespace, "Name", request.Name)
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CustomTargetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.CustomTarget{}).
+		Owns(&operatorv1beta1.FlightRecorder{}).
+		Complete(r)
+}
+
+func (r *CustomTargetReconciler) deleteTarget(ctx context.Context, target *operatorv1beta1.CustomTarget) error {
+	if len(target.Status.ConnectURL) == 0 {
+		// Never registered
+		return nil
+	}
+	// Nothing to remove if Cryostat has been deleted
+	cryostats := &operatorv1beta1.CryostatList{}
+	err := r.Client.List(ctx, cryostats, client.InNamespace(target.Namespace))
+	if err != nil {
+		return err
+	}
+	if len(cryostats.Items) == 0 {
+		return nil
+	}
+	cryostat, err := r.GetCryostatClient(ctx, target.Namespace, nil)
+	if err != nil {
+		return err
+	}
+	return r.unregisterTarget(cryostat, target)
+}
+
+func (r *CustomTargetReconciler) unregisterTarget(cryostat cryostatClient.CryostatClient,
+	target *operatorv1beta1.CustomTarget) error {
+	if len(target.Status.ConnectURL) == 0 {
+		// Never registered
+		return nil
+	}
+	return cryostat.DeleteCustomTarget(&cryostatClient.TargetAddress{ConnectURL: target.Status.ConnectURL})
+}
+
+func (r *CustomTargetReconciler) createOrUpdateFlightRecorder(ctx context.Context,
+	target *operatorv1beta1.CustomTarget) error {
+	fr := &operatorv1beta1.FlightRecorder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: target.Namespace,
+		},
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, fr, func() error {
+		if fr.Labels == nil {
+			fr.Labels = map[string]string{}
+		}
+		fr.Labels["app"] = target.Name
+		fr.Spec.RecordingSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				operatorv1beta1.RecordingLabel: target.Name,
+			},
+		}
+		fr.Spec.JMXCredentials = target.Spec.JMXCredentials
+		return controllerutil.SetControllerReference(target, fr, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info("FlightRecorder "+string(op), "namespace", fr.Namespace, "name", fr.Name)
+
+	// Point the FlightRecorder at this CustomTarget
+	var port int32
+	if target.Spec.Port != nil {
+		port = *target.Spec.Port
+	}
+	fr.Status.Target = &corev1.ObjectReference{
+		APIVersion: operatorv1beta1.GroupVersion.String(),
+		Kind:       common.CustomTargetKind,
+		Name:       target.Name,
+		Namespace:  target.Namespace,
+		UID:        target.UID,
+	}
+	fr.Status.Port = port
+	return r.Client.Status().Update(ctx, fr)
+}
+
+func (r *CustomTargetReconciler) setRegisteredCondition(ctx context.Context, target *operatorv1beta1.CustomTarget,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&target.Status.Conditions, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeTargetRegistered),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	return r.Client.Status().Update(ctx, target)
+}
@@ -0,0 +1,352 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type customTargetTestInput struct {
+	controller *controllers.CustomTargetReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("CustomTargetController", func() {
+	var t *customTargetTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.CustomTargetReconciler{
+			Client:     t.Client,
+			Scheme:     s,
+			Log:        logger,
+			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		t = &customTargetTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewCustomTarget(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new CustomTarget", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "my-jvm"),
+				}
+			})
+			It("should register the target", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkCustomTargetStatus(test.CustomTargetConnectURL, "my-jvm")
+			})
+			It("should add finalizer", func() {
+				t.expectCustomTargetReconcileSuccess()
+				target := t.getCustomTarget()
+				Expect(target.Finalizers).To(ContainElement("operator.cryostat.io/customtarget.finalizer"))
+			})
+			It("should create a FlightRecorder", func() {
+				t.expectCustomTargetReconcileSuccess()
+				fr := t.getFlightRecorder()
+				Expect(fr.Spec.RecordingSelector.MatchLabels).To(Equal(map[string]string{
+					operatorv1beta1.RecordingLabel: "test-custom-target",
+				}))
+				Expect(fr.Status.Target).ToNot(BeNil())
+				Expect(fr.Status.Target.Kind).To(Equal("CustomTarget"))
+				Expect(fr.Status.Target.Name).To(Equal("test-custom-target"))
+				Expect(fr.Status.Port).To(Equal(int32(8001)))
+				Expect(metav1.IsControlledBy(fr, t.getCustomTarget())).To(BeTrue())
+			})
+			It("should set TargetRegistered condition", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkRegisteredCondition(metav1.ConditionTrue, "TargetRegistered")
+			})
+		})
+		Context("with a connect URL", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewCustomTargetConnectURL(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "my-jvm"),
+				}
+			})
+			It("should register the target", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkCustomTargetStatus(test.CustomTargetConnectURL, "my-jvm")
+			})
+		})
+		Context("without an alias", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewCustomTargetNoAlias(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "test-custom-target"),
+				}
+			})
+			It("should use the CustomTarget name", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkCustomTargetStatus(test.CustomTargetConnectURL, "test-custom-target")
+			})
+		})
+		Context("after CustomTarget already reconciled successfully", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "my-jvm"),
+				}
+			})
+			It("should not register the target again", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.expectCustomTargetReconcileSuccess()
+				t.checkCustomTargetStatus(test.CustomTargetConnectURL, "my-jvm")
+			})
+		})
+		Context("with a changed spec", func() {
+			BeforeEach(func() {
+				target := test.NewCustomTargetRegistered()
+				target.Spec.Alias = "my-other-jvm"
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), target,
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewDeleteCustomTargetHandler(test.CustomTargetConnectURL),
+					test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "my-other-jvm"),
+				}
+			})
+			It("should replace the registration", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkCustomTargetStatus(test.CustomTargetConnectURL, "my-other-jvm")
+			})
+		})
+		Context("registered by host and port", func() {
+			BeforeEach(func() {
+				target := test.NewCustomTargetRegistered()
+				target.Status.ConnectURL = "1.2.3.4:8001"
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), target,
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewDeleteCustomTargetHandler("1.2.3.4:8001"),
+					test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "my-jvm"),
+				}
+			})
+			It("should register the target by its service URL", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkCustomTargetStatus(test.CustomTargetConnectURL, "my-jvm")
+			})
+		})
+		Context("with an invalid spec", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewCustomTargetInvalid(),
+				}
+			})
+			It("should not requeue", func() {
+				t.expectCustomTargetReconcileSuccess()
+			})
+			It("should set TargetRegistered condition", func() {
+				t.expectCustomTargetReconcileSuccess()
+				t.checkRegisteredCondition(metav1.ConditionFalse, "InvalidSpec")
+			})
+		})
+		Context("registration fails", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewCreateCustomTargetFailHandler(test.CustomTargetConnectURL, "my-jvm"),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectCustomTargetReconcileError()
+			})
+			It("should set TargetRegistered condition", func() {
+				t.expectCustomTargetReconcileError()
+				t.checkRegisteredCondition(metav1.ConditionFalse, "RegistrationFailed")
+			})
+		})
+		Context("CustomTarget does not exist", func() {
+			It("should do nothing", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("Cryostat CR is missing", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCACert(), test.NewCryostatService(), test.NewCustomTarget(),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectCustomTargetReconcileError()
+			})
+		})
+		Context("deleting a CustomTarget", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewDeletedCustomTarget(),
+				}
+			})
+			Context("that is registered", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteCustomTargetHandler(test.CustomTargetConnectURL),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectCustomTargetReconcileSuccess()
+					t.expectNoFinalizer()
+				})
+			})
+			Context("that Cryostat no longer knows about", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteCustomTargetNotFoundHandler(test.CustomTargetConnectURL),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectCustomTargetReconcileSuccess()
+					t.expectNoFinalizer()
+				})
+			})
+			Context("after Cryostat is deleted", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCACert(), test.NewCryostatService(), test.NewDeletedCustomTarget(),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectCustomTargetReconcileSuccess()
+					t.expectNoFinalizer()
+				})
+			})
+		})
+	})
+})
+
+func (t *customTargetTestInput) expectCustomTargetReconcileSuccess() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-custom-target", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *customTargetTestInput) expectCustomTargetReconcileError() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-custom-target", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).To(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *customTargetTestInput) getCustomTarget() *operatorv1beta1.CustomTarget {
+	target := &operatorv1beta1.CustomTarget{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-custom-target", Namespace: "default"}, target)
+	Expect(err).ToNot(HaveOccurred())
+	return target
+}
+
+func (t *customTargetTestInput) getFlightRecorder() *operatorv1beta1.FlightRecorder {
+	fr := &operatorv1beta1.FlightRecorder{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-custom-target", Namespace: "default"}, fr)
+	Expect(err).ToNot(HaveOccurred())
+	return fr
+}
+
+func (t *customTargetTestInput) checkCustomTargetStatus(connectURL string, alias string) {
+	target := t.getCustomTarget()
+	Expect(target.Status.ConnectURL).To(Equal(connectURL))
+	Expect(target.Status.Alias).To(Equal(alias))
+}
+
+func (t *customTargetTestInput) checkRegisteredCondition(status metav1.ConditionStatus, reason string) {
+	target := t.getCustomTarget()
+	condition := meta.FindStatusCondition(target.Status.Conditions, string(operatorv1beta1.ConditionTypeTargetRegistered))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *customTargetTestInput) expectNoFinalizer() {
+	target := &operatorv1beta1.CustomTarget{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-custom-target", Namespace: "default"}, target)
+	if err != nil {
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		return
+	}
+	Expect(target.Finalizers).ToNot(ContainElement("operator.cryostat.io/customtarget.finalizer"))
+}
@@ -38,6 +38,9 @@ package controllers
 
 import (
 	"context"
+	goerrors "errors"
+	"strconv"
+	"strings"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
@@ -46,10 +49,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -63,6 +69,7 @@ type EndpointsReconciler struct {
 }
 
 // +kubebuilder:rbac:namespace=system,groups="",resources=endpoints;services;pods;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=flightrecorders,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=flightrecorders/status,verbs=get;update;patch
 
@@ -85,15 +92,47 @@ func (r *EndpointsReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 		return reconcile.Result{}, err
 	}
 
+	// Look up the Cryostat CR in this namespace
+	cryostat, err := r.FindCryostat(ctx, ep.Namespace)
+	if err != nil {
+		if goerrors.Is(err, common.ErrCryostatNotFound) {
+			// Endpoints are reconciled again once a Cryostat is created
+			reqLogger.Info("No Cryostat found, skipping target discovery")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Get service corresponding to this Endpoints
+	svc := &corev1.Service{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: ep.Name, Namespace: ep.Namespace}, svc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Endpoints not backed by a Service, nothing to discover
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Check whether this service has been filtered out of target discovery
+	discoverable, err := r.isDiscoverable(ctx, cryostat, svc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !discoverable {
+		reqLogger.Info("Service excluded from target discovery", "Namespace", svc.Namespace, "Name", svc.Name)
+		return reconcile.Result{}, nil
+	}
+
 	for _, subset := range ep.Subsets {
 		// Check if this subset appears to be compatible with Cryostat
-		jmxPort := getServiceJMXPort(subset)
+		port, protocol := getServiceTargetPort(subset, svc, cryostat.Spec.TargetDiscoveryOptions)
 
-		if jmxPort != nil {
+		if port != nil {
 			for _, address := range subset.Addresses {
 				target := address.TargetRef
 				if target != nil && target.Kind == "Pod" {
-					err := r.handlePodAddress(ctx, target, ep, jmxPort, reqLogger)
+					err := r.handlePodAddress(ctx, target, cryostat, svc, port, protocol, reqLogger)
 					if err != nil {
 						return reconcile.Result{}, err
 					}
@@ -107,7 +146,8 @@ func (r *EndpointsReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 }
 
 func (r *EndpointsReconciler) handlePodAddress(ctx context.Context, target *corev1.ObjectReference,
-	ep *corev1.Endpoints, jmxPort *int32, reqLogger logr.Logger) error {
+	cryostat *operatorv1beta1.Cryostat, svc *corev1.Service, port *int32, protocol operatorv1beta1.TargetProtocol,
+	reqLogger logr.Logger) error {
 	// Check if this FlightRecorder already exists
 	found := &operatorv1beta1.FlightRecorder{}
 	jfrName := target.Name
@@ -120,13 +160,13 @@ func (r *EndpointsReconciler) handlePodAddress(ctx context.Context, target *core
 
 		// If this Endpoints is for Cryostat itself, fill in the JMX authentication credentials
 		// that the operator generated
-		jmxAuth, err := r.getJMXCredentials(ctx, ep)
+		jmxAuth, err := r.getJMXCredentials(ctx, cryostat, svc)
 		if err != nil {
 			return err
 		}
 
 		reqLogger.Info("Creating a new FlightRecorder", "Namespace", target.Namespace, "Name", jfrName)
-		err = r.createNewFlightRecorder(ctx, target, jmxPort, jmxAuth)
+		err = r.createNewFlightRecorder(ctx, target, port, protocol, jmxAuth)
 		if err != nil {
 			return err
 		}
@@ -135,14 +175,103 @@ func (r *EndpointsReconciler) handlePodAddress(ctx context.Context, target *core
 }
 
 const defaultJmxPort int32 = 9091
-const jmxServicePortName = "jfr-jmx"
+const defaultJmxPortName = "jfr-jmx"
+const defaultAgentPortName = "cryostat-agent"
+
+func (r *EndpointsReconciler) isDiscoverable(ctx context.Context, cryostat *operatorv1beta1.Cryostat,
+	svc *corev1.Service) (bool, error) {
+	// Services may opt out of discovery explicitly
+	if svc.Annotations[operatorv1beta1.DiscoveryExcludeAnnotation] == "true" {
+		return false, nil
+	}
+
+	options := cryostat.Spec.TargetDiscoveryOptions
+	if options == nil {
+		return true, nil
+	}
+	if options.ServiceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(options.ServiceSelector)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Matches(labels.Set(svc.Labels)) {
+			return false, nil
+		}
+	}
+	if options.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(options.NamespaceSelector)
+		if err != nil {
+			return false, err
+		}
+		ns := &corev1.Namespace{}
+		err = r.Client.Get(ctx, types.NamespacedName{Name: svc.Namespace}, ns)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getServiceTargetPort returns the port and protocol that Cryostat should use to connect
+// to the JVMs in this subset. The Cryostat agent is preferred over remote JMX.
+func getServiceTargetPort(subset corev1.EndpointSubset, svc *corev1.Service,
+	options *operatorv1beta1.TargetDiscoveryOptions) (*int32, operatorv1beta1.TargetProtocol) {
+	agentPort := getServiceAgentPort(subset, svc, options)
+	if agentPort != nil {
+		protocol := operatorv1beta1.TargetProtocolHTTP
+		if strings.EqualFold(svc.Annotations[operatorv1beta1.AgentSchemeAnnotation], "https") {
+			protocol = operatorv1beta1.TargetProtocolHTTPS
+		}
+		return agentPort, protocol
+	}
+	return getServiceJMXPort(subset, options), operatorv1beta1.TargetProtocolJMX
+}
+
+func getServiceAgentPort(subset corev1.EndpointSubset, svc *corev1.Service,
+	options *operatorv1beta1.TargetDiscoveryOptions) *int32 {
+	// An annotation on the Service names the agent port explicitly, by name or number
+	if annotation, pres := svc.Annotations[operatorv1beta1.AgentPortAnnotation]; pres {
+		portNum, err := strconv.ParseInt(annotation, 10, 32)
+		for idx, port := range subset.Ports {
+			if port.Name == annotation || (err == nil && port.Port == int32(portNum)) {
+				return &subset.Ports[idx].Port
+			}
+		}
+		return nil
+	}
+
+	portNames := []string{defaultAgentPortName}
+	if options != nil && len(options.AgentPortNames) > 0 {
+		portNames = options.AgentPortNames
+	}
+	for idx, port := range subset.Ports {
+		if containsString(portNames, port.Name) {
+			return &subset.Ports[idx].Port
+		}
+	}
+	return nil
+}
+
+func getServiceJMXPort(subset corev1.EndpointSubset, options *operatorv1beta1.TargetDiscoveryOptions) *int32 {
+	portNames := []string{defaultJmxPortName}
+	portNumbers := []int32{defaultJmxPort}
+	if options != nil {
+		if len(options.JMXPortNames) > 0 {
+			portNames = options.JMXPortNames
+		}
+		if len(options.JMXPortNumbers) > 0 {
+			portNumbers = options.JMXPortNumbers
+		}
+	}
 
-func getServiceJMXPort(subset corev1.EndpointSubset) *int32 {
 	var portNum, fallbackPortNum *int32
 	for idx, port := range subset.Ports {
-		if port.Name == jmxServicePortName {
+		if containsString(portNames, port.Name) {
 			portNum = &subset.Ports[idx].Port
-		} else if port.Port == defaultJmxPort {
+		} else if fallbackPortNum == nil && containsInt32(portNumbers, port.Port) {
 			fallbackPortNum = &subset.Ports[idx].Port
 		}
 	}
@@ -152,8 +281,8 @@ func getServiceJMXPort(subset corev1.EndpointSubset) *int32 {
 	return portNum
 }
 
-func (r *EndpointsReconciler) createNewFlightRecorder(ctx context.Context, target *corev1.ObjectReference, jmxPort *int32,
-	jmxAuth *operatorv1beta1.JMXAuthSecret) error {
+func (r *EndpointsReconciler) createNewFlightRecorder(ctx context.Context, target *corev1.ObjectReference, port *int32,
+	protocol operatorv1beta1.TargetProtocol, jmxAuth *operatorv1beta1.JMXAuthSecret) error {
 	pod := &corev1.Pod{}
 	err := r.Client.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, pod)
 	if err != nil {
@@ -161,7 +290,7 @@ func (r *EndpointsReconciler) createNewFlightRecorder(ctx context.Context, targe
 	}
 
 	// Define a new FlightRecorder object for this Pod
-	jfr, err := r.newFlightRecorderForPod(target, pod, *jmxPort, jmxAuth)
+	jfr, err := r.newFlightRecorderForPod(target, pod, *port, protocol, jmxAuth)
 	if err != nil {
 		return err
 	}
@@ -190,7 +319,7 @@ func (r *EndpointsReconciler) createNewFlightRecorder(ctx context.Context, targe
 
 // newFlightRecorderForPod returns a FlightRecorder with the same name/namespace as the target
 func (r *EndpointsReconciler) newFlightRecorderForPod(target *corev1.ObjectReference, pod *corev1.Pod,
-	jmxPort int32, jmxAuth *operatorv1beta1.JMXAuthSecret) (*operatorv1beta1.FlightRecorder, error) {
+	port int32, protocol operatorv1beta1.TargetProtocol, jmxAuth *operatorv1beta1.JMXAuthSecret) (*operatorv1beta1.FlightRecorder, error) {
 	// Inherit "app" label from endpoints
 	appLabel := pod.Name // Use endpoints name as fallback
 	if label, pres := pod.Labels["app"]; pres {
@@ -218,25 +347,14 @@ func (r *EndpointsReconciler) newFlightRecorderForPod(target *corev1.ObjectRefer
 			Events:    []operatorv1beta1.EventInfo{},
 			Templates: []operatorv1beta1.TemplateInfo{},
 			Target:    target,
-			Port:      jmxPort,
+			Port:      port,
+			Protocol:  protocol,
 		},
 	}, nil
 }
 
-func (r *EndpointsReconciler) getJMXCredentials(ctx context.Context, ep *corev1.Endpoints) (*operatorv1beta1.JMXAuthSecret, error) {
-	// Look up the Cryostat CR in this namespace
-	cryostat, err := r.FindCryostat(ctx, ep.Namespace)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get service corresponding to this Endpoints
-	svc := &corev1.Service{}
-	err = r.Client.Get(ctx, types.NamespacedName{Name: ep.Name, Namespace: ep.Namespace}, svc)
-	if err != nil {
-		return nil, err
-	}
-
+func (r *EndpointsReconciler) getJMXCredentials(ctx context.Context, cryostat *operatorv1beta1.Cryostat,
+	svc *corev1.Service) (*operatorv1beta1.JMXAuthSecret, error) {
 	// Is the service owned by the Cryostat CR
 	var result *operatorv1beta1.JMXAuthSecret
 	if metav1.IsControlledBy(svc, cryostat) {
@@ -261,9 +379,49 @@ func (r *EndpointsReconciler) getJMXCredentials(ctx context.Context, ep *corev1.
 	return result, nil
 }
 
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt32(values []int32, value int32) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *EndpointsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Endpoints{}).
+		// Endpoints share their name with the Service, so re-evaluate
+		// discovery filters when a Service's labels or annotations change
+		Watches(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForObject{}).
+		// Discover targets once a Cryostat is created, and when its discovery options change
+		Watches(&source.Kind{Type: &operatorv1beta1.Cryostat{}}, handler.EnqueueRequestsFromMapFunc(r.cryostatToEndpoints)).
 		Complete(r)
 }
+
+func (r *EndpointsReconciler) cryostatToEndpoints(obj client.Object) []reconcile.Request {
+	endpoints := &corev1.EndpointsList{}
+	err := r.Client.List(context.Background(), endpoints, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "Failed to list Endpoints", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, ep := range endpoints.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: ep.Namespace, Name: ep.Name},
+		})
+	}
+	return requests
+}
@@ -143,6 +143,17 @@ var _ = Describe("EndpointsController", func() {
 				Expect(result).To(Equal(reconcile.Result{}))
 			})
 		})
+		Context("no Cryostat exists", func() {
+			BeforeEach(func() {
+				objs = []runtime.Object{
+					test.NewTestService(), test.NewTargetPod(), test.NewTestEndpoints(),
+				}
+			})
+			It("should not create a flightrecorder", func() {
+				reconcileEndpoints(controller)
+				expectNoFlightRecorder(client)
+			})
+		})
 		Context("endpoints has no targetRef", func() {
 			BeforeEach(func() {
 				objs = []runtime.Object{
@@ -208,10 +219,206 @@ var _ = Describe("EndpointsController", func() {
 				compareFlightRecorders(recorder, expected)
 			})
 		})
-
+		Context("service has exclude annotation", func() {
+			BeforeEach(func() {
+				objs = []runtime.Object{
+					test.NewCryostat(), test.NewTestServiceExcluded(),
+					test.NewTargetPod(), test.NewTestEndpoints(),
+				}
+			})
+			It("should not create flightrecorder", func() {
+				reconcileEndpoints(controller)
+				expectNoFlightRecorder(client)
+			})
+		})
+		Context("with service selector", func() {
+			Context("and service matches", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithServiceSelector(), test.NewTestServiceWithLabels(),
+						test.NewTargetPod(), test.NewTestEndpoints(),
+					}
+				})
+				It("should create flightrecorder", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 1234)
+				})
+			})
+			Context("and service does not match", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithServiceSelector(), test.NewTestService(),
+						test.NewTargetPod(), test.NewTestEndpoints(),
+					}
+				})
+				It("should not create flightrecorder", func() {
+					reconcileEndpoints(controller)
+					expectNoFlightRecorder(client)
+				})
+			})
+		})
+		Context("with namespace selector", func() {
+			Context("and namespace matches", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithNamespaceSelector(), test.NewNamespaceWithLabels(),
+						test.NewTestService(), test.NewTargetPod(), test.NewTestEndpoints(),
+					}
+				})
+				It("should create flightrecorder", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 1234)
+				})
+			})
+			Context("and namespace does not match", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithNamespaceSelector(), test.NewNamespace(),
+						test.NewTestService(), test.NewTargetPod(), test.NewTestEndpoints(),
+					}
+				})
+				It("should not create flightrecorder", func() {
+					reconcileEndpoints(controller)
+					expectNoFlightRecorder(client)
+				})
+			})
+		})
+		Context("with custom JMX ports", func() {
+			Context("and endpoints has custom port name", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithJMXPortOptions(), test.NewTestService(),
+						test.NewTargetPod(), test.NewTestEndpointsCustomJMXPortName(),
+					}
+				})
+				It("should create flightrecorder using named port", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 4321)
+				})
+			})
+			Context("and endpoints has custom port number", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithJMXPortOptions(), test.NewTestService(),
+						test.NewTargetPod(), test.NewTestEndpointsCustomJMXPortNumber(),
+					}
+				})
+				It("should create flightrecorder using port number", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 5678)
+				})
+			})
+			Context("and endpoints only has default port", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithJMXPortOptions(), test.NewTestService(),
+						test.NewTargetPod(), test.NewTestEndpointsNoJMXPort(),
+					}
+				})
+				It("should not create flightrecorder", func() {
+					reconcileEndpoints(controller)
+					expectNoFlightRecorder(client)
+				})
+			})
+		})
+		Context("with Cryostat agent ports", func() {
+			Context("and endpoints has default agent port name", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostat(), test.NewTestService(),
+						test.NewTargetPod(), test.NewTestEndpointsWithAgentPort(),
+					}
+				})
+				It("should create flightrecorder using agent port", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 9977)
+					expectFlightRecorderProtocol(client, operatorv1beta1.TargetProtocolHTTP)
+				})
+			})
+			Context("and Cryostat has custom agent port names", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostatWithAgentPortOptions(), test.NewTestService(),
+						test.NewTargetPod(), test.NewTestEndpointsWithAgentPort(),
+					}
+				})
+				It("should create flightrecorder using named port", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 8181)
+					expectFlightRecorderProtocol(client, operatorv1beta1.TargetProtocolHTTP)
+				})
+			})
+			Context("and service annotation names the port", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostat(), test.NewTestServiceWithAgentPort("custom-agent", ""),
+						test.NewTargetPod(), test.NewTestEndpointsWithAgentPort(),
+					}
+				})
+				It("should create flightrecorder using annotated port", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 8181)
+					expectFlightRecorderProtocol(client, operatorv1beta1.TargetProtocolHTTP)
+				})
+			})
+			Context("and service annotation gives the port number and HTTPS scheme", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostat(), test.NewTestServiceWithAgentPort("8181", "https"),
+						test.NewTargetPod(), test.NewTestEndpointsWithAgentPort(),
+					}
+				})
+				It("should create flightrecorder using annotated port", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 8181)
+					expectFlightRecorderProtocol(client, operatorv1beta1.TargetProtocolHTTPS)
+				})
+			})
+			Context("and service annotation matches no port", func() {
+				BeforeEach(func() {
+					objs = []runtime.Object{
+						test.NewCryostat(), test.NewTestServiceWithAgentPort("missing", ""),
+						test.NewTargetPod(), test.NewTestEndpoints(),
+					}
+				})
+				It("should fall back to JMX port", func() {
+					reconcileEndpoints(controller)
+					expectFlightRecorderPort(client, 1234)
+					expectFlightRecorderProtocol(client, operatorv1beta1.TargetProtocolJMX)
+				})
+			})
+		})
 	})
 })
 
+func reconcileEndpoints(controller *controllers.EndpointsReconciler) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-svc", Namespace: "default"}}
+	result, err := controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func expectFlightRecorderPort(c client.Client, port int32) {
+	recorder := &operatorv1beta1.FlightRecorder{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, recorder)
+	Expect(err).ToNot(HaveOccurred())
+	compareFlightRecorders(recorder, test.NewFlightRecorderNoJMXAuth())
+	Expect(recorder.Status.Port).To(Equal(port))
+}
+
+func expectFlightRecorderProtocol(c client.Client, protocol operatorv1beta1.TargetProtocol) {
+	recorder := &operatorv1beta1.FlightRecorder{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, recorder)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(recorder.Status.Protocol).To(Equal(protocol))
+}
+
+func expectNoFlightRecorder(c client.Client) {
+	recorder := &operatorv1beta1.FlightRecorder{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, recorder)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
 func compareFlightRecorders(found *operatorv1beta1.FlightRecorder, expected *operatorv1beta1.FlightRecorder) {
 	Expect(found.TypeMeta).To(Equal(expected.TypeMeta))
 	Expect(found.ObjectMeta.Name).To(Equal(expected.ObjectMeta.Name))
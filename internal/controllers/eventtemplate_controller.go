@@ -0,0 +1,311 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// EventTemplateReconciler reconciles an EventTemplate object
+type EventTemplateReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	common.Reconciler
+}
+
+const eventTemplateFinalizer = "operator.cryostat.io/eventtemplate.finalizer"
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=eventtemplates,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=eventtemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=eventtemplates/finalizers,verbs=update
+
+// Reconcile validates an EventTemplate and uploads it to Cryostat
+func (r *EventTemplateReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling EventTemplate")
+
+	// Fetch the EventTemplate instance
+	template := &operatorv1beta1.EventTemplate{}
+	err := r.Client.Get(ctx, request.NamespacedName, template)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	sync := r.templateSync()
+
+	// Check if this EventTemplate is being deleted
+	if template.GetDeletionTimestamp() != nil {
+		return sync.finalize(ctx, template, template.Status.TemplateName)
+	}
+
+	// Add our finalizer, so we can remove the template from Cryostat when this EventTemplate is deleted
+	err = sync.addFinalizer(ctx, template)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Read and validate the template
+	content, ok, err := sync.getContent(ctx, template, &template.Status.Conditions, template.Spec.Template,
+		template.Spec.ConfigMapRef)
+	if !ok {
+		return reconcile.Result{}, err
+	}
+	info, err := parseEventTemplate(content)
+	if err != nil {
+		reqLogger.Error(err, "invalid event template")
+		return reconcile.Result{}, sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+			metav1.ConditionFalse, reasonTemplateInvalid, err.Error())
+	}
+
+	// Each template in Cryostat must have a unique label
+	owner, err := r.findTemplateOwner(ctx, template, info.Label)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if owner != nil {
+		return reconcile.Result{}, sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+			metav1.ConditionFalse, reasonTemplateNameConflict,
+			fmt.Sprintf("EventTemplate %s already provides a template named %s", owner.Name, info.Label))
+	}
+
+	// Obtain a client configured to communicate with Cryostat
+	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, nil)
+	if err != nil {
+		return common.RequeueIfNotReady(r.Log, err)
+	}
+
+	// Check which custom templates Cryostat has, since it may have lost a template
+	// uploaded by a previous reconcile
+	custom, err := cryostat.ListTemplates(nil)
+	if err != nil {
+		reqLogger.Error(err, "failed to list event templates in Cryostat")
+		return reconcile.Result{}, err
+	}
+	existing := map[string]bool{}
+	for _, other := range custom {
+		if other.Type == operatorv1beta1.TemplateTypeCustom {
+			existing[other.Name] = true
+		}
+	}
+	uploaded := len(template.Status.TemplateName) > 0 && existing[template.Status.TemplateName]
+
+	// Upload the template if it is missing from Cryostat, or has changed since it was last uploaded
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+	if !uploaded || template.Status.ContentHash != hash || template.Status.TemplateName != info.Label ||
+		!meta.IsStatusConditionTrue(template.Status.Conditions, string(operatorv1beta1.ConditionTypeTemplateAvailable)) {
+		// Don't replace a template in Cryostat that was not uploaded by this EventTemplate
+		if template.Status.TemplateName != info.Label && existing[info.Label] {
+			return reconcile.Result{}, sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+				metav1.ConditionFalse, reasonTemplateNameConflict,
+				fmt.Sprintf("Cryostat already has a template named %s", info.Label))
+		}
+
+		// Templates cannot be replaced in place, so remove the previous version first
+		if uploaded {
+			err = cryostat.DeleteEventTemplate(template.Status.TemplateName)
+			if err != nil {
+				reqLogger.Error(err, "failed to remove previous event template from Cryostat",
+					"template", template.Status.TemplateName)
+				return reconcile.Result{}, err
+			}
+		}
+
+		// Record the template name before uploading, so that the template is known to
+		// belong to this EventTemplate even if the upload cannot be recorded afterwards
+		template.Status.TemplateName = info.Label
+		template.Status.ContentHash = ""
+		err = r.Client.Status().Update(ctx, template)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		reqLogger.Info("uploading event template to Cryostat", "template", info.Label)
+		err = cryostat.UploadEventTemplate(template.Name+".jfc", content)
+		if err != nil {
+			reqLogger.Error(err, "failed to upload event template to Cryostat", "template", info.Label)
+			condErr := sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+				metav1.ConditionFalse, reasonTemplateUploadFailed, err.Error())
+			if condErr != nil {
+				return reconcile.Result{}, condErr
+			}
+			return reconcile.Result{}, err
+		}
+		template.Status.ContentHash = hash
+	}
+	template.Status.Description = info.Description
+	template.Status.Provider = info.Provider
+
+	err = sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+		metav1.ConditionTrue, reasonTemplateUploaded,
+		fmt.Sprintf("Template %s is available in Cryostat", info.Label))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("EventTemplate successfully updated", "Namespace", request.Namespace, "Name", request.Name)
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EventTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.EventTemplate{}).
+		// Upload a new version of the template when the referenced ConfigMap changes
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.templateSync().configMapToTemplates)).
+		Complete(r)
+}
+
+func (r *EventTemplateReconciler) templateSync() *templateSync {
+	return &templateSync{
+		Client:        r.Client,
+		Reconciler:    r.Reconciler,
+		Log:           r.Log,
+		kind:          "EventTemplate",
+		finalizer:     eventTemplateFinalizer,
+		conditionType: string(operatorv1beta1.ConditionTypeTemplateAvailable),
+		newList: func() client.ObjectList {
+			return &operatorv1beta1.EventTemplateList{}
+		},
+		getConfigMapRef: func(obj client.Object) *operatorv1beta1.TemplateConfigMap {
+			return obj.(*operatorv1beta1.EventTemplate).Spec.ConfigMapRef
+		},
+		deleteFromCryostat: func(cryostat cryostatClient.CryostatClient, templateName string) error {
+			return cryostat.DeleteEventTemplate(templateName)
+		},
+	}
+}
+
+// findTemplateOwner returns another EventTemplate in the same namespace that has
+// already uploaded a template with the provided name, if any
+func (r *EventTemplateReconciler) findTemplateOwner(ctx context.Context, template *operatorv1beta1.EventTemplate,
+	templateName string) (*operatorv1beta1.EventTemplate, error) {
+	templates := &operatorv1beta1.EventTemplateList{}
+	err := r.Client.List(ctx, templates, client.InNamespace(template.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	for i, other := range templates.Items {
+		if other.Name != template.Name && other.Status.TemplateName == templateName {
+			return &templates.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// eventTemplateInfo describes the root configuration element of a .jfc file
+type eventTemplateInfo struct {
+	XMLName     xml.Name `xml:"configuration"`
+	Version     string   `xml:"version,attr"`
+	Label       string   `xml:"label,attr"`
+	Description string   `xml:"description,attr"`
+	Provider    string   `xml:"provider,attr"`
+	Events      []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"event"`
+}
+
+// parseEventTemplate checks that the provided content is a well-formed
+// event template that Cryostat will accept
+func parseEventTemplate(content []byte) (*eventTemplateInfo, error) {
+	info := &eventTemplateInfo{}
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	err := decoder.Decode(info)
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("template is empty")
+		}
+		return nil, fmt.Errorf("template is not a valid event template: %s", err.Error())
+	}
+	// Ensure nothing follows the configuration element
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("template is not well-formed XML: %s", err.Error())
+		}
+		switch token.(type) {
+		case xml.StartElement, xml.CharData:
+			if data, ok := token.(xml.CharData); ok && len(bytes.TrimSpace(data)) == 0 {
+				continue
+			}
+			return nil, errors.New("template must contain a single configuration element")
+		}
+	}
+	if info.Version != "2.0" {
+		return nil, fmt.Errorf("unsupported template version \"%s\", must be \"2.0\"", info.Version)
+	}
+	if len(info.Label) == 0 {
+		return nil, errors.New("template configuration element must have a label")
+	}
+	for _, event := range info.Events {
+		if len(event.Name) == 0 {
+			return nil, errors.New("template events must have a name")
+		}
+	}
+	return info, nil
+}
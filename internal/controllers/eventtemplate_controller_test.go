@@ -0,0 +1,433 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type eventTemplateTestInput struct {
+	controller *controllers.EventTemplateReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("EventTemplateController", func() {
+	var t *eventTemplateTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.EventTemplateReconciler{
+			Client:     t.Client,
+			Scheme:     s,
+			Log:        logger,
+			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		t = &eventTemplateTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewEventTemplate(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new EventTemplate", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler(),
+					test.NewUploadEventTemplateHandler("test-template.jfc", test.EventTemplateContent),
+				}
+			})
+			It("should upload the template", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkEventTemplateStatus("Custom", "A custom template", "Cryostat")
+			})
+			It("should add finalizer", func() {
+				t.expectEventTemplateReconcileSuccess()
+				template := t.getEventTemplate()
+				Expect(template.Finalizers).To(ContainElement("operator.cryostat.io/eventtemplate.finalizer"))
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("with a template in a ConfigMap", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					test.NewEventTemplateFromConfigMap(), test.NewEventTemplateConfigMap(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler(),
+					test.NewUploadEventTemplateHandler("test-template.jfc", test.EventTemplateContent),
+				}
+			})
+			It("should upload the template", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkEventTemplateStatus("Custom", "A custom template", "Cryostat")
+				t.checkAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("after EventTemplate already reconciled successfully", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler(),
+					test.NewUploadEventTemplateHandler("test-template.jfc", test.EventTemplateContent),
+					test.NewListCustomTemplatesHandler("Custom"),
+				}
+			})
+			It("should not upload the template again", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.expectEventTemplateReconcileSuccess()
+				t.checkEventTemplateStatus("Custom", "A custom template", "Cryostat")
+			})
+		})
+		Context("after Cryostat loses an uploaded template", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewEventTemplateUploaded(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler(),
+					test.NewUploadEventTemplateHandler("test-template.jfc", test.EventTemplateContent),
+				}
+			})
+			It("should upload the template again", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkEventTemplateStatus("Custom", "A custom template", "Cryostat")
+				t.checkAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("with changed template contents", func() {
+			var content string
+
+			BeforeEach(func() {
+				content = strings.Replace(test.EventTemplateContent, `label="Custom"`, `label="Custom2"`, 1)
+				template := test.NewEventTemplateUploaded()
+				template.Spec.Template = &content
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), template,
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler("Custom"),
+					test.NewDeleteEventTemplateHandler("Custom"),
+					test.NewUploadEventTemplateHandler("test-template.jfc", content),
+				}
+			})
+			It("should replace the template", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkEventTemplateStatus("Custom2", "A custom template", "Cryostat")
+				t.checkAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("with both an inline template and a ConfigMap", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					test.NewEventTemplateInvalidSpec(), test.NewEventTemplateConfigMap(),
+				}
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkAvailableCondition(metav1.ConditionFalse, "InvalidSpec")
+			})
+		})
+		Context("with a missing ConfigMap", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					test.NewEventTemplateFromConfigMap(),
+				}
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkAvailableCondition(metav1.ConditionFalse, "ConfigMapNotFound")
+			})
+		})
+		Context("with a missing ConfigMap key", func() {
+			BeforeEach(func() {
+				cm := test.NewEventTemplateConfigMap()
+				cm.Data = map[string]string{"other.jfc": test.EventTemplateContent}
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					test.NewEventTemplateFromConfigMap(), cm,
+				}
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkAvailableCondition(metav1.ConditionFalse, "ConfigMapNotFound")
+			})
+		})
+		Context("with an invalid template", func() {
+			var content string
+
+			JustBeforeEach(func() {
+				err := t.Client.Delete(context.Background(), test.NewEventTemplate())
+				Expect(err).ToNot(HaveOccurred())
+				err = t.Client.Create(context.Background(), test.NewEventTemplateWithContent(content))
+				Expect(err).ToNot(HaveOccurred())
+			})
+			Context("that is not well-formed", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.EventTemplateContent, "</configuration>", "", 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.checkAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("with an unsupported version", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.EventTemplateContent, `version="2.0"`, `version="1.0"`, 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.checkAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("without a label", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.EventTemplateContent, `label="Custom"`, "", 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.checkAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("with an unnamed event", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.EventTemplateContent, `name="jdk.ThreadSleep"`, "", 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.checkAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("with a different root element", func() {
+				BeforeEach(func() {
+					content = `<recording version="2.0" label="Custom"></recording>`
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.checkAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+		})
+		Context("with a template name used by another EventTemplate", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewOtherEventTemplateUploaded())
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkAvailableCondition(metav1.ConditionFalse, "TemplateNameConflict")
+				t.checkEventTemplateStatus("", "", "")
+			})
+		})
+		Context("with a template name used by a template in Cryostat", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler("Custom"),
+				}
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileSuccess()
+				t.checkAvailableCondition(metav1.ConditionFalse, "TemplateNameConflict")
+				t.checkEventTemplateStatus("", "", "")
+			})
+		})
+		Context("upload fails", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListCustomTemplatesHandler(),
+					test.NewUploadEventTemplateFailHandler("test-template.jfc", test.EventTemplateContent),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectEventTemplateReconcileError()
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectEventTemplateReconcileError()
+				t.checkAvailableCondition(metav1.ConditionFalse, "UploadFailed")
+			})
+		})
+		Context("EventTemplate does not exist", func() {
+			It("should do nothing", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("Cryostat CR is missing", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCACert(), test.NewCryostatService(), test.NewEventTemplate(),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectEventTemplateReconcileError()
+			})
+		})
+		Context("deleting an EventTemplate", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewDeletedEventTemplate(),
+				}
+			})
+			Context("that is uploaded", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteEventTemplateHandler("Custom"),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.expectNoEventTemplateFinalizer()
+				})
+			})
+			Context("that Cryostat no longer knows about", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteEventTemplateNotFoundHandler("Custom"),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.expectNoEventTemplateFinalizer()
+				})
+			})
+			Context("after Cryostat is deleted", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCACert(), test.NewCryostatService(), test.NewDeletedEventTemplate(),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectEventTemplateReconcileSuccess()
+					t.expectNoEventTemplateFinalizer()
+				})
+			})
+		})
+	})
+})
+
+func (t *eventTemplateTestInput) expectEventTemplateReconcileSuccess() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-template", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *eventTemplateTestInput) expectEventTemplateReconcileError() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-template", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).To(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *eventTemplateTestInput) getEventTemplate() *operatorv1beta1.EventTemplate {
+	template := &operatorv1beta1.EventTemplate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-template", Namespace: "default"}, template)
+	Expect(err).ToNot(HaveOccurred())
+	return template
+}
+
+func (t *eventTemplateTestInput) checkEventTemplateStatus(templateName string, description string, provider string) {
+	template := t.getEventTemplate()
+	Expect(template.Status.TemplateName).To(Equal(templateName))
+	Expect(template.Status.Description).To(Equal(description))
+	Expect(template.Status.Provider).To(Equal(provider))
+}
+
+func (t *eventTemplateTestInput) checkAvailableCondition(status metav1.ConditionStatus, reason string) {
+	template := t.getEventTemplate()
+	condition := meta.FindStatusCondition(template.Status.Conditions, string(operatorv1beta1.ConditionTypeTemplateAvailable))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *eventTemplateTestInput) expectNoEventTemplateFinalizer() {
+	template := &operatorv1beta1.EventTemplate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-template", Namespace: "default"}, template)
+	if err != nil {
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		return
+	}
+	Expect(template.Finalizers).ToNot(ContainElement("operator.cryostat.io/eventtemplate.finalizer"))
+}
@@ -38,6 +38,8 @@ package controllers
 
 import (
 	"context"
+	goerrors "errors"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -47,11 +49,22 @@ import (
 	"time"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
 	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // FlightRecorderReconciler reconciles a FlightRecorder object
@@ -81,7 +94,8 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			reqLogger.Info("FlightRecorder does not exist")
-			return reconcile.Result{}, nil
+			// Drop the CA certificate of the deleted FlightRecorder from Cryostat's truststore
+			return reconcile.Result{}, r.updateTargetCASecret(ctx, request.Namespace, request.Name, nil)
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
@@ -94,6 +108,17 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 		return reconcile.Result{RequeueAfter: time.Second}, nil
 	}
 
+	// Provide Cryostat with the CA certificate needed to connect to this pod over TLS
+	clientCert, err := r.configureTLS(ctx, instance)
+	if err != nil {
+		reqLogger.Error(err, "failed to configure TLS for target")
+		setTLSHandshakeCondition(instance, metav1.ConditionFalse, reasonCertificateUnavailable, err.Error())
+		if updateErr := r.Client.Status().Update(ctx, instance); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
 	// Obtain a client configured to communicate with Cryostat
 	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, instance.Spec.JMXCredentials)
 	if err != nil {
@@ -104,32 +129,55 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 		return reconcile.Result{}, err
 	}
 
-	// Look up pod corresponding to this FlightRecorder object
-	targetPod := &corev1.Pod{}
-	err = r.Client.Get(ctx, types.NamespacedName{Namespace: targetRef.Namespace, Name: targetRef.Name}, targetPod)
+	// Get a TargetAddress for the pod or custom target of this FlightRecorder
+	targetAddr, err := r.GetFlightRecorderTarget(ctx, instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// Get a TargetAddress for this pod
-	targetAddr, err := r.GetPodTarget(targetPod, instance.Status.Port)
-	if err != nil {
-		return reconcile.Result{}, err
+	// Configure the client certificate Cryostat presents to this pod, if any
+	if clientCert != nil {
+		err = cryostat.UploadClientCertificate(targetAddr, clientCert.cert, clientCert.key)
+		if err != nil {
+			reqLogger.Error(err, "failed to upload client certificate for target")
+			return reconcile.Result{}, err
+		}
 	}
 
 	// Retrieve list of available events
-	reqLogger.Info("Listing event types for pod", "name", targetPod.Name, "namespace", targetPod.Namespace)
+	reqLogger.Info("Listing event types for target", "name", targetRef.Name, "namespace", targetRef.Namespace)
 	events, err := cryostat.ListEventTypes(targetAddr)
 	if err != nil {
 		reqLogger.Error(err, "failed to list event types")
+		if instance.Spec.TLS != nil {
+			// Report the outcome of the connection attempt in the FlightRecorder's status
+			if cryostatClient.IsTLSHandshakeError(err) {
+				message := err.(*cryostatClient.StatusError).Message
+				setTLSHandshakeCondition(instance, metav1.ConditionFalse, reasonHandshakeFailed, message)
+			} else {
+				setTLSHandshakeCondition(instance, metav1.ConditionUnknown, reasonConnectionFailed, err.Error())
+			}
+			if updateErr := r.Client.Status().Update(ctx, instance); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+		}
 		return reconcile.Result{}, err
 	}
 
+	// Connection succeeded, so any TLS handshake must have as well
+	if instance.Spec.TLS != nil {
+		setTLSHandshakeCondition(instance, metav1.ConditionTrue, reasonHandshakeSucceeded,
+			"Cryostat established a TLS connection with the target JVM.")
+	} else if meta.FindStatusCondition(instance.Status.Conditions,
+		string(operatorv1beta1.ConditionTypeTLSHandshakeSucceeded)) != nil {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, string(operatorv1beta1.ConditionTypeTLSHandshakeSucceeded))
+	}
+
 	// Update Status with events
 	instance.Status.Events = events
 
 	// Retrieve list of available templates
-	reqLogger.Info("Listing templates for pod", "name", targetPod.Name, "namespace", targetPod.Namespace)
+	reqLogger.Info("Listing templates for target", "name", targetRef.Name, "namespace", targetRef.Namespace)
 	templates, err := cryostat.ListTemplates(targetAddr)
 	if err != nil {
 		reqLogger.Error(err, "failed to list templates")
@@ -139,6 +187,28 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 	// Update Status with templates
 	instance.Status.Templates = templates
 
+	// Retrieve runtime details of the JVM
+	reqLogger.Info("Retrieving JVM details for target", "name", targetRef.Name, "namespace", targetRef.Namespace)
+	metrics, err := cryostat.GetMBeanMetrics(targetAddr)
+	if err != nil {
+		// JVM details are informational, so keep the previous details and continue
+		reqLogger.Error(err, "failed to retrieve JVM details")
+	} else {
+		// Update Status with JVM details
+		instance.Status.JVMInfo = newJVMInfo(metrics)
+	}
+
+	// Apply probes from the requested ProbeTemplate, if any
+	err = r.reconcileProbes(ctx, cryostat, instance, targetAddr)
+	if err != nil {
+		reqLogger.Error(err, "failed to apply probes")
+		// Report the failure in the FlightRecorder's status
+		if updateErr := r.Client.Status().Update(ctx, instance); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
 	err = r.Client.Status().Update(ctx, instance)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -148,9 +218,359 @@ func (r *FlightRecorderReconciler) Reconcile(ctx context.Context, request ctrl.R
 	return reconcile.Result{}, nil
 }
 
+const (
+	reasonHandshakeSucceeded     = "HandshakeSucceeded"
+	reasonHandshakeFailed        = "HandshakeFailed"
+	reasonConnectionFailed       = "ConnectionFailed"
+	reasonCertificateUnavailable = "CertificateUnavailable"
+)
+
+const (
+	reasonProbesApplied             = "ProbesApplied"
+	reasonProbeTemplateNotFound     = "ProbeTemplateNotFound"
+	reasonProbeTemplateNotAvailable = "ProbeTemplateNotAvailable"
+	reasonProbesFailed              = "ApplyFailed"
+)
+
+func (r *FlightRecorderReconciler) reconcileProbes(ctx context.Context, cryostat cryostatClient.CryostatClient,
+	fr *operatorv1beta1.FlightRecorder, target *cryostatClient.TargetAddress) error {
+	if fr.Spec.ProbeTemplate == nil {
+		// Remove any probes we previously applied
+		if fr.Status.Probes != nil {
+			err := cryostat.RemoveProbes(target)
+			if err != nil {
+				return err
+			}
+			fr.Status.Probes = nil
+		}
+		if meta.FindStatusCondition(fr.Status.Conditions, string(operatorv1beta1.ConditionTypeProbesApplied)) != nil {
+			meta.RemoveStatusCondition(&fr.Status.Conditions, string(operatorv1beta1.ConditionTypeProbesApplied))
+		}
+		return nil
+	}
+
+	// Probes can only be applied once the template is available in Cryostat.
+	// The ProbeTemplate watch retries once that happens.
+	template := &operatorv1beta1.ProbeTemplate{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: *fr.Spec.ProbeTemplate, Namespace: fr.Namespace}, template)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			setProbesAppliedCondition(fr, metav1.ConditionFalse, reasonProbeTemplateNotFound,
+				fmt.Sprintf("ProbeTemplate %s not found", *fr.Spec.ProbeTemplate))
+			return nil
+		}
+		return err
+	}
+	if !meta.IsStatusConditionTrue(template.Status.Conditions, string(operatorv1beta1.ConditionTypeProbeTemplateAvailable)) {
+		setProbesAppliedCondition(fr, metav1.ConditionFalse, reasonProbeTemplateNotAvailable,
+			fmt.Sprintf("ProbeTemplate %s is not available in Cryostat", template.Name))
+		return nil
+	}
+
+	// Apply the template if it differs from what was last applied
+	probes := fr.Status.Probes
+	if probes == nil || probes.ProbeTemplate != template.Name || probes.ContentHash != template.Status.ContentHash {
+		if probes != nil {
+			err = cryostat.RemoveProbes(target)
+			if err != nil {
+				setProbesAppliedCondition(fr, metav1.ConditionFalse, reasonProbesFailed, err.Error())
+				return err
+			}
+			fr.Status.Probes = nil
+		}
+		err = cryostat.ApplyProbes(target, template.Status.TemplateName)
+		if err != nil {
+			setProbesAppliedCondition(fr, metav1.ConditionFalse, reasonProbesFailed, err.Error())
+			return err
+		}
+		fr.Status.Probes = &operatorv1beta1.ProbeStatus{
+			ProbeTemplate: template.Name,
+			ContentHash:   template.Status.ContentHash,
+		}
+	}
+
+	// Report the probes that are active in the target JVM
+	active, err := cryostat.ListProbes(target)
+	if err != nil {
+		return err
+	}
+	fr.Status.Probes.Active = newProbeInfo(active)
+	setProbesAppliedCondition(fr, metav1.ConditionTrue, reasonProbesApplied,
+		fmt.Sprintf("Probes from ProbeTemplate %s are active in the target JVM.", template.Name))
+	return nil
+}
+
+func newProbeInfo(events []cryostatClient.ProbeEvent) []operatorv1beta1.ProbeInfo {
+	probes := make([]operatorv1beta1.ProbeInfo, 0, len(events))
+	for _, event := range events {
+		probes = append(probes, operatorv1beta1.ProbeInfo{
+			ID:               event.ID,
+			Name:             event.Name,
+			Description:      event.Description,
+			Class:            event.Class,
+			Method:           event.MethodName,
+			MethodDescriptor: event.MethodDescriptor,
+		})
+	}
+	return probes
+}
+
+// targetClientCert is a client certificate and private key that Cryostat
+// presents to a JVM requiring client authentication
+type targetClientCert struct {
+	cert []byte
+	key  []byte
+}
+
+// configureTLS adds the CA certificate of the FlightRecorder's JVM to Cryostat's
+// truststore, or removes it if the FlightRecorder no longer uses TLS. Any client
+// certificate for the JVM is returned, to be uploaded to Cryostat.
+func (r *FlightRecorderReconciler) configureTLS(ctx context.Context, fr *operatorv1beta1.FlightRecorder) (*targetClientCert, error) {
+	if fr.Spec.TLS == nil {
+		return nil, r.updateTargetCASecret(ctx, fr.Namespace, fr.Name, nil)
+	}
+	caCert, err := r.getTargetCACert(ctx, fr)
+	if err != nil {
+		return nil, err
+	}
+	var clientCert *targetClientCert
+	if fr.Spec.TLS.ClientCertSecret != nil {
+		clientCert, err = r.getTargetClientCert(ctx, fr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clientCert, r.updateTargetCASecret(ctx, fr.Namespace, fr.Name, caCert)
+}
+
+// updateTargetCASecret stores the CA certificate of a FlightRecorder's JVM in a Secret
+// mounted into Cryostat's truststore, under a key belonging to that FlightRecorder.
+// A rotated certificate replaces the previous one, and a nil certificate removes it.
+// The Secret is part of the digest of Cryostat's configuration, so Cryostat is
+// redeployed to load the updated truststore.
+func (r *FlightRecorderReconciler) updateTargetCASecret(ctx context.Context, namespace string, name string,
+	caCert []byte) error {
+	cr, err := r.FindCryostat(ctx, namespace)
+	if err != nil {
+		if goerrors.Is(err, common.ErrCryostatNotFound) {
+			// FlightRecorders are reconciled again once a Cryostat is created
+			return nil
+		}
+		return err
+	}
+
+	secret := resources.NewTargetCASecretForCR(cr)
+	key := fmt.Sprintf("%s_%s.crt", namespace, name)
+	op, err := r.createOrUpdate(ctx, secret, func() error {
+		if caCert == nil {
+			delete(secret.Data, key)
+		} else {
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[key] = caCert
+		}
+		return controllerutil.SetControllerReference(cr, secret, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("Secret %s", op), "name", secret.Name, "namespace", secret.Namespace)
+	}
+	return nil
+}
+
+func (r *FlightRecorderReconciler) createOrUpdate(ctx context.Context, obj client.Object,
+	mutate controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	return createOrUpdate(ctx, r.Client, r.Scheme, obj, mutate)
+}
+
+func (r *FlightRecorderReconciler) getTargetCACert(ctx context.Context, fr *operatorv1beta1.FlightRecorder) ([]byte, error) {
+	caSecret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: fr.Spec.TLS.CACert.SecretName, Namespace: fr.Namespace}, caSecret)
+	if err != nil {
+		return nil, err
+	}
+	certKey := operatorv1beta1.DefaultCertificateKey
+	if fr.Spec.TLS.CACert.CertificateKey != nil {
+		certKey = *fr.Spec.TLS.CACert.CertificateKey
+	}
+	caCert, pres := caSecret.Data[certKey]
+	if !pres {
+		return nil, fmt.Errorf("No key \"%s\" found in secret \"%s/%s\"", certKey, caSecret.Namespace, caSecret.Name)
+	}
+	return caCert, nil
+}
+
+func (r *FlightRecorderReconciler) getTargetClientCert(ctx context.Context,
+	fr *operatorv1beta1.FlightRecorder) (*targetClientCert, error) {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: *fr.Spec.TLS.ClientCertSecret, Namespace: fr.Namespace}, secret)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if _, pres := secret.Data[key]; !pres {
+			return nil, fmt.Errorf("No key \"%s\" found in secret \"%s/%s\"", key, secret.Namespace, secret.Name)
+		}
+	}
+	return &targetClientCert{
+		cert: secret.Data[corev1.TLSCertKey],
+		key:  secret.Data[corev1.TLSPrivateKeyKey],
+	}, nil
+}
+
+func newJVMInfo(metrics *cryostatClient.MBeanMetrics) *operatorv1beta1.JVMInfo {
+	runtimeInfo := metrics.Runtime
+	heap := metrics.Memory.HeapMemoryUsage
+	info := &operatorv1beta1.JVMInfo{
+		Version:        runtimeInfo.VMVersion,
+		Vendor:         runtimeInfo.VMVendor,
+		InputArguments: runtimeInfo.InputArguments,
+	}
+	if runtimeInfo.StartTime > 0 {
+		startTime := metav1.Unix(0, runtimeInfo.StartTime*int64(time.Millisecond))
+		info.StartTime = &startTime
+	}
+	if runtimeInfo.Uptime > 0 {
+		info.Uptime = &metav1.Duration{Duration: time.Duration(runtimeInfo.Uptime) * time.Millisecond}
+	}
+	// MemoryUsage reports -1 for undefined values
+	if heap.Init >= 0 {
+		info.HeapInitial = resource.NewQuantity(heap.Init, resource.BinarySI)
+	}
+	if heap.Max >= 0 {
+		info.HeapMax = resource.NewQuantity(heap.Max, resource.BinarySI)
+	}
+	return info
+}
+
+func setTLSHandshakeCondition(fr *operatorv1beta1.FlightRecorder, status metav1.ConditionStatus, reason string,
+	message string) {
+	meta.SetStatusCondition(&fr.Status.Conditions, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeTLSHandshakeSucceeded),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+func setProbesAppliedCondition(fr *operatorv1beta1.FlightRecorder, status metav1.ConditionStatus, reason string,
+	message string) {
+	meta.SetStatusCondition(&fr.Status.Conditions, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeProbesApplied),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *FlightRecorderReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1beta1.FlightRecorder{}).
-		Complete(r)
+	c := ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.FlightRecorder{})
+	// Reconcile FlightRecorders when their referenced secrets change,
+	// so that updated certificates reach Cryostat without a restart
+	c = c.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.secretToFlightRecorders))
+	// Apply probes once their template is uploaded, and reapply them when it changes
+	c = c.Watches(&source.Kind{Type: &operatorv1beta1.ProbeTemplate{}},
+		handler.EnqueueRequestsFromMapFunc(r.probeTemplateToFlightRecorders))
+	// Add CA certificates to the truststore of a newly created Cryostat
+	c = c.Watches(&source.Kind{Type: &operatorv1beta1.Cryostat{}},
+		handler.EnqueueRequestsFromMapFunc(r.cryostatToFlightRecorders),
+		builder.WithPredicates(predicate.Funcs{
+			UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		}))
+	return c.Complete(r)
+}
+
+func (r *FlightRecorderReconciler) secretToFlightRecorders(obj client.Object) []reconcile.Request {
+	recorders := &operatorv1beta1.FlightRecorderList{}
+	err := r.Client.List(context.Background(), recorders, &client.ListOptions{
+		Namespace: obj.GetNamespace(),
+	})
+	if err != nil {
+		r.Log.Error(err, "Failed to list FlightRecorders", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, recorder := range recorders.Items {
+		if referencesSecret(&recorder, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: recorder.Namespace,
+					Name:      recorder.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+func (r *FlightRecorderReconciler) probeTemplateToFlightRecorders(obj client.Object) []reconcile.Request {
+	recorders := &operatorv1beta1.FlightRecorderList{}
+	err := r.Client.List(context.Background(), recorders, &client.ListOptions{
+		Namespace: obj.GetNamespace(),
+	})
+	if err != nil {
+		r.Log.Error(err, "Failed to list FlightRecorders", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, recorder := range recorders.Items {
+		if recorder.Spec.ProbeTemplate != nil && *recorder.Spec.ProbeTemplate == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: recorder.Namespace,
+					Name:      recorder.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+func (r *FlightRecorderReconciler) cryostatToFlightRecorders(obj client.Object) []reconcile.Request {
+	recorders := &operatorv1beta1.FlightRecorderList{}
+	err := r.Client.List(context.Background(), recorders, &client.ListOptions{
+		Namespace: obj.GetNamespace(),
+	})
+	if err != nil {
+		r.Log.Error(err, "Failed to list FlightRecorders", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, recorder := range recorders.Items {
+		if recorder.Spec.TLS != nil {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: recorder.Namespace,
+					Name:      recorder.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+func referencesSecret(fr *operatorv1beta1.FlightRecorder, secretName string) bool {
+	if fr.Spec.JMXCredentials != nil && fr.Spec.JMXCredentials.SecretName == secretName {
+		return true
+	}
+	tlsConfig := fr.Spec.TLS
+	if tlsConfig != nil {
+		if tlsConfig.CACert.SecretName == secretName {
+			return true
+		}
+		if tlsConfig.ClientCertSecret != nil && *tlsConfig.ClientCertSecret == secretName {
+			return true
+		}
+	}
+	return false
 }
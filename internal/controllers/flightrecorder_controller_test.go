@@ -43,6 +43,10 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -108,6 +112,7 @@ var _ = Describe("FlightRecorderController", func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
 				}
 			})
 			It("should update event type list", func() {
@@ -119,8 +124,10 @@ var _ = Describe("FlightRecorderController", func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
 				}
 			})
 			It("should be idempotent", func() {
@@ -190,6 +197,26 @@ var _ = Describe("FlightRecorderController", func() {
 				t.expectFlightRecorderReconcileError()
 			})
 		})
+		Context("retrieving JVM details fails", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsFailHandler(),
+				}
+			})
+			It("should update event type list and template list", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+
+				obj := t.getFlightRecorder()
+				Expect(obj.Status.Events).To(Equal(test.NewEventTypes()))
+				Expect(obj.Status.Templates).To(Equal(test.NewTemplates()))
+				Expect(obj.Status.JVMInfo).To(BeNil())
+			})
+		})
 		Context("Cryostat CR is missing", func() {
 			BeforeEach(func() {
 				t.objs = []runtime.Object{
@@ -245,6 +272,7 @@ var _ = Describe("FlightRecorderController", func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesNoJMXAuthHandler(),
 					test.NewListTemplatesNoJMXAuthHandler(),
+					test.NewGetMBeanMetricsNoJMXAuthHandler(),
 				}
 			})
 			It("should update event type list and template list", func() {
@@ -284,11 +312,96 @@ var _ = Describe("FlightRecorderController", func() {
 				t.expectFlightRecorderReconcileError()
 			})
 		})
+		Context("successfully updates FlightRecorder CR for a Cryostat agent", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderForAgent(),
+					test.NewTargetPod(), test.NewCryostatService(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesAgentHandler(),
+					test.NewListTemplatesAgentHandler(),
+					test.NewGetMBeanMetricsAgentHandler(),
+				}
+			})
+			It("should update event type list and template list", func() {
+				t.expectFlightRecorderReconcileSuccess()
+			})
+		})
+		Context("successfully updates FlightRecorder CR for a CustomTarget", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderForCustomTarget(),
+					test.NewCustomTarget(), test.NewCryostatService(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesNoJMXAuthHandler(),
+					test.NewListTemplatesNoJMXAuthHandler(),
+					test.NewGetMBeanMetricsNoJMXAuthHandler(),
+				}
+			})
+			It("should update event type list and template list", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-custom-target", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+
+				obj := &operatorv1beta1.FlightRecorder{}
+				err = t.Client.Get(context.Background(), req.NamespacedName, obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(obj.Status.Events).To(Equal(test.NewEventTypes()))
+				Expect(obj.Status.Templates).To(Equal(test.NewTemplates()))
+			})
+		})
+		Context("with a FlightRecorder created for a CustomTarget", func() {
+			var registered string
+
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCustomTarget(), test.NewCryostatService(),
+				}
+				t.handlers = []http.HandlerFunc{
+					ghttp.CombineHandlers(
+						test.NewCreateCustomTargetHandler(test.CustomTargetConnectURL, "my-jvm"),
+						func(w http.ResponseWriter, r *http.Request) {
+							registered = r.FormValue("connectUrl")
+						},
+					),
+					test.NewListEventTypesNoJMXAuthHandler(),
+					test.NewListTemplatesNoJMXAuthHandler(),
+					// Expect the JVM details to be looked up by the URL the target was registered with
+					func(w http.ResponseWriter, r *http.Request) {
+						test.NewGetMBeanMetricsForURLHandler(registered)(w, r)
+					},
+				}
+			})
+			It("should look up the target registered by the CustomTarget", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-custom-target", Namespace: "default"}}
+				targetController := &controllers.CustomTargetReconciler{
+					Client:     t.Client,
+					Scheme:     t.controller.Scheme,
+					Log:        t.controller.Log,
+					Reconciler: t.controller.Reconciler,
+				}
+				_, err := targetController.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+
+				obj := &operatorv1beta1.FlightRecorder{}
+				err = t.Client.Get(context.Background(), req.NamespacedName, obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(obj.Status.JVMInfo).ToNot(BeNil())
+			})
+		})
 		Context("successfully updates FlightRecorder CR with TLS disabled", func() {
 			BeforeEach(func() {
 				t.handlers = []http.HandlerFunc{
 					test.NewListEventTypesHandler(),
 					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
 				}
 				disableTLS := true
 				t.EnvDisableTLS = &disableTLS
@@ -297,6 +410,297 @@ var _ = Describe("FlightRecorderController", func() {
 				t.expectFlightRecorderReconcileSuccess()
 			})
 		})
+		Context("with TLS configured", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithTLS(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewTargetCASecret(),
+				}
+			})
+			Context("and the handshake succeeds", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewGetMBeanMetricsHandler(),
+					}
+				})
+				It("should update event type list and template list", func() {
+					t.expectFlightRecorderReconcileSuccess()
+				})
+				It("should add the CA certificate to Cryostat's truststore", func() {
+					t.expectFlightRecorderReconcileSuccess()
+					t.checkTargetCASecret(map[string][]byte{
+						"default_test-pod.crt": []byte(test.TargetCACert),
+					})
+				})
+				It("should set TLSHandshakeSucceeded condition", func() {
+					t.expectFlightRecorderReconcileSuccess()
+					t.checkTLSHandshakeCondition(metav1.ConditionTrue, "HandshakeSucceeded")
+				})
+			})
+			Context("and the truststore contains an outdated certificate", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, test.NewTargetCASecretForCryostat(map[string][]byte{
+						"default_test-pod.crt":  []byte("old-target-ca-bytes"),
+						"default_other-pod.crt": []byte("other-target-ca-bytes"),
+					}))
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandler(),
+						test.NewListTemplatesHandler(),
+						test.NewGetMBeanMetricsHandler(),
+					}
+				})
+				It("should replace the outdated certificate", func() {
+					t.expectFlightRecorderReconcileSuccess()
+					t.checkTargetCASecret(map[string][]byte{
+						"default_test-pod.crt":  []byte(test.TargetCACert),
+						"default_other-pod.crt": []byte("other-target-ca-bytes"),
+					})
+				})
+			})
+			Context("and the handshake fails", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesHandshakeFailHandler(),
+					}
+				})
+				It("should requeue with error", func() {
+					t.expectFlightRecorderReconcileError()
+				})
+				It("should set TLSHandshakeSucceeded condition", func() {
+					t.expectFlightRecorderReconcileError()
+					t.checkTLSHandshakeCondition(metav1.ConditionFalse, "HandshakeFailed")
+				})
+			})
+			Context("and the connection fails", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListEventTypesFailHandler(),
+					}
+				})
+				It("should requeue with error", func() {
+					t.expectFlightRecorderReconcileError()
+				})
+				It("should set TLSHandshakeSucceeded condition", func() {
+					t.expectFlightRecorderReconcileError()
+					t.checkTLSHandshakeCondition(metav1.ConditionUnknown, "ConnectionFailed")
+				})
+			})
+			Context("and the CA secret is missing", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithTLS(), test.NewTargetPod(),
+						test.NewCryostatService(), test.NewJMXAuthSecret(),
+					}
+				})
+				It("should requeue with error", func() {
+					t.expectFlightRecorderReconcileError()
+				})
+				It("should set TLSHandshakeSucceeded condition", func() {
+					t.expectFlightRecorderReconcileError()
+					t.checkTLSHandshakeCondition(metav1.ConditionFalse, "CertificateUnavailable")
+				})
+			})
+		})
+		Context("with TLS client certificate configured", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithClientCert(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewTargetCASecret(),
+					test.NewTargetClientCertSecret(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewUploadClientCertificateHandler(),
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+				}
+			})
+			It("should update event type list and template list", func() {
+				t.expectFlightRecorderReconcileSuccess()
+			})
+			Context("and the client certificate secret is missing", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithClientCert(), test.NewTargetPod(),
+						test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewTargetCASecret(),
+					}
+					t.handlers = []http.HandlerFunc{}
+				})
+				It("should set TLSHandshakeSucceeded condition", func() {
+					t.expectFlightRecorderReconcileError()
+					t.checkTLSHandshakeCondition(metav1.ConditionFalse, "CertificateUnavailable")
+				})
+			})
+		})
+		Context("after a FlightRecorder with TLS is deleted", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewTargetCASecret(),
+					test.NewTargetCASecretForCryostat(map[string][]byte{
+						"default_test-pod.crt": []byte(test.TargetCACert),
+					}),
+				}
+			})
+			It("should remove its CA certificate from Cryostat's truststore", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-pod", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				t.checkTargetCASecret(map[string][]byte{})
+			})
+		})
+		Context("with a ProbeTemplate", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithProbes(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewProbeTemplateUploaded(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+					test.NewApplyProbesHandler("test-probes"),
+					test.NewListProbesHandler(),
+				}
+			})
+			It("should apply the probes", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				t.checkProbeStatus()
+			})
+			It("should set ProbesApplied condition", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				t.checkProbesAppliedCondition(metav1.ConditionTrue, "ProbesApplied")
+			})
+		})
+		Context("with probes already applied", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderProbesApplied(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewProbeTemplateUploaded(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+					test.NewListProbesHandler(),
+				}
+			})
+			It("should not apply the probes again", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				t.checkProbeStatus()
+				t.checkProbesAppliedCondition(metav1.ConditionTrue, "ProbesApplied")
+			})
+		})
+		Context("with a changed ProbeTemplate", func() {
+			BeforeEach(func() {
+				template := test.NewProbeTemplateUploaded()
+				template.Status.ContentHash = "changed"
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderProbesApplied(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), template,
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+					test.NewRemoveProbesHandler(),
+					test.NewApplyProbesHandler("test-probes"),
+					test.NewListProbesHandler(),
+				}
+			})
+			It("should reapply the probes", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				obj := t.getFlightRecorder()
+				Expect(obj.Status.Probes).ToNot(BeNil())
+				Expect(obj.Status.Probes.ContentHash).To(Equal("changed"))
+				Expect(obj.Status.Probes.Active).To(Equal(test.NewProbes()))
+			})
+		})
+		Context("with a missing ProbeTemplate", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithProbes(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+				}
+			})
+			It("should set ProbesApplied condition", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				t.checkProbesAppliedCondition(metav1.ConditionFalse, "ProbeTemplateNotFound")
+				Expect(t.getFlightRecorder().Status.Probes).To(BeNil())
+			})
+		})
+		Context("with an unavailable ProbeTemplate", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithProbes(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewProbeTemplateNotAvailable(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+				}
+			})
+			It("should set ProbesApplied condition", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				t.checkProbesAppliedCondition(metav1.ConditionFalse, "ProbeTemplateNotAvailable")
+			})
+		})
+		Context("applying probes fails", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewFlightRecorderWithProbes(), test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(), test.NewProbeTemplateUploaded(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+					test.NewApplyProbesFailHandler("test-probes"),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectFlightRecorderReconcileError()
+			})
+			It("should set ProbesApplied condition", func() {
+				t.expectFlightRecorderReconcileError()
+				t.checkProbesAppliedCondition(metav1.ConditionFalse, "ApplyFailed")
+			})
+		})
+		Context("with a ProbeTemplate removed", func() {
+			BeforeEach(func() {
+				fr := test.NewFlightRecorderProbesRemoved()
+				meta.SetStatusCondition(&fr.Status.Conditions, metav1.Condition{
+					Type:   string(operatorv1beta1.ConditionTypeProbesApplied),
+					Status: metav1.ConditionTrue,
+					Reason: "ProbesApplied",
+				})
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), fr, test.NewTargetPod(),
+					test.NewCryostatService(), test.NewJMXAuthSecret(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListEventTypesHandler(),
+					test.NewListTemplatesHandler(),
+					test.NewGetMBeanMetricsHandler(),
+					test.NewRemoveProbesHandler(),
+				}
+			})
+			It("should remove the probes", func() {
+				t.expectFlightRecorderReconcileSuccess()
+				obj := t.getFlightRecorder()
+				Expect(obj.Status.Probes).To(BeNil())
+				Expect(meta.FindStatusCondition(obj.Status.Conditions,
+					string(operatorv1beta1.ConditionTypeProbesApplied))).To(BeNil())
+			})
+		})
 	})
 })
 
@@ -311,6 +715,43 @@ func (t *flightRecorderTestInput) expectFlightRecorderReconcileSuccess() {
 	Expect(err).ToNot(HaveOccurred())
 	Expect(obj.Status.Events).To(Equal(test.NewEventTypes()))
 	Expect(obj.Status.Templates).To(Equal(test.NewTemplates()))
+	checkJVMInfo(obj.Status.JVMInfo, test.NewJVMInfo())
+}
+
+func checkJVMInfo(info *operatorv1beta1.JVMInfo, expected *operatorv1beta1.JVMInfo) {
+	Expect(info).ToNot(BeNil())
+	Expect(info.Version).To(Equal(expected.Version))
+	Expect(info.Vendor).To(Equal(expected.Vendor))
+	Expect(info.StartTime.Equal(expected.StartTime)).To(BeTrue())
+	Expect(info.Uptime).To(Equal(expected.Uptime))
+	Expect(info.HeapInitial.Cmp(*expected.HeapInitial)).To(BeZero())
+	Expect(info.HeapMax.Cmp(*expected.HeapMax)).To(BeZero())
+	Expect(info.InputArguments).To(Equal(expected.InputArguments))
+}
+
+func (t *flightRecorderTestInput) checkTLSHandshakeCondition(status metav1.ConditionStatus, reason string) {
+	obj := &operatorv1beta1.FlightRecorder{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, obj)
+	Expect(err).ToNot(HaveOccurred())
+
+	condition := meta.FindStatusCondition(obj.Status.Conditions, string(operatorv1beta1.ConditionTypeTLSHandshakeSucceeded))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *flightRecorderTestInput) checkTargetCASecret(expected map[string][]byte) {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat-target-ca", Namespace: "default"}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(secret.Data).To(HaveLen(len(expected)))
+	for key, value := range expected {
+		Expect(secret.Data).To(HaveKeyWithValue(key, value))
+	}
+	owner := metav1.GetControllerOf(secret)
+	Expect(owner).ToNot(BeNil())
+	Expect(owner.Kind).To(Equal("Cryostat"))
+	Expect(owner.Name).To(Equal("cryostat"))
 }
 
 func (t *flightRecorderTestInput) expectFlightRecorderReconcileError() {
@@ -319,3 +760,26 @@ func (t *flightRecorderTestInput) expectFlightRecorderReconcileError() {
 	Expect(err).To(HaveOccurred())
 	Expect(result).To(Equal(reconcile.Result{}))
 }
+
+func (t *flightRecorderTestInput) getFlightRecorder() *operatorv1beta1.FlightRecorder {
+	obj := &operatorv1beta1.FlightRecorder{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-pod", Namespace: "default"}, obj)
+	Expect(err).ToNot(HaveOccurred())
+	return obj
+}
+
+func (t *flightRecorderTestInput) checkProbeStatus() {
+	obj := t.getFlightRecorder()
+	Expect(obj.Status.Probes).ToNot(BeNil())
+	Expect(obj.Status.Probes.ProbeTemplate).To(Equal("test-probes"))
+	Expect(obj.Status.Probes.ContentHash).To(Equal(test.NewProbeTemplateUploaded().Status.ContentHash))
+	Expect(obj.Status.Probes.Active).To(Equal(test.NewProbes()))
+}
+
+func (t *flightRecorderTestInput) checkProbesAppliedCondition(status metav1.ConditionStatus, reason string) {
+	obj := t.getFlightRecorder()
+	condition := meta.FindStatusCondition(obj.Status.Conditions, string(operatorv1beta1.ConditionTypeProbesApplied))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
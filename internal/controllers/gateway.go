@@ -0,0 +1,294 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Gateway API resources are managed as unstructured objects, so that the
+// operator does not depend on a particular release of the Gateway API
+var (
+	gatewayGroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1"}
+	gatewayGVK          = gatewayGroupVersion.WithKind("Gateway")
+	httpRouteGVK        = gatewayGroupVersion.WithKind("HTTPRoute")
+	httpRouteListGVK    = gatewayGroupVersion.WithKind("HTTPRouteList")
+)
+
+const (
+	gatewayProtocolHTTP  = "HTTP"
+	gatewayProtocolHTTPS = "HTTPS"
+)
+
+func (r *CryostatReconciler) createHTTPRouteForService(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	svc *corev1.Service, exposePort corev1.ServicePort, gatewayConfig *operatorv1beta1.GatewayConfiguration) (*url.URL, error) {
+	logger := r.Log.WithValues("Request.Namespace", svc.Namespace, "Name", svc.Name, "Kind", httpRouteGVK.Kind)
+
+	available, err := r.gatewayAPIAvailable()
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, fmt.Errorf("gatewayConfig is specified, but the Gateway API is not available in the cluster")
+	}
+
+	routeConfig, err := getHTTPRouteConfig(cr, svc, gatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+	gatewayNamespace := gatewayConfig.GatewayRef.Namespace
+	if len(gatewayNamespace) == 0 {
+		gatewayNamespace = cr.Namespace
+	}
+
+	// Fields defaulted by the API server are specified explicitly,
+	// so that the operator's changes can be compared to the existing route
+	parentRef := map[string]interface{}{
+		"group":     gatewayGVK.Group,
+		"kind":      gatewayGVK.Kind,
+		"name":      gatewayConfig.GatewayRef.Name,
+		"namespace": gatewayNamespace,
+	}
+	if len(gatewayConfig.GatewayRef.SectionName) > 0 {
+		parentRef["sectionName"] = gatewayConfig.GatewayRef.SectionName
+	}
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": "/",
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"group":  "",
+						"kind":   "Service",
+						"name":   svc.Name,
+						"port":   int64(exposePort.Port),
+						"weight": int64(1),
+					},
+				},
+			},
+		},
+	}
+	if len(routeConfig.Hostnames) > 0 {
+		hostnames := make([]interface{}, 0, len(routeConfig.Hostnames))
+		for _, hostname := range routeConfig.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	route.SetName(svc.Name)
+	route.SetNamespace(svc.Namespace)
+	op, err := r.createOrUpdate(ctx, route, func() error {
+		if err := controllerutil.SetControllerReference(cr, route, r.Scheme); err != nil {
+			return err
+		}
+		replaceManagedUnstructuredLabelsAndAnnotations(route, routeConfig.Labels, routeConfig.Annotations)
+		// Update HTTPRoute spec
+		return unstructured.SetNestedField(route.Object, spec, "spec")
+	})
+	if err != nil {
+		logger.Error(err, "Could not be created or updated")
+		return nil, err
+	}
+	logger.Info(fmt.Sprintf("HTTPRoute %s", op))
+
+	// Look up the Gateway to determine the hostname and scheme of the route
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	err = r.Client.Get(ctx, types.NamespacedName{Name: gatewayConfig.GatewayRef.Name, Namespace: gatewayNamespace}, gateway)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Info("Gateway not found", "Gateway.Namespace", gatewayNamespace,
+				"Gateway.Name", gatewayConfig.GatewayRef.Name)
+			return nil, ErrIngressNotReady
+		}
+		return nil, err
+	}
+	return getGatewayURL(gateway, gatewayConfig.GatewayRef.SectionName, routeConfig.Hostnames)
+}
+
+func (r *CryostatReconciler) gatewayAPIAvailable() (bool, error) {
+	_, err := r.RESTMapper.RESTMapping(httpRouteGVK.GroupKind(), httpRouteGVK.Version)
+	if err != nil {
+		// No matches for HTTPRoute GVK
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		// Unexpected error occurred
+		return false, err
+	}
+	return true, nil
+}
+
+func getHTTPRouteConfig(cr *operatorv1beta1.Cryostat, svc *corev1.Service,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration) (*operatorv1beta1.HTTPRouteConfiguration, error) {
+	var routeConfig *operatorv1beta1.HTTPRouteConfiguration
+	if svc.Name == cr.Name {
+		routeConfig = gatewayConfig.CoreRoute
+	} else if svc.Name == cr.Name+"-grafana" {
+		routeConfig = gatewayConfig.GrafanaRoute
+	} else {
+		return nil, goerrors.New("Service name not recognized")
+	}
+	if routeConfig == nil {
+		routeConfig = &operatorv1beta1.HTTPRouteConfiguration{}
+	}
+	return routeConfig, nil
+}
+
+// getGatewayURL determines the URL of an HTTPRoute attached to the Gateway.
+// The scheme and port are taken from the listener the route attaches to,
+// preferring listeners with TLS. The host is the first of the route's hostnames,
+// or else the listener's hostname, or else the first address of the Gateway.
+func getGatewayURL(gateway *unstructured.Unstructured, sectionName string, hostnames []string) (*url.URL, error) {
+	listeners, _, err := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	if err != nil {
+		return nil, err
+	}
+	var listener map[string]interface{}
+	var protocol string
+	for _, item := range listeners {
+		candidate, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(candidate, "name")
+		candidateProtocol, _, _ := unstructured.NestedString(candidate, "protocol")
+		hostname, _, _ := unstructured.NestedString(candidate, "hostname")
+		if len(sectionName) > 0 && name != sectionName {
+			continue
+		}
+		if candidateProtocol != gatewayProtocolHTTP && candidateProtocol != gatewayProtocolHTTPS {
+			continue
+		}
+		if len(hostnames) > 0 && !listenerHostnameMatches(hostname, hostnames[0]) {
+			continue
+		}
+		if listener == nil || (candidateProtocol == gatewayProtocolHTTPS && protocol != gatewayProtocolHTTPS) {
+			listener = candidate
+			protocol = candidateProtocol
+		}
+	}
+	if listener == nil {
+		return nil, fmt.Errorf("Gateway %s/%s has no HTTP or HTTPS listener for the HTTPRoute", gateway.GetNamespace(),
+			gateway.GetName())
+	}
+
+	host := ""
+	if len(hostnames) > 0 {
+		host = hostnames[0]
+	} else if hostname, _, _ := unstructured.NestedString(listener, "hostname"); len(hostname) > 0 &&
+		!strings.HasPrefix(hostname, "*") {
+		host = hostname
+	} else {
+		addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+		if len(addresses) > 0 {
+			if address, ok := addresses[0].(map[string]interface{}); ok {
+				host, _, _ = unstructured.NestedString(address, "value")
+			}
+		}
+	}
+	if len(host) == 0 {
+		return nil, ErrIngressNotReady
+	}
+
+	scheme := "http"
+	defaultPort := int64(80)
+	if protocol == gatewayProtocolHTTPS {
+		scheme = "https"
+		defaultPort = 443
+	}
+	port, found, _ := unstructured.NestedInt64(listener, "port")
+	if found && port != defaultPort {
+		host = net.JoinHostPort(host, strconv.FormatInt(port, 10))
+	}
+	return &url.URL{
+		Scheme: scheme,
+		Host:   host,
+	}, nil
+}
+
+// listenerHostnameMatches returns whether a Gateway listener with the provided
+// hostname accepts routes for the host, following the Gateway API's wildcard rules
+func listenerHostnameMatches(listenerHostname string, host string) bool {
+	if len(listenerHostname) == 0 {
+		return true
+	}
+	if strings.HasPrefix(listenerHostname, "*.") {
+		return strings.HasSuffix(host, listenerHostname[1:])
+	}
+	return listenerHostname == host
+}
+
+// replaceManagedUnstructuredLabelsAndAnnotations is replaceManagedLabelsAndAnnotations
+// for objects without typed metadata
+func replaceManagedUnstructuredLabelsAndAnnotations(dest *unstructured.Unstructured, labels map[string]string,
+	annotations map[string]string) {
+	objMeta := &metav1.ObjectMeta{
+		Labels:      dest.GetLabels(),
+		Annotations: dest.GetAnnotations(),
+	}
+	replaceManagedLabelsAndAnnotations(objMeta, labels, annotations)
+	dest.SetLabels(objMeta.Labels)
+	dest.SetAnnotations(objMeta.Annotations)
+}
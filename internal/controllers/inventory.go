@@ -0,0 +1,208 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	openshiftv1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Label containing the name of the Cryostat that an operator-managed object
+// belongs to. Objects with this label which are controlled by the Cryostat,
+// but are no longer part of its desired state, are deleted after a reconcile.
+const inventoryLabel = "operator.cryostat.io/cryostat"
+
+// inventory records the objects that a reconcile of a Cryostat created or
+// updated, which make up the desired state of that Cryostat
+type inventory struct {
+	owner   *operatorv1beta1.Cryostat
+	objects map[inventoryKey]struct{}
+}
+
+type inventoryKey struct {
+	kind schema.GroupKind
+	name string
+}
+
+type inventoryContextKey struct{}
+
+// withInventory returns a context that records the objects reconciled
+// for the provided Cryostat
+func withInventory(ctx context.Context, cr *operatorv1beta1.Cryostat) context.Context {
+	return context.WithValue(ctx, inventoryContextKey{}, &inventory{
+		owner:   cr,
+		objects: map[inventoryKey]struct{}{},
+	})
+}
+
+func inventoryFromContext(ctx context.Context) *inventory {
+	inv, _ := ctx.Value(inventoryContextKey{}).(*inventory)
+	return inv
+}
+
+// addToInventory records that the object is part of the desired state of the
+// Cryostat being reconciled. If the operator manages the object's metadata,
+// and the object is in the same namespace, it is also labelled as belonging
+// to that Cryostat.
+func addToInventory(ctx context.Context, scheme *runtime.Scheme, obj client.Object, label bool) error {
+	inv := inventoryFromContext(ctx)
+	if inv == nil {
+		return nil
+	}
+	key, err := getInventoryKey(scheme, obj)
+	if err != nil {
+		return err
+	}
+	inv.objects[*key] = struct{}{}
+	if label && obj.GetNamespace() == inv.owner.Namespace {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[inventoryLabel] = inv.owner.Name
+		obj.SetLabels(labels)
+	}
+	return nil
+}
+
+func getInventoryKey(scheme *runtime.Scheme, obj client.Object) (*inventoryKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryKey{kind: gvk.GroupKind(), name: obj.GetName()}, nil
+}
+
+// pruneResources deletes objects belonging to the Cryostat that were not part
+// of the inventory recorded during this reconcile, such as those of components
+// that have since been disabled
+func (r *CryostatReconciler) pruneResources(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
+	inv := inventoryFromContext(ctx)
+	if inv == nil {
+		return nil
+	}
+
+	lists := []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&corev1.ServiceList{},
+		&corev1.SecretList{},
+		&corev1.PersistentVolumeClaimList{},
+		&corev1.ServiceAccountList{},
+		&rbacv1.RoleList{},
+		&rbacv1.RoleBindingList{},
+		&netv1.NetworkPolicyList{},
+		&autoscalingv2beta2.HorizontalPodAutoscalerList{},
+		&policyv1beta1.PodDisruptionBudgetList{},
+	}
+	if r.IsOpenShift {
+		lists = append(lists, &openshiftv1.RouteList{})
+	} else {
+		lists = append(lists, &netv1.IngressList{})
+		available, err := r.gatewayAPIAvailable()
+		if err != nil {
+			return err
+		}
+		if available {
+			routes := &unstructured.UnstructuredList{}
+			routes.SetGroupVersionKind(httpRouteListGVK)
+			lists = append(lists, routes)
+		}
+	}
+	available, err := r.certManagerAvailable()
+	if err != nil {
+		return err
+	}
+	if available {
+		lists = append(lists, &certv1.CertificateList{}, &certv1.IssuerList{})
+	}
+
+	for _, list := range lists {
+		err := r.Client.List(ctx, list, client.InNamespace(cr.Namespace),
+			client.MatchingLabels{inventoryLabel: cr.Name})
+		if err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !metav1.IsControlledBy(obj, cr) {
+				continue
+			}
+			key, err := getInventoryKey(r.Scheme, obj)
+			if err != nil {
+				return err
+			}
+			if _, found := inv.objects[*key]; found {
+				continue
+			}
+			// Never delete a claim that still holds Cryostat's data
+			if _, active := obj.GetLabels()[resources.ActiveStorageLabel]; active {
+				if _, ok := obj.(*corev1.PersistentVolumeClaim); ok {
+					continue
+				}
+			}
+			err = r.Client.Delete(ctx, obj)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+			r.Log.Info(fmt.Sprintf("%s pruned", key.kind.Kind), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+	}
+	return nil
+}
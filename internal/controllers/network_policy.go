@@ -0,0 +1,145 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Label that OpenShift applies to the namespaces of its ingress controllers
+const openShiftIngressPolicyGroupLabel = "network.openshift.io/policy-group"
+
+// Namespace of the NGINX ingress controller, used as the default ingress
+// controller on Kubernetes
+const defaultIngressNamespace = "ingress-nginx"
+
+func (r *CryostatReconciler) createNetworkPolicies(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
+	if cr.Spec.NetworkPolicyOptions == nil || !cr.Spec.NetworkPolicyOptions.Enabled {
+		// Any existing NetworkPolicies are pruned once reconciled
+		return nil
+	}
+
+	policy := resources.NewCoreNetworkPolicy(cr, r.getIngressControllerPeers(cr),
+		getTargetPolicyPorts(cr.Spec.TargetDiscoveryOptions), getEnvOrDefault(r, operatorNamespaceEnv, cr.Namespace))
+	if err := r.createOrUpdateNetworkPolicy(ctx, policy, cr); err != nil {
+		return err
+	}
+	if resources.IsReportsEnabled(cr) {
+		policy = resources.NewReportsNetworkPolicy(cr)
+		if err := r.createOrUpdateNetworkPolicy(ctx, policy, cr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CryostatReconciler) createOrUpdateNetworkPolicy(ctx context.Context, policy *netv1.NetworkPolicy,
+	owner metav1.Object) error {
+	policyCopy := policy.DeepCopy()
+	op, err := r.createOrUpdate(ctx, policy, func() error {
+		if err := controllerutil.SetControllerReference(owner, policy, r.Scheme); err != nil {
+			return err
+		}
+		mergeLabelsAndAnnotations(&policy.ObjectMeta, policyCopy.Labels, policyCopy.Annotations)
+		// Update NetworkPolicy spec
+		policy.Spec = policyCopy.Spec
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("NetworkPolicy %s", op), "name", policy.Name, "namespace", policy.Namespace)
+	return nil
+}
+
+func (r *CryostatReconciler) getIngressControllerPeers(cr *operatorv1beta1.Cryostat) []netv1.NetworkPolicyPeer {
+	if len(cr.Spec.NetworkPolicyOptions.IngressControllerPeers) > 0 {
+		return cr.Spec.NetworkPolicyOptions.IngressControllerPeers
+	}
+	labels := map[string]string{resources.NamespaceNameLabel: defaultIngressNamespace}
+	if r.IsOpenShift {
+		labels = map[string]string{openShiftIngressPolicyGroupLabel: "ingress"}
+	}
+	return []netv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+}
+
+// getTargetPolicyPorts returns the ports that Cryostat connects to targets on,
+// based on the ports considered for target discovery
+func getTargetPolicyPorts(options *operatorv1beta1.TargetDiscoveryOptions) []netv1.NetworkPolicyPort {
+	jmxPortNames := []string{defaultJmxPortName}
+	agentPortNames := []string{defaultAgentPortName}
+	portNumbers := []int32{defaultJmxPort}
+	if options != nil {
+		if len(options.JMXPortNames) > 0 {
+			jmxPortNames = options.JMXPortNames
+		}
+		if len(options.AgentPortNames) > 0 {
+			agentPortNames = options.AgentPortNames
+		}
+		if len(options.JMXPortNumbers) > 0 {
+			portNumbers = options.JMXPortNumbers
+		}
+	}
+	portNames := append(append([]string{}, jmxPortNames...), agentPortNames...)
+
+	tcp := corev1.ProtocolTCP
+	ports := []netv1.NetworkPolicyPort{}
+	for _, name := range portNames {
+		port := intstr.FromString(name)
+		ports = append(ports, netv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+	}
+	for _, number := range portNumbers {
+		port := intstr.FromInt(int(number))
+		ports = append(ports, netv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+	}
+	return ports
+}
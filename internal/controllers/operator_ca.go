@@ -0,0 +1,298 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	// Validity period of the CA certificate generated by the operator
+	operatorCAValidity = 365 * 24 * time.Hour
+	// Validity period of the certificates issued by the operator's CA
+	operatorCertValidity = 90 * 24 * time.Hour
+	// Key within the Cryostat TLS secret containing the PKCS #12 keystore
+	keystoreKey = "keystore.p12"
+	// Key within the keystore secret containing the keystore password
+	keystorePassKey = "KEYSTORE_PASS"
+)
+
+// setupOperatorTLS issues certificates for Cryostat components using a CA managed
+// by the operator, as an alternative to cert-manager. The resulting secrets have
+// the same names and layout as those created by cert-manager. Certificates are
+// renewed when reconciled with less than a third of their lifetime remaining.
+// The returned time is when the first of these certificates is due for renewal.
+func (r *CryostatReconciler) setupOperatorTLS(ctx context.Context, cr *operatorv1beta1.Cryostat) (*resources.TLSConfig,
+	time.Time, error) {
+	// Remove any cert-manager resources from a previous configuration,
+	// so that cert-manager doesn't overwrite the operator's secrets
+	err := r.deleteCertManagerResources(ctx, cr)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// Create secret to hold keystore password
+	keystoreSecret := resources.NewKeystoreSecretForCR(cr)
+	err = r.createOrUpdateKeystoreSecret(ctx, keystoreSecret, cr)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	keystorePass, found := getSecretValue(keystoreSecret, keystorePassKey)
+	if !found {
+		return nil, time.Time{}, fmt.Errorf("secret %s is missing key %s", keystoreSecret.Name, keystorePassKey)
+	}
+
+	// Use the cert-manager definitions to describe the certificates to issue
+	caCert := resources.NewCryostatCACert(cr)
+	ca, err := r.createOrUpdateOperatorCA(ctx, cr, caCert.Spec.SecretName)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	renewal := common.RenewalTime(ca.Certificate)
+
+	cryostatCert := resources.NewCryostatCert(cr)
+	grafanaCert := resources.NewGrafanaCert(cr)
+	reportsCert := resources.NewReportsCert(cr)
+	keystorePasses := map[*certv1.Certificate]*string{
+		cryostatCert: &keystorePass,
+	}
+	for _, cert := range []*certv1.Certificate{cryostatCert, grafanaCert, reportsCert} {
+		issued, err := r.createOrUpdateOperatorCert(ctx, cr, ca, cert, keystorePasses[cert])
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if certRenewal := common.RenewalTime(issued); certRenewal.Before(renewal) {
+			renewal = certRenewal
+		}
+	}
+
+	return &resources.TLSConfig{
+		CryostatSecret:     cryostatCert.Spec.SecretName,
+		GrafanaSecret:      grafanaCert.Spec.SecretName,
+		ReportsSecret:      reportsCert.Spec.SecretName,
+		KeystorePassSecret: keystoreSecret.Name,
+	}, renewal, nil
+}
+
+func (r *CryostatReconciler) createOrUpdateOperatorCA(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	secretName string) (*common.KeyPair, error) {
+	secret := newOperatorTLSSecret(cr, secretName)
+	var ca *common.KeyPair
+	op, err := r.createOrUpdate(ctx, secret, func() error {
+		if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
+			return err
+		}
+		// Keep the existing CA until it is due for renewal
+		existing, err := common.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && existing.Certificate.IsCA && !common.IsRenewalDue(existing.Certificate, time.Now()) {
+			ca = existing
+			return nil
+		}
+
+		r.Log.Info("Generating CA certificate", "name", secret.Name, "namespace", secret.Namespace)
+		ca, err = common.NewCertificateAuthority(fmt.Sprintf("ca.%s.cryostat", cr.Name), operatorCAValidity)
+		if err != nil {
+			return err
+		}
+		setTLSSecretData(secret, ca, ca)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Log.Info(fmt.Sprintf("Secret %s", op), "name", secret.Name, "namespace", secret.Namespace)
+	return ca, nil
+}
+
+// createOrUpdateOperatorCert issues the certificate into its secret if needed,
+// and returns the certificate now stored in the secret
+func (r *CryostatReconciler) createOrUpdateOperatorCert(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	ca *common.KeyPair, cert *certv1.Certificate, keystorePass *string) (*x509.Certificate, error) {
+	secret := newOperatorTLSSecret(cr, cert.Spec.SecretName)
+	var current *x509.Certificate
+	op, err := r.createOrUpdate(ctx, secret, func() error {
+		if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
+			return err
+		}
+		if existing := getValidCert(secret, ca, cert, keystorePass); existing != nil {
+			current = existing
+			return nil
+		}
+
+		r.Log.Info("Issuing certificate", "name", secret.Name, "namespace", secret.Namespace)
+		issued, err := common.NewCertificate(ca, cert.Spec.CommonName, cert.Spec.DNSNames,
+			getExtKeyUsages(cert.Spec.Usages), operatorCertValidity)
+		if err != nil {
+			return err
+		}
+		setTLSSecretData(secret, issued, ca)
+		if keystorePass != nil {
+			keystore, err := pkcs12.Modern.Encode(issued.PrivateKey, issued.Certificate,
+				[]*x509.Certificate{ca.Certificate}, *keystorePass)
+			if err != nil {
+				return err
+			}
+			secret.Data[keystoreKey] = keystore
+		}
+		current = issued.Certificate
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Log.Info(fmt.Sprintf("Secret %s", op), "name", secret.Name, "namespace", secret.Namespace)
+	return current, nil
+}
+
+// getValidCert returns the certificate in the secret, or nil if it is missing, due for
+// renewal, not issued by the current CA, or no longer matches the desired certificate
+func getValidCert(secret *corev1.Secret, ca *common.KeyPair, cert *certv1.Certificate,
+	keystorePass *string) *x509.Certificate {
+	existing, err := common.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil
+	}
+	if common.IsRenewalDue(existing.Certificate, time.Now()) {
+		return nil
+	}
+	if existing.Certificate.CheckSignatureFrom(ca.Certificate) != nil ||
+		string(secret.Data[resources.CAKey]) != string(ca.CertPEM) {
+		return nil
+	}
+	if existing.Certificate.Subject.CommonName != cert.Spec.CommonName ||
+		!equalStringSets(existing.Certificate.DNSNames, cert.Spec.DNSNames) {
+		return nil
+	}
+	if keystorePass != nil {
+		// Ensure the keystore exists and can be opened with the current password
+		if _, _, _, err := pkcs12.DecodeChain(secret.Data[keystoreKey], *keystorePass); err != nil {
+			return nil
+		}
+	}
+	return existing.Certificate
+}
+
+func newOperatorTLSSecret(cr *operatorv1beta1.Cryostat, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func setTLSSecretData(secret *corev1.Secret, pair *common.KeyPair, ca *common.KeyPair) {
+	// The type of an existing secret cannot be changed
+	if len(secret.Type) == 0 {
+		secret.Type = corev1.SecretTypeTLS
+	}
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       pair.CertPEM,
+		corev1.TLSPrivateKeyKey: pair.KeyPEM,
+		resources.CAKey:         ca.CertPEM,
+	}
+}
+
+func getExtKeyUsages(usages []certv1.KeyUsage) []x509.ExtKeyUsage {
+	result := []x509.ExtKeyUsage{}
+	for _, usage := range usages {
+		switch usage {
+		case certv1.UsageServerAuth:
+			result = append(result, x509.ExtKeyUsageServerAuth)
+		case certv1.UsageClientAuth:
+			result = append(result, x509.ExtKeyUsageClientAuth)
+		}
+	}
+	return result
+}
+
+func equalStringSets(a []string, b []string) bool {
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
+
+// getSecretValue returns the value of a key within a secret, which may
+// only be present in StringData if the secret was just created
+func getSecretValue(secret *corev1.Secret, key string) (string, bool) {
+	if val, found := secret.Data[key]; found {
+		return string(val), true
+	}
+	val, found := secret.StringData[key]
+	return val, found
+}
+
+func (r *CryostatReconciler) deleteCertManagerResources(ctx context.Context, cr *operatorv1beta1.Cryostat) error {
+	available, err := r.certManagerAvailable()
+	if err != nil || !available {
+		return err
+	}
+	objs := []client.Object{
+		resources.NewCryostatCert(cr), resources.NewGrafanaCert(cr), resources.NewReportsCert(cr),
+		resources.NewCryostatCAIssuer(cr), resources.NewCryostatCACert(cr), resources.NewSelfSignedIssuer(cr),
+	}
+	for _, obj := range objs {
+		err := r.Client.Delete(ctx, obj)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		r.Log.Info(fmt.Sprintf("%s deleted", reflect.TypeOf(obj).Elem().Name()), "name", obj.GetName(),
+			"namespace", obj.GetNamespace())
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+// ProbeTemplateReconciler reconciles a ProbeTemplate object
+type ProbeTemplateReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	common.Reconciler
+}
+
+const probeTemplateFinalizer = "operator.cryostat.io/probetemplate.finalizer"
+
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=probetemplates,verbs=*
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=probetemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=operator.cryostat.io,resources=probetemplates/finalizers,verbs=update
+
+// Reconcile validates a ProbeTemplate and uploads it to Cryostat
+func (r *ProbeTemplateReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ProbeTemplate")
+
+	// Fetch the ProbeTemplate instance
+	template := &operatorv1beta1.ProbeTemplate{}
+	err := r.Client.Get(ctx, request.NamespacedName, template)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	sync := r.templateSync()
+
+	// Check if this ProbeTemplate is being deleted
+	if template.GetDeletionTimestamp() != nil {
+		return sync.finalize(ctx, template, template.Status.TemplateName)
+	}
+
+	// Add our finalizer, so we can remove the template from Cryostat when this ProbeTemplate is deleted
+	err = sync.addFinalizer(ctx, template)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Read and validate the template
+	content, ok, err := sync.getContent(ctx, template, &template.Status.Conditions, template.Spec.Template,
+		template.Spec.ConfigMapRef)
+	if !ok {
+		return reconcile.Result{}, err
+	}
+	info, err := parseProbeTemplate(content)
+	if err != nil {
+		reqLogger.Error(err, "invalid probe template")
+		return reconcile.Result{}, sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+			metav1.ConditionFalse, reasonTemplateInvalid, err.Error())
+	}
+
+	// Obtain a client configured to communicate with Cryostat
+	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, nil)
+	if err != nil {
+		return common.RequeueIfNotReady(r.Log, err)
+	}
+
+	// Check which probe templates Cryostat has, since it may have lost a template
+	// uploaded by a previous reconcile
+	probeTemplates, err := cryostat.ListProbeTemplates()
+	if err != nil {
+		reqLogger.Error(err, "failed to list probe templates in Cryostat")
+		return reconcile.Result{}, err
+	}
+	existing := map[string]bool{}
+	for _, other := range probeTemplates {
+		existing[other.Name] = true
+	}
+	uploaded := len(template.Status.TemplateName) > 0 && existing[template.Status.TemplateName]
+
+	// Upload the template if it is missing from Cryostat, or has changed since it was last uploaded
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+	if !uploaded || template.Status.ContentHash != hash ||
+		!meta.IsStatusConditionTrue(template.Status.Conditions, string(operatorv1beta1.ConditionTypeProbeTemplateAvailable)) {
+		// Don't replace a template in Cryostat that was not uploaded by this ProbeTemplate
+		if template.Status.TemplateName != template.Name && existing[template.Name] {
+			return reconcile.Result{}, sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+				metav1.ConditionFalse, reasonTemplateNameConflict,
+				fmt.Sprintf("Cryostat already has a probe template named %s", template.Name))
+		}
+
+		// Templates cannot be replaced in place, so remove the previous version first
+		if uploaded {
+			err = cryostat.DeleteProbeTemplate(template.Status.TemplateName)
+			if err != nil {
+				reqLogger.Error(err, "failed to remove previous probe template from Cryostat",
+					"template", template.Status.TemplateName)
+				return reconcile.Result{}, err
+			}
+		}
+
+		// Record the template name before uploading, so that the template is known to
+		// belong to this ProbeTemplate even if the upload cannot be recorded afterwards
+		template.Status.TemplateName = template.Name
+		template.Status.ContentHash = ""
+		err = r.Client.Status().Update(ctx, template)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		reqLogger.Info("uploading probe template to Cryostat")
+		err = cryostat.UploadProbeTemplate(template.Name, content)
+		if err != nil {
+			reqLogger.Error(err, "failed to upload probe template to Cryostat")
+			condErr := sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+				metav1.ConditionFalse, reasonTemplateUploadFailed, err.Error())
+			if condErr != nil {
+				return reconcile.Result{}, condErr
+			}
+			return reconcile.Result{}, err
+		}
+		template.Status.ContentHash = hash
+	}
+	template.Status.Events = info.eventIDs()
+
+	err = sync.setAvailableCondition(ctx, template, &template.Status.Conditions,
+		metav1.ConditionTrue, reasonTemplateUploaded,
+		fmt.Sprintf("Probe template %s is available in Cryostat", template.Name))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("ProbeTemplate successfully updated", "Namespace", request.Namespace, "Name", request.Name)
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ProbeTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1beta1.ProbeTemplate{}).
+		// Upload a new version of the template when the referenced ConfigMap changes
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.templateSync().configMapToTemplates)).
+		Complete(r)
+}
+
+func (r *ProbeTemplateReconciler) templateSync() *templateSync {
+	return &templateSync{
+		Client:        r.Client,
+		Reconciler:    r.Reconciler,
+		Log:           r.Log,
+		kind:          "ProbeTemplate",
+		finalizer:     probeTemplateFinalizer,
+		conditionType: string(operatorv1beta1.ConditionTypeProbeTemplateAvailable),
+		newList: func() client.ObjectList {
+			return &operatorv1beta1.ProbeTemplateList{}
+		},
+		getConfigMapRef: func(obj client.Object) *operatorv1beta1.TemplateConfigMap {
+			return obj.(*operatorv1beta1.ProbeTemplate).Spec.ConfigMapRef
+		},
+		deleteFromCryostat: func(cryostat cryostatClient.CryostatClient, templateName string) error {
+			return cryostat.DeleteProbeTemplate(templateName)
+		},
+	}
+}
+
+// probeTemplateInfo describes the root jfragent element of a JMC agent probe template
+type probeTemplateInfo struct {
+	XMLName xml.Name `xml:"jfragent"`
+	Events  []struct {
+		ID     string `xml:"id,attr"`
+		Label  string `xml:"label"`
+		Class  string `xml:"class"`
+		Method struct {
+			Name       string `xml:"name"`
+			Descriptor string `xml:"descriptor"`
+		} `xml:"method"`
+	} `xml:"events>event"`
+}
+
+func (info *probeTemplateInfo) eventIDs() []string {
+	ids := make([]string, 0, len(info.Events))
+	for _, event := range info.Events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+// parseProbeTemplate checks that the provided content is a well-formed
+// probe template that the JMC agent will accept
+func parseProbeTemplate(content []byte) (*probeTemplateInfo, error) {
+	info := &probeTemplateInfo{}
+	err := xml.NewDecoder(bytes.NewReader(content)).Decode(info)
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("template is empty")
+		}
+		return nil, fmt.Errorf("template is not a valid probe template: %s", err.Error())
+	}
+	if len(info.Events) == 0 {
+		return nil, errors.New("template must define at least one event")
+	}
+	ids := map[string]bool{}
+	for _, event := range info.Events {
+		if len(event.ID) == 0 {
+			return nil, errors.New("template events must have an id")
+		}
+		if ids[event.ID] {
+			return nil, fmt.Errorf("template defines event %s more than once", event.ID)
+		}
+		ids[event.ID] = true
+		if len(event.Class) == 0 || len(event.Method.Name) == 0 || len(event.Method.Descriptor) == 0 {
+			return nil, fmt.Errorf("event %s must specify a class, method name and method descriptor", event.ID)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,414 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type probeTemplateTestInput struct {
+	controller *controllers.ProbeTemplateReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("ProbeTemplateController", func() {
+	var t *probeTemplateTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.ProbeTemplateReconciler{
+			Client:     t.Client,
+			Scheme:     s,
+			Log:        logger,
+			Reconciler: test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		t = &probeTemplateTestInput{
+			objs: []runtime.Object{
+				test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewProbeTemplate(),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a new ProbeTemplate", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler(),
+					test.NewUploadProbeTemplateHandler("test-probes", test.ProbeTemplateContent),
+				}
+			})
+			It("should upload the template", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateStatus("test-probes", []string{"demo.jfr.test1"})
+			})
+			It("should add finalizer", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				template := t.getProbeTemplate()
+				Expect(template.Finalizers).To(ContainElement("operator.cryostat.io/probetemplate.finalizer"))
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("with a template in a ConfigMap", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					test.NewProbeTemplateFromConfigMap(), test.NewProbeTemplateConfigMap(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler(),
+					test.NewUploadProbeTemplateHandler("test-probes", test.ProbeTemplateContent),
+				}
+			})
+			It("should upload the template", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateStatus("test-probes", []string{"demo.jfr.test1"})
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("after ProbeTemplate already reconciled successfully", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler(),
+					test.NewUploadProbeTemplateHandler("test-probes", test.ProbeTemplateContent),
+					test.NewListProbeTemplatesHandler("test-probes"),
+				}
+			})
+			It("should not upload the template again", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateStatus("test-probes", []string{"demo.jfr.test1"})
+			})
+		})
+		Context("with changed template contents", func() {
+			var content string
+
+			BeforeEach(func() {
+				content = strings.Replace(test.ProbeTemplateContent, "demo.jfr.test1", "demo.jfr.test2", 1)
+				template := test.NewProbeTemplateUploaded()
+				template.Spec.Template = &content
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), template,
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler("test-probes"),
+					test.NewDeleteProbeTemplateHandler("test-probes"),
+					test.NewUploadProbeTemplateHandler("test-probes", content),
+				}
+			})
+			It("should replace the template", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateStatus("test-probes", []string{"demo.jfr.test2"})
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("with a template missing from Cryostat", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewProbeTemplateUploaded(),
+				}
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler(),
+					test.NewUploadProbeTemplateHandler("test-probes", test.ProbeTemplateContent),
+				}
+			})
+			It("should upload the template again", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateStatus("test-probes", []string{"demo.jfr.test1"})
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionTrue, "TemplateUploaded")
+			})
+		})
+		Context("with a template of the same name already in Cryostat", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler("test-probes"),
+				}
+			})
+			It("should not replace the template", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				Expect(t.getProbeTemplate().Status.TemplateName).To(BeEmpty())
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "TemplateNameConflict")
+			})
+		})
+		Context("with both an inline template and a ConfigMap", func() {
+			BeforeEach(func() {
+				template := test.NewProbeTemplateFromConfigMap()
+				content := test.ProbeTemplateContent
+				template.Spec.Template = &content
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					template, test.NewProbeTemplateConfigMap(),
+				}
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "InvalidSpec")
+			})
+		})
+		Context("with a missing ConfigMap", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(),
+					test.NewProbeTemplateFromConfigMap(),
+				}
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectProbeTemplateReconcileSuccess()
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "ConfigMapNotFound")
+			})
+		})
+		Context("with an invalid template", func() {
+			var content string
+
+			JustBeforeEach(func() {
+				err := t.Client.Delete(context.Background(), test.NewProbeTemplate())
+				Expect(err).ToNot(HaveOccurred())
+				err = t.Client.Create(context.Background(), test.NewProbeTemplateWithContent(content))
+				Expect(err).ToNot(HaveOccurred())
+			})
+			Context("that is not well-formed", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.ProbeTemplateContent, "</jfragent>", "", 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("without any events", func() {
+				BeforeEach(func() {
+					content = "<jfragent><events></events></jfragent>"
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("with an event missing its id", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.ProbeTemplateContent, ` id="demo.jfr.test1"`, "", 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("with an event missing its method", func() {
+				BeforeEach(func() {
+					content = strings.Replace(test.ProbeTemplateContent, "<name>printHelloWorldJFR1</name>", "", 1)
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+			Context("with a different root element", func() {
+				BeforeEach(func() {
+					content = `<configuration version="2.0" label="Custom"></configuration>`
+				})
+				It("should set TemplateAvailable condition", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "InvalidTemplate")
+				})
+			})
+		})
+		Context("upload fails", func() {
+			BeforeEach(func() {
+				t.handlers = []http.HandlerFunc{
+					test.NewListProbeTemplatesHandler(),
+					test.NewUploadProbeTemplateFailHandler("test-probes", test.ProbeTemplateContent),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectProbeTemplateReconcileError()
+			})
+			It("should set TemplateAvailable condition", func() {
+				t.expectProbeTemplateReconcileError()
+				t.checkProbeTemplateAvailableCondition(metav1.ConditionFalse, "UploadFailed")
+			})
+		})
+		Context("ProbeTemplate does not exist", func() {
+			It("should do nothing", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "does-not-exist", Namespace: "default"}}
+				result, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+		})
+		Context("Cryostat CR is missing", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCACert(), test.NewCryostatService(), test.NewProbeTemplate(),
+				}
+			})
+			It("should requeue with error", func() {
+				t.expectProbeTemplateReconcileError()
+			})
+		})
+		Context("deleting a ProbeTemplate", func() {
+			BeforeEach(func() {
+				t.objs = []runtime.Object{
+					test.NewCryostat(), test.NewCACert(), test.NewCryostatService(), test.NewDeletedProbeTemplate(),
+				}
+			})
+			Context("that is uploaded", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteProbeTemplateHandler("test-probes"),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.expectNoProbeTemplateFinalizer()
+				})
+			})
+			Context("that Cryostat no longer knows about", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewDeleteProbeTemplateNotFoundHandler("test-probes"),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.expectNoProbeTemplateFinalizer()
+				})
+			})
+			Context("after Cryostat is deleted", func() {
+				BeforeEach(func() {
+					t.objs = []runtime.Object{
+						test.NewCACert(), test.NewCryostatService(), test.NewDeletedProbeTemplate(),
+					}
+				})
+				It("should remove finalizer", func() {
+					t.expectProbeTemplateReconcileSuccess()
+					t.expectNoProbeTemplateFinalizer()
+				})
+			})
+		})
+	})
+})
+
+func (t *probeTemplateTestInput) expectProbeTemplateReconcileSuccess() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-probes", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *probeTemplateTestInput) expectProbeTemplateReconcileError() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-probes", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).To(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{}))
+}
+
+func (t *probeTemplateTestInput) getProbeTemplate() *operatorv1beta1.ProbeTemplate {
+	template := &operatorv1beta1.ProbeTemplate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-probes", Namespace: "default"}, template)
+	Expect(err).ToNot(HaveOccurred())
+	return template
+}
+
+func (t *probeTemplateTestInput) checkProbeTemplateStatus(templateName string, events []string) {
+	template := t.getProbeTemplate()
+	Expect(template.Status.TemplateName).To(Equal(templateName))
+	Expect(template.Status.Events).To(Equal(events))
+}
+
+func (t *probeTemplateTestInput) checkProbeTemplateAvailableCondition(status metav1.ConditionStatus, reason string) {
+	template := t.getProbeTemplate()
+	condition := meta.FindStatusCondition(template.Status.Conditions, string(operatorv1beta1.ConditionTypeProbeTemplateAvailable))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *probeTemplateTestInput) expectNoProbeTemplateFinalizer() {
+	template := &operatorv1beta1.ProbeTemplate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "test-probes", Namespace: "default"}, template)
+	if err != nil {
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		return
+	}
+	Expect(template.Finalizers).ToNot(ContainElement("operator.cryostat.io/probetemplate.finalizer"))
+}
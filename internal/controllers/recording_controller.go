@@ -53,7 +53,6 @@ import (
 
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
 	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
-	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -125,20 +124,15 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 		return r.requeueIfNotReady(err)
 	}
 
-	// Look up pod corresponding to this FlightRecorder object
+	// Look up target corresponding to this FlightRecorder object
 	targetRef := jfr.Status.Target
 	if targetRef == nil {
 		// FlightRecorder status must not have been updated yet
 		return reconcile.Result{RequeueAfter: time.Second}, nil
 	}
-	targetPod := &corev1.Pod{}
-	err = r.Client.Get(ctx, types.NamespacedName{Namespace: targetRef.Namespace, Name: targetRef.Name}, targetPod)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
 
-	// Get TargetAddress for the referenced pod and port number listed in FlightRecorder
-	targetAddr, err := r.GetPodTarget(targetPod, jfr.Status.Port)
+	// Get TargetAddress for the referenced pod or custom target listed in FlightRecorder
+	targetAddr, err := r.GetFlightRecorderTarget(ctx, jfr)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -183,7 +177,7 @@ func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 	}
 
 	// If the recording is found in Cryostat's list, update Recording.Status with the newest info
-	r.Log.Info("Looking for recordings for pod", "pod", targetPod.Name, "namespace", targetPod.Namespace)
+	r.Log.Info("Looking for recordings for target", "target", targetRef.Name, "namespace", targetRef.Namespace)
 	// Updated Download URL, use existing URL as default
 	downloadURL := instance.Status.DownloadURL
 	reportURL := instance.Status.ReportURL
@@ -0,0 +1,430 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// storageState describes how the Cryostat deployment should be scaled
+// while its persistent storage is migrated
+type storageState struct {
+	// Cryostat must be stopped so that its data can be copied to a new volume
+	scaleDown bool
+}
+
+type storageAction int
+
+const (
+	storageActionNone storageAction = iota
+	storageActionExpand
+	storageActionMigrate
+)
+
+const (
+	reasonStorageResizing          = "Resizing"
+	reasonStorageResized           = "Resized"
+	reasonStorageScalingDown       = "ScalingDown"
+	reasonStorageCopyingData       = "CopyingData"
+	reasonStorageMigrationComplete = "MigrationComplete"
+	reasonStorageMigrationFailed   = "MigrationFailed"
+	reasonStorageEmptyDirBlocked   = "EmptyDirBlocked"
+)
+
+const (
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	defaultStorageClassBetaAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// reconcileStorage creates or updates the persistent volume claim used by Cryostat.
+// Increases in requested storage are applied to the existing claim, if supported
+// by its StorageClass. Other changes are applied by migrating Cryostat's data
+// to a new claim.
+func (r *CryostatReconciler) reconcileStorage(ctx context.Context, cr *operatorv1beta1.Cryostat) (*storageState, error) {
+	// If the status no longer records the claim in use, look for it using its label,
+	// so that a claim that Cryostat's data was migrated to is not replaced
+	if cr.Status.Storage == nil || len(cr.Status.Storage.ClaimName) == 0 {
+		claimName, err := r.findActiveStorageClaim(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		if len(claimName) > 0 {
+			setStorageClaimName(cr, claimName)
+		}
+	}
+
+	if resources.IsEmptyDirRequested(cr) {
+		return r.reconcileEmptyDir(ctx, cr)
+	}
+
+	desired := resources.NewPersistentVolumeClaimForCR(cr)
+	existing := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	action := storageActionNone
+	if err == nil {
+		action, err = r.getStorageAction(ctx, existing, desired)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if action == storageActionMigrate {
+		return r.migrateStorage(ctx, cr, desired)
+	}
+	pvc := desired.DeepCopy()
+	if err := r.createOrUpdatePVC(ctx, pvc, cr, action == storageActionExpand); err != nil {
+		return nil, err
+	}
+
+	setStorageClaimName(cr, pvc.Name)
+	// Any migration is no longer needed, since the current claim matches the spec
+	if isStorageMigrating(cr) {
+		removeConditionIfPresent(cr, operatorv1beta1.ConditionTypeStorageMigrated)
+	}
+	updateStorageResizedCondition(cr, pvc)
+	return &storageState{}, r.Client.Status().Update(ctx, cr)
+}
+
+// reconcileEmptyDir switches Cryostat to an EmptyDir once it no longer has a persistent
+// volume claim holding its data. An EmptyDir belongs to the pod using it, so Cryostat's
+// data cannot be copied into one. Instead, the claim is kept until the user deletes it.
+func (r *CryostatReconciler) reconcileEmptyDir(ctx context.Context, cr *operatorv1beta1.Cryostat) (*storageState, error) {
+	if cr.Status.Storage != nil && len(cr.Status.Storage.ClaimName) > 0 {
+		claimName := cr.Status.Storage.ClaimName
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: claimName, Namespace: cr.Namespace}, pvc)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil && pvc.DeletionTimestamp == nil {
+			// Keep the claim as part of the desired state, so that it is not pruned
+			if err := r.createOrUpdatePVC(ctx, resources.NewPersistentVolumeClaimForCR(cr), cr, false); err != nil {
+				return nil, err
+			}
+			setStatusCondition(cr, metav1.Condition{
+				Type:   string(operatorv1beta1.ConditionTypeStorageMigrated),
+				Status: metav1.ConditionFalse,
+				Reason: reasonStorageEmptyDirBlocked,
+				Message: fmt.Sprintf("Cryostat's data cannot be moved to an EmptyDir, so it continues to use "+
+					"PersistentVolumeClaim %s. Back up any data to keep using a CryostatBackup, then delete "+
+					"PersistentVolumeClaim %s to switch to an EmptyDir.", claimName, claimName),
+			})
+			return &storageState{}, r.Client.Status().Update(ctx, cr)
+		}
+	}
+
+	// The claim has been deleted, so nothing remains to be lost
+	cr.Status.Storage = nil
+	removeConditionIfPresent(cr, operatorv1beta1.ConditionTypeStorageResized, operatorv1beta1.ConditionTypeStorageMigrated,
+		operatorv1beta1.ConditionTypeStorageNearlyFull)
+	return &storageState{}, r.Client.Status().Update(ctx, cr)
+}
+
+// findActiveStorageClaim returns the name of the persistent volume claim labeled as
+// mounted by Cryostat, or an empty string if there is none
+func (r *CryostatReconciler) findActiveStorageClaim(ctx context.Context, cr *operatorv1beta1.Cryostat) (string, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	err := r.Client.List(ctx, pvcs, client.InNamespace(cr.Namespace),
+		client.MatchingLabels{resources.ActiveStorageLabel: cr.Name})
+	if err != nil {
+		return "", err
+	}
+	var active *corev1.PersistentVolumeClaim
+	for i, pvc := range pvcs.Items {
+		if !metav1.IsControlledBy(&pvcs.Items[i], cr) || pvc.DeletionTimestamp != nil {
+			continue
+		}
+		// The claim migrated to is labeled before the previous claim is deleted,
+		// so prefer the newest claim if both are present
+		if active == nil || active.CreationTimestamp.Before(&pvc.CreationTimestamp) {
+			active = &pvcs.Items[i]
+		}
+	}
+	if active == nil {
+		return "", nil
+	}
+	return active.Name, nil
+}
+
+// getStorageAction compares the existing persistent volume claim against the desired
+// claim, and determines how to apply any differences between them
+func (r *CryostatReconciler) getStorageAction(ctx context.Context, existing *corev1.PersistentVolumeClaim,
+	desired *corev1.PersistentVolumeClaim) (storageAction, error) {
+	// The storage class, access modes and volume mode of a claim are immutable
+	if desired.Spec.StorageClassName != nil && !equality.Semantic.DeepEqual(desired.Spec.StorageClassName, existing.Spec.StorageClassName) {
+		return storageActionMigrate, nil
+	}
+	if !equality.Semantic.DeepEqual(desired.Spec.AccessModes, existing.Spec.AccessModes) {
+		return storageActionMigrate, nil
+	}
+	if desired.Spec.VolumeMode != nil && !equality.Semantic.DeepEqual(desired.Spec.VolumeMode, existing.Spec.VolumeMode) {
+		return storageActionMigrate, nil
+	}
+
+	desiredSize := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+	existingSize := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch desiredSize.Cmp(existingSize) {
+	case 0:
+		return storageActionNone, nil
+	case -1:
+		// Persistent volumes cannot be shrunk
+		return storageActionMigrate, nil
+	}
+
+	// Only expand the claim in place if its StorageClass allows it
+	expandable, err := r.isStorageExpandable(ctx, existing)
+	if err != nil {
+		return storageActionNone, err
+	}
+	if expandable {
+		return storageActionExpand, nil
+	}
+	return storageActionMigrate, nil
+}
+
+func (r *CryostatReconciler) isStorageExpandable(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	var class *storagev1.StorageClass
+	if pvc.Spec.StorageClassName == nil {
+		// The claim was created before a default StorageClass was assigned to it
+		classes := &storagev1.StorageClassList{}
+		err := r.Client.List(ctx, classes)
+		if err != nil {
+			return false, err
+		}
+		for i, sc := range classes.Items {
+			if sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[defaultStorageClassBetaAnnotation] == "true" {
+				class = &classes.Items[i]
+				break
+			}
+		}
+	} else if len(*pvc.Spec.StorageClassName) > 0 {
+		class = &storagev1.StorageClass{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, class)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	return class != nil && class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion, nil
+}
+
+// migrateStorage copies Cryostat's data to a new persistent volume claim matching
+// the desired spec. Cryostat is stopped during the copy, and then started again
+// using the new claim.
+func (r *CryostatReconciler) migrateStorage(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	desired *corev1.PersistentVolumeClaim) (*storageState, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", cr.Namespace, "Request.Name", cr.Name)
+	targetName, err := resources.GetMigrationClaimName(cr, &desired.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &batchv1.Job{}
+	jobName := targetName + "-migration"
+	err = r.Client.Get(ctx, types.NamespacedName{Name: jobName, Namespace: cr.Namespace}, job)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+	jobExists := err == nil
+	succeeded, failure := false, ""
+	if jobExists {
+		succeeded, failure = getJobResult(job)
+	}
+
+	// Only label the new claim as active once Cryostat's data has been copied to it
+	target := desired.DeepCopy()
+	target.Name = targetName
+	if !succeeded {
+		delete(target.Labels, resources.ActiveStorageLabel)
+	}
+	if err := r.createOrUpdatePVC(ctx, target, cr, false); err != nil {
+		return nil, err
+	}
+	if succeeded {
+		// Switch Cryostat over to the new claim. The previous claim no longer holds
+		// Cryostat's current data, and is unlabeled so that it can be pruned.
+		if err := r.unlabelActiveStorage(ctx, cr, desired.Name); err != nil {
+			return nil, err
+		}
+		setStorageClaimName(cr, targetName)
+		setStatusCondition(cr, metav1.Condition{
+			Type:    string(operatorv1beta1.ConditionTypeStorageMigrated),
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonStorageMigrationComplete,
+			Message: fmt.Sprintf("Cryostat's data was migrated to PersistentVolumeClaim %s.", targetName),
+		})
+		removeConditionIfPresent(cr, operatorv1beta1.ConditionTypeStorageResized)
+		if err := r.Client.Status().Update(ctx, cr); err != nil {
+			return nil, err
+		}
+		reqLogger.Info("Storage migration complete", "claim", targetName)
+		err = r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		return &storageState{}, nil
+	}
+
+	// Keep the current claim up to date until Cryostat is switched over
+	if err := r.createOrUpdatePVC(ctx, desired.DeepCopy(), cr, false); err != nil {
+		return nil, err
+	}
+	if len(failure) > 0 {
+		// Leave Cryostat running on its current claim
+		setStatusCondition(cr, metav1.Condition{
+			Type:   string(operatorv1beta1.ConditionTypeStorageMigrated),
+			Status: metav1.ConditionFalse,
+			Reason: reasonStorageMigrationFailed,
+			Message: fmt.Sprintf("Failed to migrate Cryostat's data to PersistentVolumeClaim %s: %s. "+
+				"Delete Job %s to retry.", targetName, failure, jobName),
+		})
+		return &storageState{}, r.Client.Status().Update(ctx, cr)
+	} else if jobExists {
+		// The Job is still running
+		return r.updateStorageMigratedCondition(ctx, cr, reasonStorageCopyingData,
+			fmt.Sprintf("Copying Cryostat's data to PersistentVolumeClaim %s.", targetName))
+	}
+
+	// Stop Cryostat before copying its data, so that it is not modified during the copy
+	deploy := &appsv1.Deployment{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, deploy)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && (deploy.Spec.Replicas == nil || *deploy.Spec.Replicas > 0 || deploy.Status.Replicas > 0) {
+		return r.updateStorageMigratedCondition(ctx, cr, reasonStorageScalingDown,
+			"Scaling down Cryostat to migrate its data.")
+	}
+
+	fsGroup, err := getFSGroup(ctx, r.Client, r.IsOpenShift, cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	image := getEnvOrDefault(r, coreImageTagEnv, resources.DefaultCoreImageTag)
+	job = resources.NewJobForStorageMigration(cr, targetName, image, *fsGroup)
+	if err := getOrCreateStorageJob(ctx, r.Client, r.Scheme, r.Log, cr, job); err != nil {
+		return nil, err
+	}
+	return r.updateStorageMigratedCondition(ctx, cr, reasonStorageCopyingData,
+		fmt.Sprintf("Copying Cryostat's data to PersistentVolumeClaim %s.", targetName))
+}
+
+func (r *CryostatReconciler) unlabelActiveStorage(ctx context.Context, cr *operatorv1beta1.Cryostat, claimName string) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: claimName, Namespace: cr.Namespace}, pvc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if _, found := pvc.Labels[resources.ActiveStorageLabel]; !found {
+		return nil
+	}
+	delete(pvc.Labels, resources.ActiveStorageLabel)
+	return r.Client.Update(ctx, pvc)
+}
+
+func (r *CryostatReconciler) updateStorageMigratedCondition(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	reason string, message string) (*storageState, error) {
+	setStatusCondition(cr, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeStorageMigrated),
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	return &storageState{scaleDown: true}, r.Client.Status().Update(ctx, cr)
+}
+
+// setStorageClaimName records the persistent volume claim mounted by Cryostat,
+// discarding any usage measured on a previous claim
+func setStorageClaimName(cr *operatorv1beta1.Cryostat, claimName string) {
+	if cr.Status.Storage == nil || cr.Status.Storage.ClaimName != claimName {
+		cr.Status.Storage = &operatorv1beta1.StorageStatus{ClaimName: claimName}
+	}
+}
+
+func isStorageMigrating(cr *operatorv1beta1.Cryostat) bool {
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageMigrated))
+	return condition != nil && (condition.Reason == reasonStorageScalingDown || condition.Reason == reasonStorageCopyingData)
+}
+
+// updateStorageResizedCondition reports the progress of an expansion of the provided
+// persistent volume claim
+func updateStorageResizedCondition(cr *operatorv1beta1.Cryostat, pvc *corev1.PersistentVolumeClaim) {
+	capacity, bound := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !bound {
+		return
+	}
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if capacity.Cmp(request) < 0 {
+		setStatusCondition(cr, metav1.Condition{
+			Type:   string(operatorv1beta1.ConditionTypeStorageResized),
+			Status: metav1.ConditionFalse,
+			Reason: reasonStorageResizing,
+			Message: fmt.Sprintf("Expanding PersistentVolumeClaim %s from %s to %s.", pvc.Name,
+				capacity.String(), request.String()),
+		})
+	} else if meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageResized)) != nil {
+		setStatusCondition(cr, metav1.Condition{
+			Type:    string(operatorv1beta1.ConditionTypeStorageResized),
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonStorageResized,
+			Message: fmt.Sprintf("PersistentVolumeClaim %s has a capacity of %s.", pvc.Name, capacity.String()),
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+)
+
+// StorageMonitorReconciler periodically measures how much of a Cryostat's
+// Persistent Volume Claim is used by archived recordings
+type StorageMonitorReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	common.Reconciler
+}
+
+const (
+	defaultStorageWarningThresholdPercent  int32 = 80
+	defaultStorageCriticalThresholdPercent int32 = 95
+	defaultStorageMonitoringInterval             = 5 * time.Minute
+)
+
+const (
+	reasonStorageBelowThreshold     = "UsageBelowThreshold"
+	reasonStorageAboveWarningLevel  = "UsageAboveWarningThreshold"
+	reasonStorageAboveCriticalLevel = "UsageAboveCriticalThreshold"
+	eventStorageNearlyFullType      = "StorageNearlyFull"
+	storageUsageLevelBelowThreshold = 0
+	storageUsageLevelAboveWarning   = 1
+	storageUsageLevelAboveCritical  = 2
+)
+
+// Reconcile compares the size of the recordings archived by Cryostat with the
+// capacity of its Persistent Volume Claim, and reports the result in the Cryostat's status
+func (r *StorageMonitorReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Measuring Cryostat storage usage")
+
+	// Fetch the Cryostat instance
+	cr := &operatorv1beta1.Cryostat{}
+	err := r.Client.Get(ctx, request.NamespacedName, cr)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+	if cr.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// Cryostat has no persistent storage to monitor if it uses an EmptyDir
+	if resources.UsesEmptyDir(cr) {
+		if meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageNearlyFull)) != nil {
+			removeConditionIfPresent(cr, operatorv1beta1.ConditionTypeStorageNearlyFull)
+			return reconcile.Result{}, r.Client.Status().Update(ctx, cr)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Status updates don't trigger a reconcile, so check again for the claim
+	// once the Cryostat controller has recorded it
+	if cr.Status.Storage == nil || len(cr.Status.Storage.ClaimName) == 0 {
+		reqLogger.Info("Waiting for Cryostat's storage to be created")
+		return reconcile.Result{RequeueAfter: storageOperationRequeueDelay}, nil
+	}
+
+	// Wait for Cryostat to be available before querying it
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeMainDeploymentAvailable)) {
+		reqLogger.Info("Waiting for Cryostat to become available")
+		return reconcile.Result{RequeueAfter: storageOperationRequeueDelay}, nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: cr.Status.Storage.ClaimName, Namespace: cr.Namespace}, pvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	capacity, bound := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !bound {
+		reqLogger.Info("Waiting for PersistentVolumeClaim to be bound", "name", pvc.Name)
+		return reconcile.Result{RequeueAfter: storageOperationRequeueDelay}, nil
+	}
+
+	// Obtain a client configured to communicate with Cryostat
+	cryostat, err := r.GetCryostatClient(ctx, request.Namespace, nil)
+	if err != nil {
+		return common.RequeueIfNotReady(r.Log, err)
+	}
+	saved, err := cryostat.ListSavedRecordings()
+	if err != nil {
+		reqLogger.Error(err, "failed to list archived recordings")
+		return reconcile.Result{}, err
+	}
+	used := int64(0)
+	for _, recording := range saved {
+		used += recording.Size
+	}
+
+	err = r.updateStorageUsage(ctx, cr, pvc.Name, capacity.Value(), used)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: getStorageMonitoringInterval(cr)}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Storage is measured periodically, so status updates don't need to trigger a reconcile
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("storagemonitor").
+		For(&operatorv1beta1.Cryostat{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *StorageMonitorReconciler) updateStorageUsage(ctx context.Context, cr *operatorv1beta1.Cryostat,
+	claimName string, capacity int64, used int64) error {
+	available := capacity - used
+	if available < 0 {
+		available = 0
+	}
+	storage := cr.Status.Storage.DeepCopy()
+	cr.Status.Storage.CapacityBytes = &capacity
+	cr.Status.Storage.UsedBytes = &used
+	cr.Status.Storage.AvailableBytes = &available
+
+	// Compare the usage against the configured thresholds
+	percent := int32(100)
+	if capacity > 0 {
+		percent = int32(used * 100 / capacity)
+	}
+	warning, critical := getStorageThresholds(cr)
+	level := storageUsageLevelBelowThreshold
+	status := metav1.ConditionTrue
+	var reason, message string
+	usage := fmt.Sprintf("Archived recordings use %s (%d%%) of the %s PersistentVolumeClaim %s", formatBytes(used), percent,
+		formatBytes(capacity), claimName)
+	if percent >= critical {
+		level = storageUsageLevelAboveCritical
+		reason = reasonStorageAboveCriticalLevel
+		message = fmt.Sprintf("%s, above the critical threshold of %d%%.", usage, critical)
+	} else if percent >= warning {
+		level = storageUsageLevelAboveWarning
+		reason = reasonStorageAboveWarningLevel
+		message = fmt.Sprintf("%s, above the warning threshold of %d%%.", usage, warning)
+	} else {
+		status = metav1.ConditionFalse
+		reason = reasonStorageBelowThreshold
+		message = fmt.Sprintf("%s, below the warning threshold of %d%%.", usage, warning)
+	}
+
+	// Emit an Event each time usage crosses a higher threshold
+	previous := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageNearlyFull))
+	previousLevel := storageUsageLevelBelowThreshold
+	if previous != nil {
+		previousLevel = getStorageUsageLevel(previous.Reason)
+	}
+	if level > previousLevel {
+		r.EventRecorder.Event(cr, corev1.EventTypeWarning, eventStorageNearlyFullType, message)
+	}
+
+	if previous != nil && previous.Status == status && previous.Reason == reason &&
+		equality.Semantic.DeepEqual(storage, cr.Status.Storage) {
+		// Nothing has changed since the last measurement
+		return nil
+	}
+	setStatusCondition(cr, metav1.Condition{
+		Type:    string(operatorv1beta1.ConditionTypeStorageNearlyFull),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	err := r.Client.Status().Update(ctx, cr)
+	if err != nil {
+		r.Log.Error(err, "failed to update storage usage", "namespace", cr.Namespace, "name", cr.Name)
+	}
+	return err
+}
+
+func getStorageUsageLevel(reason string) int {
+	switch reason {
+	case reasonStorageAboveCriticalLevel:
+		return storageUsageLevelAboveCritical
+	case reasonStorageAboveWarningLevel:
+		return storageUsageLevelAboveWarning
+	default:
+		return storageUsageLevelBelowThreshold
+	}
+}
+
+func getStorageThresholds(cr *operatorv1beta1.Cryostat) (warning int32, critical int32) {
+	warning = defaultStorageWarningThresholdPercent
+	critical = defaultStorageCriticalThresholdPercent
+	config := getStorageMonitoringConfig(cr)
+	if config != nil && config.WarningThresholdPercent != nil {
+		warning = *config.WarningThresholdPercent
+	}
+	if config != nil && config.CriticalThresholdPercent != nil {
+		critical = *config.CriticalThresholdPercent
+	}
+	return warning, critical
+}
+
+func getStorageMonitoringInterval(cr *operatorv1beta1.Cryostat) time.Duration {
+	config := getStorageMonitoringConfig(cr)
+	if config != nil && config.Interval != nil && config.Interval.Duration > 0 {
+		return config.Interval.Duration
+	}
+	return defaultStorageMonitoringInterval
+}
+
+func getStorageMonitoringConfig(cr *operatorv1beta1.Cryostat) *operatorv1beta1.StorageMonitoringConfiguration {
+	if cr.Spec.StorageOptions == nil {
+		return nil
+	}
+	return cr.Spec.StorageOptions.Monitoring
+}
+
+func formatBytes(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
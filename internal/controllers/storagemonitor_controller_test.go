@@ -0,0 +1,333 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const mebibyte = int64(1024 * 1024)
+
+type storageMonitorTestInput struct {
+	controller *controllers.StorageMonitorReconciler
+	objs       []runtime.Object
+	handlers   []http.HandlerFunc
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("StorageMonitorController", func() {
+	var t *storageMonitorTestInput
+
+	JustBeforeEach(func() {
+		logger := zap.New()
+		logf.SetLogger(logger)
+		s := test.NewTestScheme()
+
+		t.Client = fake.NewFakeClientWithScheme(s, t.objs...)
+		t.Server = test.NewServer(t.Client, t.handlers, t.TLS)
+		t.controller = &controllers.StorageMonitorReconciler{
+			Client:        t.Client,
+			Scheme:        s,
+			Log:           logger,
+			EventRecorder: record.NewFakeRecorder(1024),
+			Reconciler:    test.NewTestReconciler(&t.TestReconcilerConfig),
+		}
+	})
+
+	JustAfterEach(func() {
+		t.Server.VerifyRequestsReceived(t.handlers)
+		t.Server.Close()
+	})
+
+	BeforeEach(func() {
+		t = &storageMonitorTestInput{
+			objs: []runtime.Object{
+				test.NewCACert(), test.NewCryostatService(), test.NewBoundPVC("500Mi"),
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				TLS: true,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		// Reset test inputs
+		t = nil
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with usage below the thresholds", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostat())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(60*mebibyte, 40*mebibyte)),
+				}
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(5 * time.Minute)
+			})
+			It("should report the storage usage", func() {
+				t.checkStorageUsage(500*mebibyte, 100*mebibyte, 400*mebibyte)
+			})
+			It("should set the StorageNearlyFull condition to false", func() {
+				t.checkNearlyFullCondition(metav1.ConditionFalse, "UsageBelowThreshold")
+			})
+			It("should not emit an Event", func() {
+				t.expectNoEvent()
+			})
+		})
+		Context("with usage above the warning threshold", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostat())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(400*mebibyte, 20*mebibyte)),
+				}
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(5 * time.Minute)
+			})
+			It("should report the storage usage", func() {
+				t.checkStorageUsage(500*mebibyte, 420*mebibyte, 80*mebibyte)
+			})
+			It("should set the StorageNearlyFull condition", func() {
+				t.checkNearlyFullCondition(metav1.ConditionTrue, "UsageAboveWarningThreshold")
+			})
+			It("should emit a Warning Event", func() {
+				t.expectEvent("Warning StorageNearlyFull Archived recordings use 420Mi (84%) of the 500Mi " +
+					"PersistentVolumeClaim cryostat, above the warning threshold of 80%.")
+			})
+		})
+		Context("with usage above the critical threshold", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostatNearlyFull())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(490 * mebibyte)),
+				}
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(5 * time.Minute)
+			})
+			It("should set the StorageNearlyFull condition", func() {
+				t.checkNearlyFullCondition(metav1.ConditionTrue, "UsageAboveCriticalThreshold")
+			})
+			It("should emit a Warning Event", func() {
+				t.expectEvent("Warning StorageNearlyFull Archived recordings use 490Mi (98%) of the 500Mi " +
+					"PersistentVolumeClaim cryostat, above the critical threshold of 95%.")
+			})
+		})
+		Context("with usage still above the warning threshold", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostatNearlyFull())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(430 * mebibyte)),
+				}
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(5 * time.Minute)
+			})
+			It("should update the storage usage", func() {
+				t.checkStorageUsage(500*mebibyte, 430*mebibyte, 70*mebibyte)
+			})
+			It("should not emit another Event", func() {
+				t.expectNoEvent()
+			})
+		})
+		Context("with usage above the capacity", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostat())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(300*mebibyte, 300*mebibyte)),
+				}
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(5 * time.Minute)
+			})
+			It("should report no available storage", func() {
+				t.checkStorageUsage(500*mebibyte, 600*mebibyte, 0)
+			})
+		})
+		Context("with custom thresholds and interval", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostatWithThresholds())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(300 * mebibyte)),
+				}
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(time.Minute)
+			})
+			It("should set the StorageNearlyFull condition", func() {
+				t.checkNearlyFullCondition(metav1.ConditionTrue, "UsageAboveWarningThreshold")
+			})
+		})
+		Context("with Cryostat unavailable", func() {
+			BeforeEach(func() {
+				cr := test.NewMonitoredCryostat()
+				cr.Status.Conditions[0].Status = metav1.ConditionFalse
+				t.objs = append(t.objs, cr)
+			})
+			It("should requeue without contacting Cryostat", func() {
+				t.expectStorageMonitorReconcile(10 * time.Second)
+				Expect(t.getMonitoredCryostat().Status.Storage.UsedBytes).To(BeNil())
+			})
+		})
+		Context("with no storage recorded in the status", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewCryostat())
+			})
+			JustBeforeEach(func() {
+				t.expectStorageMonitorReconcile(10 * time.Second)
+			})
+			It("should requeue without contacting Cryostat", func() {
+				Expect(t.getMonitoredCryostat().Status.Storage).To(BeNil())
+			})
+			Context("once the storage is recorded", func() {
+				BeforeEach(func() {
+					t.handlers = []http.HandlerFunc{
+						test.NewListSavedNoJMXAuthHandler(test.NewSavedRecordingsWithSizes(60*mebibyte, 40*mebibyte)),
+					}
+				})
+				JustBeforeEach(func() {
+					t.setMonitoredStatus()
+					t.expectStorageMonitorReconcile(5 * time.Minute)
+				})
+				It("should report the storage usage", func() {
+					t.checkStorageUsage(500*mebibyte, 100*mebibyte, 400*mebibyte)
+				})
+			})
+		})
+		Context("with an EmptyDir", func() {
+			BeforeEach(func() {
+				cr := test.NewCryostatWithDefaultEmptyDir()
+				cr.Status.Conditions = []metav1.Condition{
+					{
+						Type:   string(operatorv1beta1.ConditionTypeStorageNearlyFull),
+						Status: metav1.ConditionTrue,
+						Reason: "UsageAboveWarningThreshold",
+					},
+				}
+				t.objs = append(t.objs, cr)
+			})
+			It("should remove the StorageNearlyFull condition", func() {
+				t.expectStorageMonitorReconcile(0)
+				cr := t.getMonitoredCryostat()
+				Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageNearlyFull))).To(BeNil())
+			})
+		})
+		Context("when Cryostat fails to list archived recordings", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, test.NewMonitoredCryostat())
+				t.handlers = []http.HandlerFunc{
+					test.NewListSavedNoJMXAuthFailHandler(test.NewSavedRecordings()),
+				}
+			})
+			It("should return an error", func() {
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+				_, err := t.controller.Reconcile(context.Background(), req)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
+
+func (t *storageMonitorTestInput) expectStorageMonitorReconcile(requeueAfter time.Duration) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cryostat", Namespace: "default"}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(reconcile.Result{RequeueAfter: requeueAfter}))
+}
+
+func (t *storageMonitorTestInput) setMonitoredStatus() {
+	cr := t.getMonitoredCryostat()
+	cr.Status = test.NewMonitoredCryostat().Status
+	err := t.Client.Status().Update(context.Background(), cr)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (t *storageMonitorTestInput) getMonitoredCryostat() *operatorv1beta1.Cryostat {
+	cr := &operatorv1beta1.Cryostat{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cryostat", Namespace: "default"}, cr)
+	Expect(err).ToNot(HaveOccurred())
+	return cr
+}
+
+func (t *storageMonitorTestInput) checkStorageUsage(capacity int64, used int64, available int64) {
+	storage := t.getMonitoredCryostat().Status.Storage
+	Expect(storage).ToNot(BeNil())
+	Expect(storage.ClaimName).To(Equal("cryostat"))
+	Expect(storage.CapacityBytes).To(Equal(&capacity))
+	Expect(storage.UsedBytes).To(Equal(&used))
+	Expect(storage.AvailableBytes).To(Equal(&available))
+}
+
+func (t *storageMonitorTestInput) checkNearlyFullCondition(status metav1.ConditionStatus, reason string) {
+	cr := t.getMonitoredCryostat()
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta1.ConditionTypeStorageNearlyFull))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *storageMonitorTestInput) expectEvent(expected string) {
+	recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+	var eventMsg string
+	Expect(recorder.Events).To(Receive(&eventMsg))
+	Expect(eventMsg).To(Equal(expected))
+}
+
+func (t *storageMonitorTestInput) expectNoEvent() {
+	recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+	Expect(recorder.Events).ToNot(Receive())
+}
@@ -0,0 +1,236 @@
+// Copyright The Cryostat Authors
+//
+// The Universal Permissive License (UPL), Version 1.0
+//
+// Subject to the condition set forth below, permission is hereby granted to any
+// person obtaining a copy of this software, associated documentation and/or data
+// (collectively the "Software"), free of charge and under any and all copyright
+// rights in the Software, and any and all patent rights owned or freely
+// licensable by each licensor hereunder covering either (i) the unmodified
+// Software as contributed to or provided by such licensor, or (ii) the Larger
+// Works (as defined below), to deal in both
+//
+// (a) the Software, and
+// (b) any piece of software and/or hardware listed in the lrgrwrks.txt file if
+// one is included with the Software (each a "Larger Work" to which the Software
+// is contributed by such licensors),
+//
+// without restriction, including without limitation the rights to copy, create
+// derivative works of, display, perform, and distribute the Software and make,
+// use, sell, offer for sale, import, export, have made, and have sold the
+// Software and the Larger Work(s), and to sublicense the foregoing rights on
+// either these or other terms.
+//
+// This license is subject to the following condition:
+// The above copyright notice and either this complete permission notice or at
+// a minimum a reference to the UPL must be included in all copies or
+// substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+)
+
+const (
+	reasonTemplateUploaded          = "TemplateUploaded"
+	reasonTemplateInvalidSpec       = "InvalidSpec"
+	reasonTemplateConfigMapNotFound = "ConfigMapNotFound"
+	reasonTemplateInvalid           = "InvalidTemplate"
+	reasonTemplateNameConflict      = "TemplateNameConflict"
+	reasonTemplateUploadFailed      = "UploadFailed"
+)
+
+var (
+	errTemplateInvalidSpec = errors.New("exactly one of template or configMapRef must be specified")
+	errTemplateKeyNotFound = errors.New("template file not found in ConfigMap")
+)
+
+// templateSync contains the logic shared by controllers that upload a template,
+// provided inline or in a ConfigMap, to the Cryostat in the same namespace
+type templateSync struct {
+	client.Client
+	common.Reconciler
+	Log logr.Logger
+	// Kind of the template objects, used in log messages
+	kind string
+	// Finalizer used to remove the template from Cryostat
+	finalizer string
+	// Condition type reporting whether the template is available in Cryostat
+	conditionType string
+	// Returns an empty list of the template objects
+	newList func() client.ObjectList
+	// Returns the ConfigMap referenced by a template object, if any
+	getConfigMapRef func(obj client.Object) *operatorv1beta1.TemplateConfigMap
+	// Removes the named template from Cryostat
+	deleteFromCryostat func(cryostat cryostatClient.CryostatClient, templateName string) error
+}
+
+// finalize removes a template object's template from Cryostat, then removes
+// its finalizer so that it may be deleted
+func (s *templateSync) finalize(ctx context.Context, obj client.Object, templateName string) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(obj, s.finalizer) {
+		// Ready for deletion
+		return reconcile.Result{}, nil
+	}
+	err := s.deleteTemplate(ctx, obj.GetNamespace(), templateName)
+	if err != nil {
+		return common.RequeueIfNotReady(s.Log, err)
+	}
+	return reconcile.Result{}, common.RemoveFinalizer(ctx, s.Client, obj, s.finalizer)
+}
+
+// addFinalizer adds our finalizer to a template object, so we can remove its
+// template from Cryostat when the object is deleted
+func (s *templateSync) addFinalizer(ctx context.Context, obj client.Object) error {
+	if controllerutil.ContainsFinalizer(obj, s.finalizer) {
+		return nil
+	}
+	return common.AddFinalizer(ctx, s.Client, obj, s.finalizer)
+}
+
+// getContent reads and returns the contents of a template object's template.
+// If the template cannot be read until the object or its ConfigMap changes,
+// the reason is reported in the object's condition, and false is returned.
+func (s *templateSync) getContent(ctx context.Context, obj client.Object, conditions *[]metav1.Condition,
+	inline *string, ref *operatorv1beta1.TemplateConfigMap) ([]byte, bool, error) {
+	content, err := getTemplateContent(ctx, s.Client, obj.GetNamespace(), inline, ref)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Retried once the ConfigMap is created
+			return nil, false, s.setAvailableCondition(ctx, obj, conditions, metav1.ConditionFalse,
+				reasonTemplateConfigMapNotFound, fmt.Sprintf("ConfigMap %s not found", ref.ConfigMapName))
+		}
+		if errors.Is(err, errTemplateInvalidSpec) || errors.Is(err, errTemplateKeyNotFound) {
+			// Not recoverable without a change to the spec or ConfigMap, so don't requeue
+			s.Log.Error(err, "invalid "+s.kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+			reason := reasonTemplateInvalidSpec
+			if errors.Is(err, errTemplateKeyNotFound) {
+				reason = reasonTemplateConfigMapNotFound
+			}
+			return nil, false, s.setAvailableCondition(ctx, obj, conditions, metav1.ConditionFalse, reason, err.Error())
+		}
+		return nil, false, err
+	}
+	return content, true, nil
+}
+
+// configMapToTemplates returns a request for each template object referencing
+// the provided ConfigMap, so that a new version of its template is uploaded
+func (s *templateSync) configMapToTemplates(obj client.Object) []reconcile.Request {
+	list := s.newList()
+	err := s.Client.List(context.Background(), list, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		s.Log.Error(err, fmt.Sprintf("Failed to list %ss", s.kind), "namespace", obj.GetNamespace())
+		return nil
+	}
+	templates, err := meta.ExtractList(list)
+	if err != nil {
+		s.Log.Error(err, fmt.Sprintf("Failed to list %ss", s.kind), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, item := range templates {
+		template, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		ref := s.getConfigMapRef(template)
+		if ref != nil && ref.ConfigMapName == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: template.GetNamespace(), Name: template.GetName()},
+			})
+		}
+	}
+	return requests
+}
+
+// deleteTemplate removes the named template from the Cryostat in the provided namespace
+func (s *templateSync) deleteTemplate(ctx context.Context, namespace string, templateName string) error {
+	if len(templateName) == 0 {
+		// Never uploaded
+		return nil
+	}
+	// Nothing to remove if Cryostat has been deleted
+	cryostats := &operatorv1beta1.CryostatList{}
+	err := s.Client.List(ctx, cryostats, client.InNamespace(namespace))
+	if err != nil {
+		return err
+	}
+	if len(cryostats.Items) == 0 {
+		return nil
+	}
+	cryostat, err := s.GetCryostatClient(ctx, namespace, nil)
+	if err != nil {
+		return err
+	}
+	err = s.deleteFromCryostat(cryostat, templateName)
+	if err != nil {
+		s.Log.Error(err, "failed to remove template from Cryostat", "kind", s.kind, "template", templateName)
+		return err
+	}
+	return nil
+}
+
+// setAvailableCondition sets the availability condition of a template object and updates its status
+func (s *templateSync) setAvailableCondition(ctx context.Context, obj client.Object, conditions *[]metav1.Condition,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    s.conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	return s.Client.Status().Update(ctx, obj)
+}
+
+// getTemplateContent returns the contents of a template provided either inline,
+// or within a ConfigMap in the given namespace
+func getTemplateContent(ctx context.Context, c client.Client, namespace string, inline *string,
+	ref *operatorv1beta1.TemplateConfigMap) ([]byte, error) {
+	if (inline == nil) == (ref == nil) {
+		return nil, errTemplateInvalidSpec
+	}
+	if inline != nil {
+		return []byte(*inline), nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Name: ref.ConfigMapName, Namespace: namespace}, cm)
+	if err != nil {
+		return nil, err
+	}
+	if data, pres := cm.Data[ref.Filename]; pres {
+		return []byte(data), nil
+	}
+	if data, pres := cm.BinaryData[ref.Filename]; pres {
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: no key \"%s\" found in ConfigMap %s", errTemplateKeyNotFound,
+		ref.Filename, cm.Name)
+}
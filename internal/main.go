@@ -161,6 +161,87 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Endpoints")
 		os.Exit(1)
 	}
+	if err = (&controllers.CustomTargetReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CustomTarget"),
+		Scheme: mgr.GetScheme(),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CustomTarget")
+		os.Exit(1)
+	}
+	if err = (&controllers.CryostatBackupReconciler{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("CryostatBackup"),
+		Scheme:      mgr.GetScheme(),
+		IsOpenShift: *openShift,
+		RESTMapper:  mgr.GetRESTMapper(),
+		ReconcilerTLS: common.NewReconcilerTLS(&common.ReconcilerTLSConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CryostatBackup")
+		os.Exit(1)
+	}
+	if err = (&controllers.CryostatRestoreReconciler{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("CryostatRestore"),
+		Scheme:      mgr.GetScheme(),
+		IsOpenShift: *openShift,
+		ReconcilerTLS: common.NewReconcilerTLS(&common.ReconcilerTLSConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CryostatRestore")
+		os.Exit(1)
+	}
+	if err = (&controllers.StorageMonitorReconciler{
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("StorageMonitor"),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("cryostat-storage-monitor"),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StorageMonitor")
+		os.Exit(1)
+	}
+	if err = (&controllers.EventTemplateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("EventTemplate"),
+		Scheme: mgr.GetScheme(),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EventTemplate")
+		os.Exit(1)
+	}
+	if err = (&controllers.ProbeTemplateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ProbeTemplate"),
+		Scheme: mgr.GetScheme(),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProbeTemplate")
+		os.Exit(1)
+	}
+	if err = (&controllers.AutomatedRuleReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("AutomatedRule"),
+		Scheme: mgr.GetScheme(),
+		Reconciler: common.NewReconciler(&common.ReconcilerConfig{
+			Client: mgr.GetClient(),
+		}),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AutomatedRule")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -37,11 +37,16 @@
 package test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	cryostatClient "github.com/cryostatio/cryostat-operator/internal/controllers/client"
+	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
 )
 
@@ -176,6 +181,10 @@ func NewListSavedFailHandler(saved []cryostatClient.SavedRecording) http.Handler
 	return newListSavedHandler(saved, true, false)
 }
 
+func NewListSavedNoJMXAuthFailHandler(saved []cryostatClient.SavedRecording) http.HandlerFunc {
+	return newListSavedHandler(saved, false, false)
+}
+
 func newListSavedHandler(saved []cryostatClient.SavedRecording, jmxAuth bool, succeed bool) http.HandlerFunc {
 	handlers := []http.HandlerFunc{
 		ghttp.VerifyRequest(http.MethodGet, "/api/v1/recordings"),
@@ -202,6 +211,21 @@ func NewSavedRecordings() []cryostatClient.SavedRecording {
 	}
 }
 
+// NewSavedRecordingsWithSizes returns archived recordings with the provided file sizes
+func NewSavedRecordingsWithSizes(sizes ...int64) []cryostatClient.SavedRecording {
+	saved := make([]cryostatClient.SavedRecording, 0, len(sizes))
+	for i, size := range sizes {
+		name := fmt.Sprintf("saved-test-recording-%d.jfr", i)
+		saved = append(saved, cryostatClient.SavedRecording{
+			Name:        name,
+			DownloadURL: "http://path/to/" + name,
+			ReportURL:   "http://path/to/" + strings.TrimSuffix(name, ".jfr") + ".html",
+			Size:        size,
+		})
+	}
+	return saved
+}
+
 func NewDeleteHandler() http.HandlerFunc {
 	return ghttp.CombineHandlers(
 		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/targets/1.2.3.4:8001/recordings/test-recording"),
@@ -266,6 +290,14 @@ func NewListEventTypesNoJMXAuthHandler() http.HandlerFunc {
 	)
 }
 
+func NewListEventTypesAgentHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/http://1.2.3.4:8001/events"),
+		verifyToken(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, NewEventTypes()),
+	)
+}
+
 func NewListEventTypesFailHandler() http.HandlerFunc {
 	return ghttp.CombineHandlers(
 		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/events"),
@@ -275,6 +307,16 @@ func NewListEventTypesFailHandler() http.HandlerFunc {
 	)
 }
 
+func NewListEventTypesHandshakeFailHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/events"),
+		verifyToken(),
+		verifyJMXAuth(),
+		ghttp.RespondWith(http.StatusBadGateway,
+			"javax.net.ssl.SSLHandshakeException: PKIX path building failed"),
+	)
+}
+
 func NewEventTypes() []operatorv1beta1.EventInfo {
 	return []operatorv1beta1.EventInfo{
 		{
@@ -320,6 +362,14 @@ func NewListTemplatesNoJMXAuthHandler() http.HandlerFunc {
 	)
 }
 
+func NewListTemplatesAgentHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/http://1.2.3.4:8001/templates"),
+		verifyToken(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, NewTemplates()),
+	)
+}
+
 func NewListTemplatesFailHandler() http.HandlerFunc {
 	return ghttp.CombineHandlers(
 		ghttp.VerifyRequest(http.MethodGet, "/api/v1/targets/1.2.3.4:8001/templates"),
@@ -329,6 +379,23 @@ func NewListTemplatesFailHandler() http.HandlerFunc {
 	)
 }
 
+func NewListCustomTemplatesHandler(templateNames ...string) http.HandlerFunc {
+	templates := []operatorv1beta1.TemplateInfo{}
+	for _, name := range templateNames {
+		templates = append(templates, operatorv1beta1.TemplateInfo{
+			Name:        name,
+			Description: "A custom template",
+			Provider:    "Cryostat",
+			Type:        operatorv1beta1.TemplateTypeCustom,
+		})
+	}
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v1/templates"),
+		verifyToken(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, templates),
+	)
+}
+
 func NewTemplates() []operatorv1beta1.TemplateInfo {
 	return []operatorv1beta1.TemplateInfo{
 		{
@@ -340,6 +407,404 @@ func NewTemplates() []operatorv1beta1.TemplateInfo {
 	}
 }
 
+func NewGetMBeanMetricsHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		verifyMBeanMetricsQuery("service:jmx:rmi:///jndi/rmi://1.2.3.4:8001/jmxrmi"),
+		verifyJMXAuth(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newMBeanMetricsResponse()),
+	)
+}
+
+func NewGetMBeanMetricsNoJMXAuthHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		verifyMBeanMetricsQuery("service:jmx:rmi:///jndi/rmi://1.2.3.4:8001/jmxrmi"),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newMBeanMetricsResponse()),
+	)
+}
+
+func NewGetMBeanMetricsForURLHandler(connectURL string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		verifyMBeanMetricsQuery(connectURL),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newMBeanMetricsResponse()),
+	)
+}
+
+func NewGetMBeanMetricsAgentHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		verifyMBeanMetricsQuery("http://1.2.3.4:8001"),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newMBeanMetricsResponse()),
+	)
+}
+
+func NewGetMBeanMetricsFailHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		verifyMBeanMetricsQuery("service:jmx:rmi:///jndi/rmi://1.2.3.4:8001/jmxrmi"),
+		verifyJMXAuth(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+			"errors": []map[string]interface{}{
+				{"message": "Connection refused"},
+			},
+		}),
+	)
+}
+
+func verifyMBeanMetricsQuery(connectURL string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodPost, "/api/v2.2/graphql"),
+		ghttp.VerifyContentType("application/json"),
+		verifyToken(),
+		func(w http.ResponseWriter, r *http.Request) {
+			request := map[string]interface{}{}
+			err := json.NewDecoder(r.Body).Decode(&request)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(request["query"]).To(gomega.ContainSubstring("mbeanMetrics"))
+			gomega.Expect(request["variables"]).To(gomega.HaveKeyWithValue("connectUrl", connectURL))
+		},
+	)
+}
+
+func newMBeanMetricsResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"targetNodes": []map[string]interface{}{
+				{"mbeanMetrics": NewMBeanMetrics()},
+			},
+		},
+	}
+}
+
+func NewMBeanMetrics() *cryostatClient.MBeanMetrics {
+	return &cryostatClient.MBeanMetrics{
+		Runtime: cryostatClient.RuntimeMetrics{
+			VMVersion:      "11.0.14+9",
+			VMVendor:       "Eclipse Adoptium",
+			StartTime:      1598045501000,
+			Uptime:         3600000,
+			InputArguments: []string{"-Dcom.sun.management.jmxremote.port=9091", "-Xmx512m"},
+		},
+		Memory: cryostatClient.MemoryMetrics{
+			HeapMemoryUsage: cryostatClient.MemoryUsage{
+				Init:      33554432,
+				Used:      20971520,
+				Committed: 33554432,
+				Max:       536870912,
+			},
+		},
+	}
+}
+
+func NewCreateCustomTargetHandler(connectURL string, alias string) http.HandlerFunc {
+	return createCustomTargetHandler(connectURL, alias, true)
+}
+
+func NewCreateCustomTargetFailHandler(connectURL string, alias string) http.HandlerFunc {
+	return createCustomTargetHandler(connectURL, alias, false)
+}
+
+func createCustomTargetHandler(connectURL string, alias string, succeed bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/v2/targets"),
+		ghttp.VerifyContentType("application/x-www-form-urlencoded"),
+		ghttp.VerifyFormKV("connectUrl", connectURL),
+		ghttp.VerifyFormKV("alias", alias),
+		verifyToken(),
+	}
+	if succeed {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, nil))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusBadRequest, "Invalid connectUrl"))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewDeleteCustomTargetHandler(connectURL string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/targets/"+connectURL),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusOK, nil),
+	)
+}
+
+func NewDeleteCustomTargetNotFoundHandler(connectURL string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/targets/"+connectURL),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusNotFound, "Target not found"),
+	)
+}
+
+func NewUploadEventTemplateHandler(fileName string, content string) http.HandlerFunc {
+	return uploadEventTemplateHandler(fileName, content, true)
+}
+
+func NewUploadEventTemplateFailHandler(fileName string, content string) http.HandlerFunc {
+	return uploadEventTemplateHandler(fileName, content, false)
+}
+
+func uploadEventTemplateHandler(fileName string, content string, succeed bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/v1/templates"),
+		verifyToken(),
+		verifyMultipartFile("template", fileName, content),
+	}
+	if succeed {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, nil))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusBadRequest, "Invalid template"))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewDeleteEventTemplateHandler(templateName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/templates/"+templateName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusOK, nil),
+	)
+}
+
+func NewDeleteEventTemplateNotFoundHandler(templateName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v1/templates/"+templateName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusNotFound, "Template not found"),
+	)
+}
+
+func NewListProbeTemplatesHandler(templateNames ...string) http.HandlerFunc {
+	templates := []cryostatClient.ProbeTemplate{}
+	for _, name := range templateNames {
+		templates = append(templates, cryostatClient.ProbeTemplate{
+			Name: name,
+			XML:  ProbeTemplateContent,
+		})
+	}
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v2/probes"),
+		verifyToken(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newV2Response(templates)),
+	)
+}
+
+func NewUploadProbeTemplateHandler(templateName string, content string) http.HandlerFunc {
+	return uploadProbeTemplateHandler(templateName, content, true)
+}
+
+func NewUploadProbeTemplateFailHandler(templateName string, content string) http.HandlerFunc {
+	return uploadProbeTemplateHandler(templateName, content, false)
+}
+
+func uploadProbeTemplateHandler(templateName string, content string, succeed bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/v2/probes/"+templateName),
+		verifyToken(),
+		verifyMultipartFile("probeTemplate", templateName+".xml", content),
+	}
+	if succeed {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, nil))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusBadRequest, "Invalid probe template"))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewDeleteProbeTemplateHandler(templateName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/probes/"+templateName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusOK, nil),
+	)
+}
+
+func NewDeleteProbeTemplateNotFoundHandler(templateName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/probes/"+templateName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusNotFound, "Probe template not found"),
+	)
+}
+
+func NewApplyProbesHandler(templateName string) http.HandlerFunc {
+	return applyProbesHandler(templateName, true)
+}
+
+func NewApplyProbesFailHandler(templateName string) http.HandlerFunc {
+	return applyProbesHandler(templateName, false)
+}
+
+func applyProbesHandler(templateName string, succeed bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/v2/targets/1.2.3.4:8001/probes/"+templateName),
+		verifyToken(),
+		verifyJMXAuth(),
+	}
+	if succeed {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusOK, nil))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusInternalServerError, "JMC agent not loaded"))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewRemoveProbesHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/targets/1.2.3.4:8001/probes"),
+		verifyToken(),
+		verifyJMXAuth(),
+		ghttp.RespondWith(http.StatusOK, nil),
+	)
+}
+
+func NewListProbesHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v2/targets/1.2.3.4:8001/probes"),
+		verifyToken(),
+		verifyJMXAuth(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newV2Response(NewProbeEvents())),
+	)
+}
+
+func NewProbeEvents() []cryostatClient.ProbeEvent {
+	return []cryostatClient.ProbeEvent{
+		{
+			ID:               "demo.jfr.test1",
+			Name:             "JFR Hello World Event 1",
+			Description:      "Defined in the xml file and added to the class at runtime",
+			Class:            "org.openjdk.jmc.agent.test.InstrumentMe",
+			MethodName:       "printHelloWorldJFR1",
+			MethodDescriptor: "()V",
+		},
+	}
+}
+
+func NewProbes() []operatorv1beta1.ProbeInfo {
+	return []operatorv1beta1.ProbeInfo{
+		{
+			ID:               "demo.jfr.test1",
+			Name:             "JFR Hello World Event 1",
+			Description:      "Defined in the xml file and added to the class at runtime",
+			Class:            "org.openjdk.jmc.agent.test.InstrumentMe",
+			Method:           "printHelloWorldJFR1",
+			MethodDescriptor: "()V",
+		},
+	}
+}
+
+func NewGetRuleHandler(rule *cryostatClient.Rule) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v2/rules/"+rule.Name),
+		verifyToken(),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, newV2Response(rule)),
+	)
+}
+
+func NewGetRuleNotFoundHandler(ruleName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodGet, "/api/v2/rules/"+ruleName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusNotFound, "Rule not found"),
+	)
+}
+
+func NewCreateRuleHandler(rule *cryostatClient.Rule) http.HandlerFunc {
+	return createRuleHandler(rule, true)
+}
+
+func NewCreateRuleFailHandler(rule *cryostatClient.Rule) http.HandlerFunc {
+	return createRuleHandler(rule, false)
+}
+
+func createRuleHandler(rule *cryostatClient.Rule, succeed bool) http.HandlerFunc {
+	handlers := []http.HandlerFunc{
+		ghttp.VerifyRequest(http.MethodPost, "/api/v2/rules"),
+		ghttp.VerifyContentType("application/x-www-form-urlencoded"),
+		ghttp.VerifyFormKV("name", rule.Name),
+		ghttp.VerifyFormKV("description", rule.Description),
+		ghttp.VerifyFormKV("matchExpression", rule.MatchExpression),
+		ghttp.VerifyFormKV("eventSpecifier", rule.EventSpecifier),
+		ghttp.VerifyFormKV("archivalPeriodSeconds", strconv.FormatInt(rule.ArchivalPeriodSeconds, 10)),
+		ghttp.VerifyFormKV("preservedArchives", strconv.FormatInt(int64(rule.PreservedArchives), 10)),
+		ghttp.VerifyFormKV("maxAgeSeconds", strconv.FormatInt(rule.MaxAgeSeconds, 10)),
+		ghttp.VerifyFormKV("maxSizeBytes", strconv.FormatInt(rule.MaxSizeBytes, 10)),
+		ghttp.VerifyFormKV("enabled", strconv.FormatBool(rule.Enabled)),
+		verifyToken(),
+	}
+	if succeed {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusCreated, nil))
+	} else {
+		handlers = append(handlers, ghttp.RespondWith(http.StatusBadRequest, "Invalid matchExpression"))
+	}
+	return ghttp.CombineHandlers(handlers...)
+}
+
+func NewDeleteRuleHandler(ruleName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/rules/"+ruleName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusOK, nil),
+	)
+}
+
+func NewDeleteRuleNotFoundHandler(ruleName string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodDelete, "/api/v2/rules/"+ruleName),
+		verifyToken(),
+		ghttp.RespondWith(http.StatusNotFound, "Rule not found"),
+	)
+}
+
+func NewRule() *cryostatClient.Rule {
+	return &cryostatClient.Rule{
+		Name:                  "test_rule",
+		Description:           "Record the test application",
+		MatchExpression:       "target.alias == 'com.example.App'",
+		EventSpecifier:        "template=Continuous,type=TARGET",
+		ArchivalPeriodSeconds: 300,
+		PreservedArchives:     3,
+		MaxAgeSeconds:         600,
+		MaxSizeBytes:          10485760,
+		Enabled:               true,
+	}
+}
+
+func newV2Response(result interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"meta": map[string]interface{}{
+			"type":   "application/json",
+			"status": "OK",
+		},
+		"data": map[string]interface{}{
+			"result": result,
+		},
+	}
+}
+
+func NewUploadClientCertificateHandler() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodPost, "/api/v2/targets/1.2.3.4:8001/certificates"),
+		verifyToken(),
+		verifyMultipartFile("cert", "tls.crt", TargetClientCert),
+		verifyMultipartFile("key", "tls.key", TargetClientKey),
+		ghttp.RespondWith(http.StatusOK, nil),
+	)
+}
+
+func verifyMultipartFile(field string, fileName string, content string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(1 << 20)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		files := r.MultipartForm.File[field]
+		gomega.Expect(files).To(gomega.HaveLen(1))
+		gomega.Expect(files[0].Filename).To(gomega.Equal(fileName))
+		file, err := files[0].Open()
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		defer file.Close()
+		buf, err := ioutil.ReadAll(file)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(string(buf)).To(gomega.Equal(content))
+	}
+}
+
 func verifyToken() http.HandlerFunc {
 	return ghttp.VerifyHeaderKV("Authorization", "Bearer bXlUb2tlbg==")
 }
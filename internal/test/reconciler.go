@@ -57,6 +57,7 @@ type TestReconcilerConfig struct {
 	EnvDatasourceImageTag *string
 	EnvGrafanaImageTag    *string
 	EnvReportsImageTag    *string
+	EnvOperatorNamespace  *string
 }
 
 // NewTestReconciler returns a common.Reconciler for use by unit tests
@@ -124,6 +125,9 @@ func newTestOSUtils(config *TestReconcilerConfig) *testOSUtils {
 	if config.EnvReportsImageTag != nil {
 		envs["RELATED_IMAGE_REPORTS"] = *config.EnvReportsImageTag
 	}
+	if config.EnvOperatorNamespace != nil {
+		envs["OPERATOR_NAMESPACE"] = *config.EnvOperatorNamespace
+	}
 	return &testOSUtils{envs}
 }
 
@@ -37,6 +37,8 @@
 package test
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"time"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
@@ -48,12 +50,17 @@ import (
 	consolev1 "github.com/openshift/api/console/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -73,6 +80,14 @@ func NewTestScheme() *runtime.Scheme {
 	err := sb.AddToScheme(s)
 	gomega.Expect(err).ToNot(gomega.HaveOccurred())
 
+	// The operator manages Gateway API objects as unstructured objects, but the
+	// fake client requires their types to be registered in order to list them
+	gatewayGV := schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1"}
+	s.AddKnownTypeWithName(gatewayGV.WithKind("HTTPRoute"), &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(gatewayGV.WithKind("HTTPRouteList"), &unstructured.UnstructuredList{})
+	s.AddKnownTypeWithName(volumeSnapshotGVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(volumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"), &unstructured.UnstructuredList{})
+
 	return s
 }
 
@@ -86,9 +101,23 @@ func NewTESTRESTMapper() meta.RESTMapper {
 		Version: certv1.SchemeGroupVersion.Version,
 		Kind:    certv1.IssuerKind,
 	}, meta.RESTScopeNamespace)
+	// Add Gateway API HTTPRoute GVK
+	mapper.Add(schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "HTTPRoute",
+	}, meta.RESTScopeNamespace)
+	// Add VolumeSnapshot GVK
+	mapper.Add(volumeSnapshotGVK, meta.RESTScopeNamespace)
 	return mapper
 }
 
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
 func NewCryostat() *operatorv1beta1.Cryostat {
 	certManager := true
 	return &operatorv1beta1.Cryostat{
@@ -104,6 +133,47 @@ func NewCryostat() *operatorv1beta1.Cryostat {
 	}
 }
 
+// NewMonitoredCryostat returns an available Cryostat using the default
+// PersistentVolumeClaim, whose storage usage can be measured
+func NewMonitoredCryostat() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Status.Storage = &operatorv1beta1.StorageStatus{
+		ClaimName: "cryostat",
+	}
+	cr.Status.Conditions = []metav1.Condition{
+		{
+			Type:   string(operatorv1beta1.ConditionTypeMainDeploymentAvailable),
+			Status: metav1.ConditionTrue,
+			Reason: "MinimumReplicasAvailable",
+		},
+	}
+	return cr
+}
+
+func NewMonitoredCryostatWithThresholds() *operatorv1beta1.Cryostat {
+	cr := NewMonitoredCryostat()
+	warning := int32(50)
+	critical := int32(75)
+	cr.Spec.StorageOptions = &operatorv1beta1.StorageConfiguration{
+		Monitoring: &operatorv1beta1.StorageMonitoringConfiguration{
+			WarningThresholdPercent:  &warning,
+			CriticalThresholdPercent: &critical,
+			Interval:                 &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	return cr
+}
+
+func NewMonitoredCryostatNearlyFull() *operatorv1beta1.Cryostat {
+	cr := NewMonitoredCryostat()
+	cr.Status.Conditions = append(cr.Status.Conditions, metav1.Condition{
+		Type:   string(operatorv1beta1.ConditionTypeStorageNearlyFull),
+		Status: metav1.ConditionTrue,
+		Reason: "UsageAboveWarningThreshold",
+	})
+	return cr
+}
+
 func NewCryostatWithSecrets() *operatorv1beta1.Cryostat {
 	cr := NewCryostat()
 	key := "test.crt"
@@ -141,6 +211,42 @@ func NewCryostatWithIngress() *operatorv1beta1.Cryostat {
 	return cr
 }
 
+func NewCryostatWithRouteConfig() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.NetworkOptions = &operatorv1beta1.NetworkConfigurationList{
+		CoreConfig: &operatorv1beta1.NetworkConfiguration{
+			RouteConfig: &operatorv1beta1.RouteConfiguration{
+				Host:                  "cryostat.apps.example.com",
+				Path:                  "/cryostat",
+				WildcardPolicy:        "Subdomain",
+				CertificateSecretName: "cryostat-route-tls",
+			},
+			Annotations: map[string]string{"haproxy.router.openshift.io/timeout": "5m"},
+			Labels:      map[string]string{"my": "label"},
+		},
+		GrafanaConfig: &operatorv1beta1.NetworkConfiguration{
+			Annotations: map[string]string{"grafana": "annotation"},
+			Labels:      map[string]string{"grafana": "label"},
+		},
+	}
+	return cr
+}
+
+func NewRouteCertificateSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-route-tls",
+			Namespace: "default",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("route-cert-bytes"),
+			corev1.TLSPrivateKeyKey: []byte("route-key-bytes"),
+			"ca.crt":                []byte("route-ca-bytes"),
+		},
+	}
+}
+
 func NewCryostatWithIngressNoTLS() *operatorv1beta1.Cryostat {
 	cr := NewCryostat()
 	networkConfig := NewNetworkConfigurationList(false)
@@ -148,6 +254,14 @@ func NewCryostatWithIngressNoTLS() *operatorv1beta1.Cryostat {
 	return cr
 }
 
+func NewCryostatWithGateway() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.NetworkOptions = &operatorv1beta1.NetworkConfigurationList{
+		GatewayConfig: NewGatewayConfiguration(),
+	}
+	return cr
+}
+
 func NewCryostatWithPVCSpec() *operatorv1beta1.Cryostat {
 	cr := NewCryostat()
 	cr.Spec.StorageOptions = &operatorv1beta1.StorageConfiguration{
@@ -297,6 +411,268 @@ func NewCryostatWithReportsResources() *operatorv1beta1.Cryostat {
 	return cr
 }
 
+func NewCryostatWithScheduling() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.SchedulingOptions = NewSchedulingConfiguration("node")
+	return cr
+}
+
+func NewCryostatWithReportsAutoscaling() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	minReplicas := int32(2)
+	cpuUtilization := int32(60)
+	maxUnavailable := intstr.FromInt(1)
+	cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{
+		Autoscaling: &operatorv1beta1.ReportsAutoscalingConfiguration{
+			MinReplicas:                    &minReplicas,
+			MaxReplicas:                    5,
+			TargetCPUUtilizationPercentage: &cpuUtilization,
+		},
+		PodDisruptionBudget: &operatorv1beta1.PodDisruptionBudgetConfiguration{
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+	return cr
+}
+
+func NewReportsCustomMetric() autoscalingv2beta2.MetricSpec {
+	target := resource.MustParse("2")
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.PodsMetricSourceType,
+		Pods: &autoscalingv2beta2.PodsMetricSource{
+			Metric: autoscalingv2beta2.MetricIdentifier{
+				Name: "report_requests_in_flight",
+			},
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:         autoscalingv2beta2.AverageValueMetricType,
+				AverageValue: &target,
+			},
+		},
+	}
+}
+
+func NewCryostatWithReportsScheduling() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{
+		Replicas:          1,
+		SchedulingOptions: NewSchedulingConfiguration("reports-node"),
+	}
+	return cr
+}
+
+func NewSchedulingConfiguration(nodeName string) *operatorv1beta1.SchedulingConfiguration {
+	return &operatorv1beta1.SchedulingConfiguration{
+		NodeSelector: map[string]string{"node": nodeName},
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{
+									Key:      "node",
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{nodeName},
+								},
+							},
+						},
+					},
+				},
+			},
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"app": "cryostat"},
+							},
+							TopologyKey: "kubernetes.io/hostname",
+						},
+					},
+				},
+			},
+		},
+		Tolerations: []corev1.Toleration{
+			{
+				Key:      "node-role.kubernetes.io/infra",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			},
+		},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "cryostat"},
+				},
+			},
+		},
+		PriorityClassName: "infra-critical",
+	}
+}
+
+func NewCryostatWithSecurityOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	nonRoot := true
+	privEscalation := false
+	runAsUser := int64(1001)
+	fsGroup := int64(2000)
+	cr.Spec.SecurityOptions = &operatorv1beta1.SecurityOptions{
+		PodSecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot: &nonRoot,
+			FSGroup:      &fsGroup,
+		},
+		CoreSecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &privEscalation,
+			RunAsUser:                &runAsUser,
+		},
+		DataSourceSecurityContext: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+		GrafanaSecurityContext: &corev1.SecurityContext{
+			RunAsUser: &runAsUser,
+		},
+	}
+	return cr
+}
+
+func NewCryostatWithReportSecurityOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	nonRoot := true
+	runAsUser := int64(1002)
+	cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{
+		Replicas: 1,
+		SecurityOptions: &operatorv1beta1.ReportsSecurityOptions{
+			PodSecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot: &nonRoot,
+			},
+			ReportsSecurityContext: &corev1.SecurityContext{
+				RunAsUser: &runAsUser,
+			},
+		},
+	}
+	return cr
+}
+
+func NewCryostatWithEnvironmentOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.EnvironmentOptions = &operatorv1beta1.EnvironmentOptions{
+		CoreEnvironment: &operatorv1beta1.JavaContainerEnvironment{
+			JVMOptions: []string{"-XX:+UseG1GC", "-Xmx512m"},
+			ContainerEnvironment: operatorv1beta1.ContainerEnvironment{
+				ExtraEnv: []corev1.EnvVar{
+					{
+						Name:  "CRYOSTAT_DISCOVERY_PING_PERIOD",
+						Value: "30000",
+					},
+					{
+						Name:  "CRYOSTAT_WEB_PORT",
+						Value: "9000",
+					},
+				},
+				ExtraEnvFrom: []corev1.EnvFromSource{
+					{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "cryostat-extra-config",
+							},
+						},
+					},
+				},
+			},
+		},
+		DataSourceEnvironment: &operatorv1beta1.JavaContainerEnvironment{
+			JVMOptions: []string{"-Xmx256m"},
+		},
+		GrafanaEnvironment: &operatorv1beta1.ContainerEnvironment{
+			ExtraEnv: []corev1.EnvVar{
+				{
+					Name:  "GF_LOG_LEVEL",
+					Value: "debug",
+				},
+			},
+			ExtraEnvFrom: []corev1.EnvFromSource{
+				{
+					SecretRef: &corev1.SecretEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "grafana-extra-config",
+						},
+					},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func NewCryostatWithReportEnvironmentOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{
+		Replicas: 1,
+		EnvironmentOptions: &operatorv1beta1.JavaContainerEnvironment{
+			JVMOptions: []string{"-Xmx1g"},
+			ContainerEnvironment: operatorv1beta1.ContainerEnvironment{
+				ExtraEnv: []corev1.EnvVar{
+					{
+						Name:  "QUARKUS_LOG_LEVEL",
+						Value: "DEBUG",
+					},
+					{
+						Name:  "JAVA_OPTIONS",
+						Value: "-Xmx2g",
+					},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func NewCryostatWithPodTemplateOverride() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.PodTemplateOverride = &operatorv1beta1.PodTemplateOverride{
+		Patch: `metadata:
+  labels:
+    sidecar.istio.io/inject: "true"
+  annotations:
+    example.com/owner: team-a
+spec:
+  imagePullSecrets:
+  - name: registry-credentials
+  containers:
+  - name: cryostat
+    volumeMounts:
+    - name: extra-config
+      mountPath: /opt/extra
+  - name: log-forwarder
+    image: quay.io/example/log-forwarder:latest
+  volumes:
+  - name: extra-config
+    configMap:
+      name: extra-config
+`,
+	}
+	return cr
+}
+
+func NewCryostatWithReportsPodTemplateOverride() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	patchType := operatorv1beta1.PodTemplatePatchTypeJSON
+	cr.Spec.ReportOptions = &operatorv1beta1.ReportConfiguration{
+		Replicas: 1,
+		PodTemplateOverride: &operatorv1beta1.PodTemplateOverride{
+			Type:  &patchType,
+			Patch: `[{"op": "add", "path": "/spec/imagePullSecrets", "value": [{"name": "registry-credentials"}]}]`,
+		},
+	}
+	return cr
+}
+
 func NewCryostatCertManagerDisabled() *operatorv1beta1.Cryostat {
 	cr := NewCryostat()
 	certManager := false
@@ -310,6 +686,34 @@ func NewCryostatCertManagerUndefined() *operatorv1beta1.Cryostat {
 	return cr
 }
 
+func NewCryostatWithOperatorCA() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	provider := operatorv1beta1.TLSProviderOperator
+	cr.Spec.TLSProvider = &provider
+	return cr
+}
+
+func NewCryostatWithCertificateOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.CertificateOptions = NewCertificateConfiguration()
+	return cr
+}
+
+func NewCertificateConfiguration() *operatorv1beta1.CertificateConfiguration {
+	return &operatorv1beta1.CertificateConfiguration{
+		IssuerRef: &operatorv1beta1.IssuerReference{
+			Name: "corporate-issuer",
+			Kind: "ClusterIssuer",
+		},
+		Duration:    &metav1.Duration{Duration: 720 * time.Hour},
+		RenewBefore: &metav1.Duration{Duration: 240 * time.Hour},
+		PrivateKey: &operatorv1beta1.CertificatePrivateKey{
+			Algorithm: "ECDSA",
+			Size:      384,
+		},
+	}
+}
+
 func NewCryostatWithResources() *operatorv1beta1.Cryostat {
 	cr := NewCryostat()
 	cr.Spec.Resources = operatorv1beta1.ResourceConfigList{
@@ -389,6 +793,59 @@ func NewCryostatWithReportSubprocessHeapSpec() *operatorv1beta1.Cryostat {
 	return cr
 }
 
+func NewCryostatWithNetworkPolicies() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.NetworkPolicyOptions = &operatorv1beta1.NetworkPolicyConfiguration{
+		Enabled: true,
+	}
+	return cr
+}
+
+func NewCryostatWithServiceSelector() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.TargetDiscoveryOptions = &operatorv1beta1.TargetDiscoveryOptions{
+		ServiceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"cryostat.io/discover": "true",
+			},
+		},
+	}
+	return cr
+}
+
+func NewCryostatWithNamespaceSelector() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.TargetDiscoveryOptions = &operatorv1beta1.TargetDiscoveryOptions{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "environment",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{"dev", "test"},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func NewCryostatWithJMXPortOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.TargetDiscoveryOptions = &operatorv1beta1.TargetDiscoveryOptions{
+		JMXPortNames:   []string{"custom-jmx"},
+		JMXPortNumbers: []int32{5678},
+	}
+	return cr
+}
+
+func NewCryostatWithAgentPortOptions() *operatorv1beta1.Cryostat {
+	cr := NewCryostat()
+	cr.Spec.TargetDiscoveryOptions = &operatorv1beta1.TargetDiscoveryOptions{
+		AgentPortNames: []string{"custom-agent"},
+	}
+	return cr
+}
+
 func NewFlightRecorder() *operatorv1beta1.FlightRecorder {
 	return newFlightRecorder(&operatorv1beta1.JMXAuthSecret{
 		SecretName: "test-jmx-auth",
@@ -399,6 +856,12 @@ func NewFlightRecorderNoJMXAuth() *operatorv1beta1.FlightRecorder {
 	return newFlightRecorder(nil)
 }
 
+func NewFlightRecorderForAgent() *operatorv1beta1.FlightRecorder {
+	recorder := newFlightRecorder(nil)
+	recorder.Status.Protocol = operatorv1beta1.TargetProtocolHTTP
+	return recorder
+}
+
 func NewFlightRecorderBadJMXUserKey() *operatorv1beta1.FlightRecorder {
 	key := "not-username"
 	return newFlightRecorder(&operatorv1beta1.JMXAuthSecret{
@@ -430,6 +893,59 @@ func NewFlightRecorderForCryostat() *operatorv1beta1.FlightRecorder {
 	return recorder
 }
 
+func NewFlightRecorderWithTLS() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	recorder.Spec.TLS = &operatorv1beta1.JMXTLSConfig{
+		CACert: operatorv1beta1.CertificateSecret{
+			SecretName: "test-jmx-ca",
+		},
+	}
+	return recorder
+}
+
+func NewFlightRecorderWithClientCert() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorderWithTLS()
+	secretName := "test-jmx-client-tls"
+	recorder.Spec.TLS.ClientCertSecret = &secretName
+	return recorder
+}
+
+func NewFlightRecorderWithProbes() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorder()
+	templateName := "test-probes"
+	recorder.Spec.ProbeTemplate = &templateName
+	return recorder
+}
+
+func NewFlightRecorderProbesApplied() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorderWithProbes()
+	recorder.Status.Probes = &operatorv1beta1.ProbeStatus{
+		ProbeTemplate: "test-probes",
+		ContentHash:   fmt.Sprintf("%x", sha256.Sum256([]byte(ProbeTemplateContent))),
+		Active:        NewProbes(),
+	}
+	return recorder
+}
+
+func NewFlightRecorderProbesRemoved() *operatorv1beta1.FlightRecorder {
+	recorder := NewFlightRecorderProbesApplied()
+	recorder.Spec.ProbeTemplate = nil
+	return recorder
+}
+
+func NewJVMInfo() *operatorv1beta1.JVMInfo {
+	startTime := metav1.Unix(1598045501, 0)
+	return &operatorv1beta1.JVMInfo{
+		Version:        "11.0.14+9",
+		Vendor:         "Eclipse Adoptium",
+		StartTime:      &startTime,
+		Uptime:         &metav1.Duration{Duration: time.Hour},
+		HeapInitial:    resource.NewQuantity(33554432, resource.BinarySI),
+		HeapMax:        resource.NewQuantity(536870912, resource.BinarySI),
+		InputArguments: []string{"-Dcom.sun.management.jmxremote.port=9091", "-Xmx512m"},
+	}
+}
+
 func newFlightRecorder(jmxAuth *operatorv1beta1.JMXAuthSecret) *operatorv1beta1.FlightRecorder {
 	return &operatorv1beta1.FlightRecorder{
 		TypeMeta: metav1.TypeMeta{
@@ -462,7 +978,646 @@ func newFlightRecorder(jmxAuth *operatorv1beta1.JMXAuthSecret) *operatorv1beta1.
 				Name:       "test-pod",
 				Namespace:  "default",
 			},
-			Port: 8001,
+			Port: 8001,
+		},
+	}
+}
+
+func NewFlightRecorderForCustomTarget() *operatorv1beta1.FlightRecorder {
+	recorder := newFlightRecorder(nil)
+	recorder.Name = "test-custom-target"
+	recorder.Labels = map[string]string{"app": "test-custom-target"}
+	recorder.OwnerReferences[0].APIVersion = "operator.cryostat.io/v1beta1"
+	recorder.OwnerReferences[0].Kind = "CustomTarget"
+	recorder.OwnerReferences[0].Name = "test-custom-target"
+	recorder.Spec.RecordingSelector.MatchLabels = map[string]string{"operator.cryostat.io/flightrecorder": "test-custom-target"}
+	recorder.Status.Target = &corev1.ObjectReference{
+		APIVersion: "operator.cryostat.io/v1beta1",
+		Kind:       "CustomTarget",
+		Name:       "test-custom-target",
+		Namespace:  "default",
+	}
+	return recorder
+}
+
+const CustomTargetConnectURL = "service:jmx:rmi:///jndi/rmi://1.2.3.4:8001/jmxrmi"
+
+func NewCustomTarget() *operatorv1beta1.CustomTarget {
+	host := "1.2.3.4"
+	port := int32(8001)
+	return &operatorv1beta1.CustomTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-custom-target",
+			Namespace: "default",
+		},
+		Spec: operatorv1beta1.CustomTargetSpec{
+			Host:  &host,
+			Port:  &port,
+			Alias: "my-jvm",
+		},
+	}
+}
+
+func NewCustomTargetConnectURL() *operatorv1beta1.CustomTarget {
+	target := NewCustomTarget()
+	connectURL := CustomTargetConnectURL
+	target.Spec.Host = nil
+	target.Spec.Port = nil
+	target.Spec.ConnectURL = &connectURL
+	return target
+}
+
+func NewCustomTargetNoAlias() *operatorv1beta1.CustomTarget {
+	target := NewCustomTarget()
+	target.Spec.Alias = ""
+	return target
+}
+
+func NewCustomTargetInvalid() *operatorv1beta1.CustomTarget {
+	target := NewCustomTarget()
+	connectURL := CustomTargetConnectURL
+	target.Spec.ConnectURL = &connectURL
+	return target
+}
+
+func NewCustomTargetRegistered() *operatorv1beta1.CustomTarget {
+	target := NewCustomTarget()
+	target.Finalizers = []string{"operator.cryostat.io/customtarget.finalizer"}
+	target.Status = operatorv1beta1.CustomTargetStatus{
+		ConnectURL: CustomTargetConnectURL,
+		Alias:      "my-jvm",
+	}
+	return target
+}
+
+func NewDeletedCustomTarget() *operatorv1beta1.CustomTarget {
+	target := NewCustomTargetRegistered()
+	delTime := metav1.Unix(0, 1598045501618*int64(time.Millisecond))
+	target.DeletionTimestamp = &delTime
+	return target
+}
+
+const EventTemplateContent = `<?xml version="1.0" encoding="UTF-8"?>
+<configuration version="2.0" label="Custom" description="A custom template" provider="Cryostat">
+  <event name="jdk.ThreadSleep">
+    <setting name="enabled">true</setting>
+    <setting name="threshold">20 ms</setting>
+  </event>
+</configuration>
+`
+
+func NewEventTemplate() *operatorv1beta1.EventTemplate {
+	content := EventTemplateContent
+	return &operatorv1beta1.EventTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-template",
+			Namespace: "default",
+		},
+		Spec: operatorv1beta1.EventTemplateSpec{
+			Template: &content,
+		},
+	}
+}
+
+func NewEventTemplateFromConfigMap() *operatorv1beta1.EventTemplate {
+	template := NewEventTemplate()
+	template.Spec.Template = nil
+	template.Spec.ConfigMapRef = &operatorv1beta1.TemplateConfigMap{
+		ConfigMapName: "templateCM1",
+		Filename:      "custom.jfc",
+	}
+	return template
+}
+
+func NewEventTemplateConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "templateCM1",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"custom.jfc": EventTemplateContent,
+		},
+	}
+}
+
+func NewEventTemplateInvalidSpec() *operatorv1beta1.EventTemplate {
+	template := NewEventTemplateFromConfigMap()
+	content := EventTemplateContent
+	template.Spec.Template = &content
+	return template
+}
+
+func NewEventTemplateWithContent(content string) *operatorv1beta1.EventTemplate {
+	template := NewEventTemplate()
+	template.Spec.Template = &content
+	return template
+}
+
+func NewEventTemplateUploaded() *operatorv1beta1.EventTemplate {
+	template := NewEventTemplate()
+	template.Finalizers = []string{"operator.cryostat.io/eventtemplate.finalizer"}
+	template.Status = operatorv1beta1.EventTemplateStatus{
+		TemplateName: "Custom",
+		Description:  "A custom template",
+		Provider:     "Cryostat",
+		ContentHash:  fmt.Sprintf("%x", sha256.Sum256([]byte(EventTemplateContent))),
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(operatorv1beta1.ConditionTypeTemplateAvailable),
+				Status:             metav1.ConditionTrue,
+				Reason:             "TemplateUploaded",
+				LastTransitionTime: metav1.Unix(0, 1598045501618*int64(time.Millisecond)),
+			},
+		},
+	}
+	return template
+}
+
+func NewOtherEventTemplateUploaded() *operatorv1beta1.EventTemplate {
+	template := NewEventTemplateUploaded()
+	template.Name = "other-template"
+	return template
+}
+
+func NewDeletedEventTemplate() *operatorv1beta1.EventTemplate {
+	template := NewEventTemplateUploaded()
+	delTime := metav1.Unix(0, 1598045501618*int64(time.Millisecond))
+	template.DeletionTimestamp = &delTime
+	return template
+}
+
+const ProbeTemplateContent = `<jfragent>
+  <config>
+    <classprefix>__JFREvent</classprefix>
+  </config>
+  <events>
+    <event id="demo.jfr.test1">
+      <label>JFR Hello World Event 1</label>
+      <description>Defined in the xml file and added to the class at runtime</description>
+      <class>org.openjdk.jmc.agent.test.InstrumentMe</class>
+      <path>demo/jfrhelloworldevent1</path>
+      <stacktrace>true</stacktrace>
+      <method>
+        <name>printHelloWorldJFR1</name>
+        <descriptor>()V</descriptor>
+      </method>
+      <location>WRAP</location>
+    </event>
+  </events>
+</jfragent>
+`
+
+func NewProbeTemplate() *operatorv1beta1.ProbeTemplate {
+	content := ProbeTemplateContent
+	return &operatorv1beta1.ProbeTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-probes",
+			Namespace: "default",
+		},
+		Spec: operatorv1beta1.ProbeTemplateSpec{
+			Template: &content,
+		},
+	}
+}
+
+func NewProbeTemplateFromConfigMap() *operatorv1beta1.ProbeTemplate {
+	template := NewProbeTemplate()
+	template.Spec.Template = nil
+	template.Spec.ConfigMapRef = &operatorv1beta1.TemplateConfigMap{
+		ConfigMapName: "probeCM1",
+		Filename:      "probes.xml",
+	}
+	return template
+}
+
+func NewProbeTemplateConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "probeCM1",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"probes.xml": ProbeTemplateContent,
+		},
+	}
+}
+
+func NewProbeTemplateWithContent(content string) *operatorv1beta1.ProbeTemplate {
+	template := NewProbeTemplate()
+	template.Spec.Template = &content
+	return template
+}
+
+func NewProbeTemplateUploaded() *operatorv1beta1.ProbeTemplate {
+	template := NewProbeTemplate()
+	template.Finalizers = []string{"operator.cryostat.io/probetemplate.finalizer"}
+	template.Status = operatorv1beta1.ProbeTemplateStatus{
+		TemplateName: "test-probes",
+		Events:       []string{"demo.jfr.test1"},
+		ContentHash:  fmt.Sprintf("%x", sha256.Sum256([]byte(ProbeTemplateContent))),
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(operatorv1beta1.ConditionTypeProbeTemplateAvailable),
+				Status:             metav1.ConditionTrue,
+				Reason:             "TemplateUploaded",
+				LastTransitionTime: metav1.Unix(0, 1598045501618*int64(time.Millisecond)),
+			},
+		},
+	}
+	return template
+}
+
+func NewProbeTemplateNotAvailable() *operatorv1beta1.ProbeTemplate {
+	template := NewProbeTemplateUploaded()
+	template.Status.Conditions[0].Status = metav1.ConditionFalse
+	template.Status.Conditions[0].Reason = "UploadFailed"
+	return template
+}
+
+func NewDeletedProbeTemplate() *operatorv1beta1.ProbeTemplate {
+	template := NewProbeTemplateUploaded()
+	delTime := metav1.Unix(0, 1598045501618*int64(time.Millisecond))
+	template.DeletionTimestamp = &delTime
+	return template
+}
+
+func NewAutomatedRule() *operatorv1beta1.AutomatedRule {
+	templateType := operatorv1beta1.TemplateTypeTarget
+	preserved := int32(3)
+	maxSize := resource.MustParse("10Mi")
+	return &operatorv1beta1.AutomatedRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-rule",
+			Namespace:  "default",
+			Generation: 1,
+		},
+		Spec: operatorv1beta1.AutomatedRuleSpec{
+			Description:       "Record the test application",
+			MatchExpression:   "target.alias == 'com.example.App'",
+			EventTemplate:     "Continuous",
+			TemplateType:      &templateType,
+			ArchivalPeriod:    &metav1.Duration{Duration: 5 * time.Minute},
+			PreservedArchives: &preserved,
+			MaxAge:            &metav1.Duration{Duration: 10 * time.Minute},
+			MaxSize:           &maxSize,
+		},
+	}
+}
+
+func NewAutomatedRuleSynced() *operatorv1beta1.AutomatedRule {
+	rule := NewAutomatedRule()
+	rule.Finalizers = []string{"operator.cryostat.io/automatedrule.finalizer"}
+	rule.Status = operatorv1beta1.AutomatedRuleStatus{
+		RuleName:           "test_rule",
+		ObservedGeneration: 1,
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(operatorv1beta1.ConditionTypeRuleSynced),
+				Status:             metav1.ConditionTrue,
+				Reason:             "RuleSynced",
+				LastTransitionTime: metav1.Unix(0, 1598045501618*int64(time.Millisecond)),
+			},
+		},
+	}
+	return rule
+}
+
+func NewAutomatedRuleChanged() *operatorv1beta1.AutomatedRule {
+	rule := NewAutomatedRuleSynced()
+	rule.Generation = 2
+	rule.Spec.MaxAge = &metav1.Duration{Duration: 20 * time.Minute}
+	return rule
+}
+
+func NewDeletedAutomatedRule() *operatorv1beta1.AutomatedRule {
+	rule := NewAutomatedRuleSynced()
+	delTime := metav1.Unix(0, 1598045501618*int64(time.Millisecond))
+	rule.DeletionTimestamp = &delTime
+	return rule
+}
+
+func NewCryostatBackup() *operatorv1beta1.CryostatBackup {
+	return &operatorv1beta1.CryostatBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-backup",
+			Namespace: "default",
+		},
+		Spec: operatorv1beta1.CryostatBackupSpec{
+			CryostatName: "cryostat",
+			Destination: operatorv1beta1.BackupDestination{
+				PVC: &operatorv1beta1.BackupPVCDestination{
+					ClaimName: "cryostat-backups",
+					SubPath:   "backups",
+				},
+			},
+		},
+	}
+}
+
+func NewCryostatBackupWithPaths() *operatorv1beta1.CryostatBackup {
+	backup := NewCryostatBackup()
+	backup.Spec.Paths = []operatorv1beta1.StoragePath{
+		operatorv1beta1.StoragePathFlightRecordings,
+		operatorv1beta1.StoragePathTemplates,
+	}
+	return backup
+}
+
+func NewCryostatBackupToSnapshot() *operatorv1beta1.CryostatBackup {
+	backup := NewCryostatBackup()
+	className := "csi-snapclass"
+	backup.Spec.Destination = operatorv1beta1.BackupDestination{
+		VolumeSnapshot: &operatorv1beta1.BackupVolumeSnapshotDestination{
+			VolumeSnapshotClassName: &className,
+		},
+	}
+	return backup
+}
+
+func NewCryostatBackupInvalid() *operatorv1beta1.CryostatBackup {
+	backup := NewCryostatBackup()
+	backup.Spec.Destination.VolumeSnapshot = &operatorv1beta1.BackupVolumeSnapshotDestination{}
+	return backup
+}
+
+func NewCryostatBackupSucceeded() *operatorv1beta1.CryostatBackup {
+	backup := NewCryostatBackupWithPaths()
+	backup.Status = operatorv1beta1.CryostatBackupStatus{
+		Phase: operatorv1beta1.StorageOperationSucceeded,
+		Paths: backup.Spec.Paths,
+		Archive: &operatorv1beta1.BackupArchive{
+			ClaimName: "cryostat-backups",
+			Path:      "backups/test-backup.tar.gz",
+		},
+	}
+	return backup
+}
+
+func NewCryostatBackupSnapshotSucceeded() *operatorv1beta1.CryostatBackup {
+	backup := NewCryostatBackupToSnapshot()
+	backup.Status = operatorv1beta1.CryostatBackupStatus{
+		Phase:              operatorv1beta1.StorageOperationSucceeded,
+		Paths:              operatorv1beta1.AllStoragePaths,
+		VolumeSnapshotName: "test-backup",
+	}
+	return backup
+}
+
+func NewCryostatBackupFailed() *operatorv1beta1.CryostatBackup {
+	backup := NewCryostatBackup()
+	backup.Status.Phase = operatorv1beta1.StorageOperationFailed
+	return backup
+}
+
+func NewCryostatRestore() *operatorv1beta1.CryostatRestore {
+	return &operatorv1beta1.CryostatRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-restore",
+			Namespace: "default",
+		},
+		Spec: operatorv1beta1.CryostatRestoreSpec{
+			CryostatName: "cryostat",
+			BackupName:   "test-backup",
+		},
+	}
+}
+
+func NewCryostatRestoreWithPaths(paths ...operatorv1beta1.StoragePath) *operatorv1beta1.CryostatRestore {
+	restore := NewCryostatRestore()
+	restore.Spec.Paths = paths
+	return restore
+}
+
+func NewCryostatRestoreRunning() *operatorv1beta1.CryostatRestore {
+	restore := NewCryostatRestore()
+	startTime := metav1.Unix(0, 1598045501618*int64(time.Millisecond))
+	restore.Status = operatorv1beta1.CryostatRestoreStatus{
+		Phase:     operatorv1beta1.StorageOperationRunning,
+		StartTime: &startTime,
+	}
+	return restore
+}
+
+func NewCryostatRestoreSucceeded() *operatorv1beta1.CryostatRestore {
+	restore := NewCryostatRestoreRunning()
+	restore.Status.Phase = operatorv1beta1.StorageOperationSucceeded
+	return restore
+}
+
+func NewBackupJob() *batchv1.Job {
+	return newStorageJob("test-backup-backup", "backup", []string{"tar"},
+		[]string{"-czf", "/backup/test-backup.tar.gz", "--ignore-failed-read", "-C", "/cryostat",
+			"config", "flightrecordings", "templates", "clientlib", "probes", "truststore"},
+		[]corev1.VolumeMount{
+			{
+				Name:      "cryostat-storage",
+				MountPath: "/cryostat",
+				ReadOnly:  true,
+			},
+			{
+				Name:      "backup",
+				MountPath: "/backup",
+				SubPath:   "backups",
+			},
+		}, "cryostat-backups", false)
+}
+
+func NewRestoreJob() *batchv1.Job {
+	return newStorageJob("test-restore-restore", "restore", []string{"/bin/sh", "-c"},
+		NewRestoreJobArgs("flightrecordings", "templates"), newRestoreVolumeMounts(), "cryostat-backups", true)
+}
+
+func NewRestoreJobArgs(paths ...string) []string {
+	return append([]string{`archive="$1"; shift; members=""; for dir in "$@"; do ` +
+		`if tar -tzf "$archive" "$dir" > /dev/null 2>&1; then members="$members $dir"; fi; done; ` +
+		`if [ -n "$members" ]; then tar -xzf "$archive" -C "/cryostat" $members; fi`,
+		"restore", "/backup/backups/test-backup.tar.gz"}, paths...)
+}
+
+func NewSnapshotRestoreJob() *batchv1.Job {
+	return newStorageJob("test-restore-restore", "restore", []string{"/bin/sh", "-c"},
+		[]string{`for dir in "$@"; do if [ -e "/backup/$dir" ]; then cp -a "/backup/$dir" "/cryostat/" || exit 1; fi; done`,
+			"restore", "config", "flightrecordings", "templates", "clientlib", "probes", "truststore"},
+		newRestoreVolumeMounts(), "test-restore-restore-source", true)
+}
+
+func NewMigrationJob(targetClaim string) *batchv1.Job {
+	job := newStorageJob(targetClaim+"-migration", "migration", []string{"cp"}, []string{"-a", "/cryostat/.", "/migration/"},
+		[]corev1.VolumeMount{
+			{
+				Name:      "cryostat-storage",
+				MountPath: "/cryostat",
+				ReadOnly:  true,
+			},
+			{
+				Name:      "migration",
+				MountPath: "/migration",
+			},
+		}, targetClaim, false)
+	job.Spec.Template.Spec.Volumes[1].Name = "migration"
+	return job
+}
+
+func newRestoreVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      "cryostat-storage",
+			MountPath: "/cryostat",
+		},
+		{
+			Name:      "backup",
+			MountPath: "/backup",
+			ReadOnly:  true,
+		},
+	}
+}
+
+func newStorageJob(name string, containerName string, command []string, args []string,
+	mounts []corev1.VolumeMount, backupClaim string, restore bool) *batchv1.Job {
+	labels := map[string]string{
+		"app":       "cryostat",
+		"component": "storage-" + containerName,
+	}
+	backoffLimit := int32(2)
+	fsGroup := int64(18500)
+	nonRoot := true
+	privEscalation := false
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            containerName,
+							Image:           "my/core-image:1.0",
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         command,
+							Args:            args,
+							VolumeMounts:    mounts,
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: &privEscalation,
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{"ALL"},
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "cryostat-storage",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: "cryostat",
+									ReadOnly:  !restore,
+								},
+							},
+						},
+						{
+							Name: "backup",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: backupClaim,
+									ReadOnly:  restore,
+								},
+							},
+						},
+					},
+					SecurityContext: &corev1.PodSecurityContext{
+						FSGroup:      &fsGroup,
+						RunAsNonRoot: &nonRoot,
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+								{
+									Weight: 100,
+									PodAffinityTerm: corev1.PodAffinityTerm{
+										LabelSelector: &metav1.LabelSelector{
+											MatchLabels: map[string]string{
+												"app":       "cryostat",
+												"kind":      "cryostat",
+												"component": "cryostat",
+											},
+										},
+										TopologyKey: "kubernetes.io/hostname",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func NewVolumeSnapshot(ready bool) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": "cryostat",
+				},
+				"volumeSnapshotClassName": "csi-snapclass",
+			},
+			"status": map[string]interface{}{
+				"readyToUse":  ready,
+				"restoreSize": "1Gi",
+			},
+		},
+	}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName("test-backup")
+	snapshot.SetNamespace("default")
+	snapshot.SetLabels(map[string]string{
+		"app": "cryostat",
+	})
+	return snapshot
+}
+
+func NewFailedVolumeSnapshot() *unstructured.Unstructured {
+	snapshot := NewVolumeSnapshot(false)
+	err := unstructured.SetNestedField(snapshot.Object, "snapshot failed", "status", "error", "message")
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	return snapshot
+}
+
+func NewRestoreSourcePVC() *corev1.PersistentVolumeClaim {
+	apiGroup := "snapshot.storage.k8s.io"
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-restore-restore-source",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app": "cryostat",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VolumeSnapshot",
+				Name:     "test-backup",
+			},
 		},
 	}
 }
@@ -647,6 +1802,71 @@ func NewTestEndpointsNoJMXPort() *corev1.Endpoints {
 	return newTestEndpoints(target, ports)
 }
 
+func NewTestEndpointsWithAgentPort() *corev1.Endpoints {
+	target := &corev1.ObjectReference{
+		Kind:      "Pod",
+		Name:      "test-pod",
+		Namespace: "default",
+	}
+	ports := []corev1.EndpointPort{
+		{
+			Name: "jfr-jmx",
+			Port: 1234,
+		},
+		{
+			Name: "cryostat-agent",
+			Port: 9977,
+		},
+		{
+			Name: "custom-agent",
+			Port: 8181,
+		},
+	}
+	return newTestEndpoints(target, ports)
+}
+
+func NewTestEndpointsCustomJMXPortName() *corev1.Endpoints {
+	target := &corev1.ObjectReference{
+		Kind:      "Pod",
+		Name:      "test-pod",
+		Namespace: "default",
+	}
+	ports := []corev1.EndpointPort{
+		{
+			Name: "jfr-jmx",
+			Port: 1234,
+		},
+		{
+			Name: "custom-jmx",
+			Port: 4321,
+		},
+		{
+			Name: "other-port",
+			Port: 5678,
+		},
+	}
+	return newTestEndpoints(target, ports)
+}
+
+func NewTestEndpointsCustomJMXPortNumber() *corev1.Endpoints {
+	target := &corev1.ObjectReference{
+		Kind:      "Pod",
+		Name:      "test-pod",
+		Namespace: "default",
+	}
+	ports := []corev1.EndpointPort{
+		{
+			Name: "other-port",
+			Port: 9091,
+		},
+		{
+			Name: "another-port",
+			Port: 5678,
+		},
+	}
+	return newTestEndpoints(target, ports)
+}
+
 func newTestEndpoints(targetRef *corev1.ObjectReference, ports []corev1.EndpointPort) *corev1.Endpoints {
 	return &corev1.Endpoints{
 		ObjectMeta: metav1.ObjectMeta{
@@ -777,6 +1997,68 @@ func NewGrafanaService() *corev1.Service {
 	}
 }
 
+func NewReportsHorizontalPodAutoscaler(minReplicas int32, maxReplicas int32,
+	metrics ...autoscalingv2beta2.MetricSpec) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-reports",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":       "cryostat",
+				"component": "reports",
+			},
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "cryostat-reports",
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+func NewReportsCPUMetric(utilization int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
+
+func NewReportsPodDisruptionBudget(minAvailable *intstr.IntOrString,
+	maxUnavailable *intstr.IntOrString) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-reports",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":       "cryostat",
+				"component": "reports",
+			},
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       "cryostat",
+					"kind":      "cryostat",
+					"component": "reports",
+				},
+			},
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+		},
+	}
+}
+
 func NewReportsService() *corev1.Service {
 	c := true
 	return &corev1.Service{
@@ -878,6 +2160,33 @@ func NewTestService() *corev1.Service {
 	}
 }
 
+func NewTestServiceWithLabels() *corev1.Service {
+	svc := NewTestService()
+	svc.Labels = map[string]string{
+		"cryostat.io/discover": "true",
+	}
+	return svc
+}
+
+func NewTestServiceExcluded() *corev1.Service {
+	svc := NewTestService()
+	svc.Annotations = map[string]string{
+		operatorv1beta1.DiscoveryExcludeAnnotation: "true",
+	}
+	return svc
+}
+
+func NewTestServiceWithAgentPort(port string, scheme string) *corev1.Service {
+	svc := NewTestService()
+	svc.Annotations = map[string]string{
+		operatorv1beta1.AgentPortAnnotation: port,
+	}
+	if len(scheme) > 0 {
+		svc.Annotations[operatorv1beta1.AgentSchemeAnnotation] = scheme
+	}
+	return svc
+}
+
 func NewCryostatCert() *certv1.Certificate {
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1041,6 +2350,51 @@ func NewJMXAuthSecret() *corev1.Secret {
 	}
 }
 
+func NewTargetCASecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-jmx-ca",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: []byte(TargetCACert),
+		},
+	}
+}
+
+func NewTargetCASecretForCryostat(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-target-ca",
+			Namespace: "default",
+		},
+		Data: data,
+	}
+}
+
+func NewTargetClientCertSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-jmx-client-tls",
+			Namespace: "default",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(TargetClientCert),
+			corev1.TLSPrivateKeyKey: []byte(TargetClientKey),
+		},
+	}
+}
+
+const (
+	// TargetCACert is placeholder content for a target's CA certificate
+	TargetCACert = "target-ca-bytes"
+	// TargetClientCert is placeholder content for a client certificate
+	TargetClientCert = "client-cert-bytes"
+	// TargetClientKey is placeholder content for a client private key
+	TargetClientKey = "client-key-bytes"
+)
+
 func NewJMXAuthSecretForCryostat() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1056,6 +2410,7 @@ func NewJMXAuthSecretForCryostat() *corev1.Secret {
 
 func newPVC(spec *corev1.PersistentVolumeClaimSpec, labels map[string]string,
 	annotations map[string]string) *corev1.PersistentVolumeClaim {
+	labels["operator.cryostat.io/active-storage"] = "cryostat"
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        "cryostat",
@@ -1113,6 +2468,15 @@ func NewCustomPVCSomeDefault() *corev1.PersistentVolumeClaim {
 	}, nil)
 }
 
+func NewBoundPVC(capacity string) *corev1.PersistentVolumeClaim {
+	pvc := NewDefaultPVC()
+	pvc.Status.Phase = corev1.ClaimBound
+	pvc.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse(capacity),
+	}
+	return pvc
+}
+
 func NewDefaultPVCWithLabel() *corev1.PersistentVolumeClaim {
 	return newPVC(&corev1.PersistentVolumeClaimSpec{
 		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
@@ -1127,6 +2491,24 @@ func NewDefaultPVCWithLabel() *corev1.PersistentVolumeClaim {
 	}, nil)
 }
 
+func NewStorageClass(name string, expandable bool) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Provisioner:          "example.com/storage",
+		AllowVolumeExpansion: &expandable,
+	}
+}
+
+func NewDefaultStorageClass(expandable bool) *storagev1.StorageClass {
+	sc := NewStorageClass("default-storage", expandable)
+	sc.Annotations = map[string]string{
+		"storageclass.kubernetes.io/is-default-class": "true",
+	}
+	return sc
+}
+
 func NewDefaultEmptyDir() *corev1.EmptyDirVolumeSource {
 	sizeLimit := resource.MustParse("0")
 	return &corev1.EmptyDirVolumeSource{
@@ -1778,6 +3160,15 @@ func newVolumes(minimal bool, tls bool, certProjections []corev1.VolumeProjectio
 				})
 		}
 	}
+	optional := true
+	projs = append(projs, corev1.VolumeProjection{
+		Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "cryostat-target-ca",
+			},
+			Optional: &optional,
+		},
+	})
 
 	volumes = append(volumes,
 		corev1.Volume{
@@ -1810,8 +3201,33 @@ func NewReportsVolumes(tls bool) []corev1.Volume {
 
 func NewPodSecurityContext() *corev1.PodSecurityContext {
 	fsGroup := int64(18500)
+	nonRoot := true
+	return &corev1.PodSecurityContext{
+		FSGroup:      &fsGroup,
+		RunAsNonRoot: &nonRoot,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+func NewReportsPodSecurityContext() *corev1.PodSecurityContext {
+	nonRoot := true
 	return &corev1.PodSecurityContext{
-		FSGroup: &fsGroup,
+		RunAsNonRoot: &nonRoot,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+func NewContainerSecurityContext() *corev1.SecurityContext {
+	privEscalation := false
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &privEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
 	}
 }
 
@@ -1868,6 +3284,204 @@ func NewNetworkConfiguration(svcName string, svcPort int32, tls bool) operatorv1
 	}
 }
 
+func NewGatewayConfiguration() *operatorv1beta1.GatewayConfiguration {
+	return &operatorv1beta1.GatewayConfiguration{
+		GatewayRef: operatorv1beta1.GatewayReference{
+			Name:      "cryostat-gateway",
+			Namespace: "gateways",
+		},
+		CoreRoute: &operatorv1beta1.HTTPRouteConfiguration{
+			Hostnames:   []string{"cryostat.example.com"},
+			Annotations: map[string]string{"my": "annotation"},
+			Labels:      map[string]string{"my": "label"},
+		},
+		GrafanaRoute: &operatorv1beta1.HTTPRouteConfiguration{
+			Hostnames:   []string{"cryostat-grafana.example.com"},
+			Annotations: map[string]string{"my": "annotation"},
+			Labels:      map[string]string{"my": "label"},
+		},
+	}
+}
+
+func NewGateway(tls bool) *unstructured.Unstructured {
+	listeners := []interface{}{
+		map[string]interface{}{
+			"name":     "http",
+			"protocol": "HTTP",
+			"port":     int64(8080),
+		},
+	}
+	if tls {
+		listeners = append(listeners, map[string]interface{}{
+			"name":     "https",
+			"protocol": "HTTPS",
+			"port":     int64(443),
+			"hostname": "*.example.com",
+		})
+	}
+	gateway := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"gatewayClassName": "example",
+				"listeners":        listeners,
+			},
+			"status": map[string]interface{}{
+				"addresses": []interface{}{
+					map[string]interface{}{
+						"type":  "IPAddress",
+						"value": "192.0.2.10",
+					},
+				},
+			},
+		},
+	}
+	gateway.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "Gateway",
+	})
+	gateway.SetName("cryostat-gateway")
+	gateway.SetNamespace("gateways")
+	return gateway
+}
+
+func NewCoreNetworkPolicy(isOpenShift bool, reports bool) *netv1.NetworkPolicy {
+	tcp := corev1.ProtocolTCP
+	udp := corev1.ProtocolUDP
+	newPort := func(protocol *corev1.Protocol, port intstr.IntOrString) netv1.NetworkPolicyPort {
+		return netv1.NetworkPolicyPort{Protocol: protocol, Port: &port}
+	}
+	ingressControllerPeer := netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"},
+		},
+	}
+	if isOpenShift {
+		ingressControllerPeer.NamespaceSelector.MatchLabels = map[string]string{
+			"network.openshift.io/policy-group": "ingress",
+		}
+	}
+	operatorPeer := netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": "default"},
+		},
+	}
+	allNamespacesPeer := netv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{}}
+
+	egress := []netv1.NetworkPolicyEgressRule{
+		{
+			Ports: []netv1.NetworkPolicyPort{
+				newPort(&tcp, intstr.FromString("jfr-jmx")),
+				newPort(&tcp, intstr.FromString("cryostat-agent")),
+				newPort(&tcp, intstr.FromInt(9091)),
+			},
+			To: []netv1.NetworkPolicyPeer{allNamespacesPeer},
+		},
+		{
+			Ports: []netv1.NetworkPolicyPort{
+				newPort(&udp, intstr.FromInt(53)),
+				newPort(&tcp, intstr.FromInt(53)),
+			},
+		},
+		{
+			Ports: []netv1.NetworkPolicyPort{
+				newPort(&tcp, intstr.FromInt(443)),
+				newPort(&tcp, intstr.FromInt(6443)),
+			},
+		},
+	}
+	if reports {
+		egress = append(egress, netv1.NetworkPolicyEgressRule{
+			Ports: []netv1.NetworkPolicyPort{newPort(&tcp, intstr.FromInt(10000))},
+			To: []netv1.NetworkPolicyPeer{
+				{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app":       "cryostat",
+							"component": "reports",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":       "cryostat",
+				"component": "cryostat",
+			},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       "cryostat",
+					"component": "cryostat",
+				},
+			},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{
+					Ports: []netv1.NetworkPolicyPort{newPort(&tcp, intstr.FromInt(8181))},
+					From:  []netv1.NetworkPolicyPeer{ingressControllerPeer, operatorPeer, allNamespacesPeer},
+				},
+				{
+					Ports: []netv1.NetworkPolicyPort{newPort(&tcp, intstr.FromInt(3000))},
+					From:  []netv1.NetworkPolicyPeer{ingressControllerPeer},
+				},
+			},
+			Egress: egress,
+			PolicyTypes: []netv1.PolicyType{
+				netv1.PolicyTypeIngress,
+				netv1.PolicyTypeEgress,
+			},
+		},
+	}
+}
+
+func NewReportsNetworkPolicy() *netv1.NetworkPolicy {
+	tcp := corev1.ProtocolTCP
+	port := intstr.FromInt(10000)
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-reports",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":       "cryostat",
+				"component": "reports",
+			},
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       "cryostat",
+					"component": "reports",
+				},
+			},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{
+					Ports: []netv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+					From: []netv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       "cryostat",
+									"component": "cryostat",
+								},
+							},
+						},
+					},
+				},
+			},
+			PolicyTypes: []netv1.PolicyType{
+				netv1.PolicyTypeIngress,
+			},
+		},
+	}
+}
+
 func NewServiceAccount(isOpenShift bool) *corev1.ServiceAccount {
 	var annotations map[string]string
 	if isOpenShift {
@@ -2020,6 +3634,18 @@ func NewOtherTemplateConfigMap() *corev1.ConfigMap {
 	}
 }
 
+func NewTrustedCertSecret(name string, key string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			key: []byte(name + "-cert-bytes"),
+		},
+	}
+}
+
 func NewNamespace() *corev1.Namespace {
 	return &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2028,6 +3654,14 @@ func NewNamespace() *corev1.Namespace {
 	}
 }
 
+func NewNamespaceWithLabels() *corev1.Namespace {
+	ns := NewNamespace()
+	ns.Labels = map[string]string{
+		"environment": "dev",
+	}
+	return ns
+}
+
 func NewNamespaceWithSCCSupGroups() *corev1.Namespace {
 	ns := NewNamespace()
 	ns.Annotations = map[string]string{